@@ -14,6 +14,15 @@ type Node interface {
 
 type Program struct {
 	Body BlockStatement
+
+	// Modules imported by the program, ordered so every module comes after the modules it imports
+	Modules []Module
+}
+
+// A file imported by the program or by another module. Path is the path written in the import statements
+type Module struct {
+	Path string
+	Body BlockStatement
 }
 
 func (p Program) node() {}
@@ -147,6 +156,7 @@ func (s ExecuteFunctionExpression) GetChildNodes() []Node {
 }
 
 type DefineFunctionExpression struct {
+	TypeParameters         []string
 	Arguments              []Variable
 	ReturnTypes            []types.Type
 	FunctionBody           BlockStatement
@@ -163,11 +173,15 @@ func (s DefineFunctionExpression) GetChildNodes() []Node {
 	return []Node{s.FunctionBody}
 }
 
+// The lines of the if keyword and the two branches are used in the errors of the validator
 type IfExpression struct {
 	Condition       Node
 	TrueExpression  Node
 	FalseExpression Node
 	ReturnType      []types.Type
+	Line            int
+	TrueLine        int
+	FalseLine       int
 }
 
 func (p IfExpression) node()           {}
@@ -205,6 +219,32 @@ func (s FloatExpression) GetChildNodes() []Node {
 	return []Node{}
 }
 
+type LongExpression struct {
+	Value int64
+}
+
+func (p LongExpression) node()           {}
+func (s LongExpression) expressionNode() {}
+func (s LongExpression) GetExpressionReturnType() []types.Type {
+	return []types.Type{types.StandardType{Name: token.LONG}}
+}
+func (s LongExpression) GetChildNodes() []Node {
+	return []Node{}
+}
+
+type DoubleExpression struct {
+	Value float64
+}
+
+func (p DoubleExpression) node()           {}
+func (s DoubleExpression) expressionNode() {}
+func (s DoubleExpression) GetExpressionReturnType() []types.Type {
+	return []types.Type{types.StandardType{Name: token.DOUBLE}}
+}
+func (s DoubleExpression) GetChildNodes() []Node {
+	return []Node{}
+}
+
 type StringExpression struct {
 	Value string
 }
@@ -231,6 +271,7 @@ func (s BoolExpression) GetChildNodes() []Node {
 	return []Node{}
 }
 
+// Type is the type of the operands. Comparisons give a bool
 type OperatorExpression struct {
 	Operator  string
 	Type      types.Type
@@ -241,12 +282,32 @@ type OperatorExpression struct {
 func (p OperatorExpression) node()           {}
 func (s OperatorExpression) expressionNode() {}
 func (s OperatorExpression) GetExpressionReturnType() []types.Type {
+	switch s.Operator {
+	case token.EQUAL, token.NOT_EQUAL, token.LESS_THEN, token.GREATER_THEN, token.EQUAL_OR_LESS_THEN, token.EQUAL_OR_GREATER_THEN:
+		return []types.Type{types.StandardType{Name: token.BOOL}}
+	}
+
 	return []types.Type{s.Type}
 }
 func (s OperatorExpression) GetChildNodes() []Node {
 	return []Node{s.LeftSide, s.RightSide}
 }
 
+type UnaryOperatorExpression struct {
+	Operator   string
+	Type       types.Type
+	Expression Node
+}
+
+func (p UnaryOperatorExpression) node()           {}
+func (s UnaryOperatorExpression) expressionNode() {}
+func (s UnaryOperatorExpression) GetExpressionReturnType() []types.Type {
+	return []types.Type{s.Type}
+}
+func (s UnaryOperatorExpression) GetChildNodes() []Node {
+	return []Node{s.Expression}
+}
+
 type ArrayExpression struct {
 	Type                types.Type
 	ElementsExpressions []Node
@@ -260,3 +321,262 @@ func (s ArrayExpression) GetExpressionReturnType() []types.Type {
 func (s ArrayExpression) GetChildNodes() []Node {
 	return s.ElementsExpressions
 }
+
+// Defines a record type, a union type, a type alias or a newtype. Type aliases are other names for their type, while
+// newtypes are new types holding a value of their type
+type TypeDefinitionStatement struct {
+	Name           string
+	TypeParameters []string
+	Type           types.Type
+	IsAlias        bool
+	IsNewType      bool
+}
+
+func (s TypeDefinitionStatement) node()          {}
+func (s TypeDefinitionStatement) statementNode() {}
+func (s TypeDefinitionStatement) GetExpressionReturnType() []types.Type {
+	return []types.Type{types.StandardType{}}
+}
+func (s TypeDefinitionStatement) GetChildNodes() []Node {
+	return []Node{}
+}
+
+type RecordExpression struct {
+	Type        types.Type
+	FieldNames  []string
+	FieldValues []Node
+}
+
+func (p RecordExpression) node()           {}
+func (s RecordExpression) expressionNode() {}
+func (s RecordExpression) GetExpressionReturnType() []types.Type {
+	return []types.Type{s.Type}
+}
+func (s RecordExpression) GetChildNodes() []Node {
+	return s.FieldValues
+}
+
+type FieldAccessExpression struct {
+	Type   types.Type
+	Record Node
+	Field  string
+}
+
+func (p FieldAccessExpression) node()           {}
+func (s FieldAccessExpression) expressionNode() {}
+func (s FieldAccessExpression) GetExpressionReturnType() []types.Type {
+	return []types.Type{s.Type}
+}
+func (s FieldAccessExpression) GetChildNodes() []Node {
+	return []Node{s.Record}
+}
+
+type RecordUpdateExpression struct {
+	Type        types.Type
+	Record      Node
+	FieldNames  []string
+	FieldValues []Node
+}
+
+func (p RecordUpdateExpression) node()           {}
+func (s RecordUpdateExpression) expressionNode() {}
+func (s RecordUpdateExpression) GetExpressionReturnType() []types.Type {
+	return []types.Type{s.Type}
+}
+func (s RecordUpdateExpression) GetChildNodes() []Node {
+	return append([]Node{s.Record}, s.FieldValues...)
+}
+
+// Creates a value of a union type. Only created by the validator, as the body of the functions constructing the variants
+type VariantExpression struct {
+	Type      types.UnionType
+	Variant   string
+	Arguments []Node
+}
+
+func (p VariantExpression) node()           {}
+func (s VariantExpression) expressionNode() {}
+func (s VariantExpression) GetExpressionReturnType() []types.Type {
+	return []types.Type{s.Type}
+}
+func (s VariantExpression) GetChildNodes() []Node {
+	return s.Arguments
+}
+
+type MatchExpression struct {
+	Type        types.Type
+	Value       Node
+	Arms        []MatchArm
+	ReturnTypes []types.Type
+}
+
+// An arm without variant matches all variants not matched by the arms before it
+type MatchArm struct {
+	Variant    string
+	Bindings   []Variable
+	Expression Node
+}
+
+func (p MatchExpression) node()           {}
+func (s MatchExpression) expressionNode() {}
+func (s MatchExpression) GetExpressionReturnType() []types.Type {
+	return s.ReturnTypes
+}
+func (s MatchExpression) GetChildNodes() []Node {
+	childNodes := []Node{s.Value}
+	for i := 0; i < len(s.Arms); i++ {
+		childNodes = append(childNodes, s.Arms[i].Expression)
+	}
+
+	return childNodes
+}
+
+// Binds values to names that can only be used in the expression after in: let x = a * 2, y = x + 1 in x * y
+// The values are bound in order, so a value can use the names bound before it
+type LetExpression struct {
+	Bindings    []AssignmentStatement
+	Expression  Node
+	ReturnTypes []types.Type
+}
+
+func (p LetExpression) node()           {}
+func (s LetExpression) expressionNode() {}
+func (s LetExpression) GetExpressionReturnType() []types.Type {
+	return s.ReturnTypes
+}
+func (s LetExpression) GetChildNodes() []Node {
+	childNodes := make([]Node, 0)
+	for i := 0; i < len(s.Bindings); i++ {
+		childNodes = append(childNodes, s.Bindings[i].Value)
+	}
+
+	return append(childNodes, s.Expression)
+}
+
+// Creates a tuple from the values given by the elements. An element can give more than one value when the validator
+// turns the values returned by a function into a tuple
+type TupleExpression struct {
+	Type     types.TupleType
+	Elements []Node
+}
+
+func (p TupleExpression) node()           {}
+func (s TupleExpression) expressionNode() {}
+func (s TupleExpression) GetExpressionReturnType() []types.Type {
+	return []types.Type{s.Type}
+}
+func (s TupleExpression) GetChildNodes() []Node {
+	return s.Elements
+}
+
+// Gives the values held by a tuple as separate values. Only created by the validator, when a tuple is assigned to
+// more than one variable
+type TupleUnpackExpression struct {
+	Type  types.TupleType
+	Tuple Node
+}
+
+func (p TupleUnpackExpression) node()           {}
+func (s TupleUnpackExpression) expressionNode() {}
+func (s TupleUnpackExpression) GetExpressionReturnType() []types.Type {
+	return s.Type.ElementTypes
+}
+func (s TupleUnpackExpression) GetChildNodes() []Node {
+	return []Node{s.Tuple}
+}
+
+// Gives the values bound to the variables in the patterns, with one pattern for each value given by Value.
+// Variables in the patterns named _ are not bound to any value
+type DestructureExpression struct {
+	Patterns    []Node
+	Value       Node
+	ReturnTypes []types.Type
+}
+
+func (p DestructureExpression) node()           {}
+func (s DestructureExpression) expressionNode() {}
+func (s DestructureExpression) GetExpressionReturnType() []types.Type {
+	return s.ReturnTypes
+}
+func (s DestructureExpression) GetChildNodes() []Node {
+	return []Node{s.Value}
+}
+
+// Pattern matching the elements of a tuple: (a, b)
+type TuplePattern struct {
+	Type     types.Type
+	Elements []Node
+}
+
+func (p TuplePattern) node() {}
+func (s TuplePattern) GetExpressionReturnType() []types.Type {
+	return []types.Type{s.Type}
+}
+func (s TuplePattern) GetChildNodes() []Node {
+	return s.Elements
+}
+
+// Pattern matching the first elements of an array, and optionally the array of the elements after them: [a, b, ...rest]
+type ArrayPattern struct {
+	Type     types.Type
+	Elements []Node
+	Rest     Node
+}
+
+func (p ArrayPattern) node() {}
+func (s ArrayPattern) GetExpressionReturnType() []types.Type {
+	return []types.Type{s.Type}
+}
+func (s ArrayPattern) GetChildNodes() []Node {
+	if s.Rest == nil {
+		return s.Elements
+	}
+
+	return append(append([]Node{}, s.Elements...), s.Rest)
+}
+
+// Pattern binding fields of a record to variables with the names of the fields: Point { x, y } or { x, y }
+type RecordPattern struct {
+	Type   types.Type
+	Fields []Variable
+}
+
+func (p RecordPattern) node() {}
+func (s RecordPattern) GetExpressionReturnType() []types.Type {
+	return []types.Type{s.Type}
+}
+func (s RecordPattern) GetChildNodes() []Node {
+	return []Node{}
+}
+
+// Gives the value as the type Type without changing it. Used to create values of newtypes and to get the values they hold
+type NewTypeExpression struct {
+	Type  types.Type
+	Value Node
+}
+
+func (p NewTypeExpression) node()           {}
+func (s NewTypeExpression) expressionNode() {}
+func (s NewTypeExpression) GetExpressionReturnType() []types.Type {
+	return []types.Type{s.Type}
+}
+func (s NewTypeExpression) GetChildNodes() []Node {
+	return []Node{s.Value}
+}
+
+// Imports the module with the path. The globals and types of the module are used by writing Name, a dot and their
+// name, like vector.dot, where Name is the last part of the path
+type ImportStatement struct {
+	Path string
+	Name string
+	Line int
+}
+
+func (s ImportStatement) node()          {}
+func (s ImportStatement) statementNode() {}
+func (s ImportStatement) GetExpressionReturnType() []types.Type {
+	return []types.Type{types.StandardType{}}
+}
+func (s ImportStatement) GetChildNodes() []Node {
+	return []Node{}
+}
@@ -0,0 +1,385 @@
+(module
+    (memory (export "memory") 1)
+    (type $0 (func (param i32) (param i32) (result i32)))
+    (type $1 (func (param i32) (result i32)))
+    (type $2 (func (param i64) (param i64) (result i64)))
+    (type $3 (func (param i64) (result i64)))
+    (type $4 (func (param f32) (param f32) (result f32)))
+    (type $5 (func (param f32) (result f32)))
+    (type $6 (func (param f64) (param f64) (result f64)))
+    (type $7 (func (param f64) (result f64)))
+
+    (func $i32min (type $0) (param $a i32) (param $b i32) (result i32)
+        (select (local.get $a) (local.get $b) (i32.lt_s (local.get $a) (local.get $b)))
+    )
+
+    (func $i32max (type $0) (param $a i32) (param $b i32) (result i32)
+        (select (local.get $a) (local.get $b) (i32.gt_s (local.get $a) (local.get $b)))
+    )
+
+    (func $i32abs (type $1) (param $a i32) (result i32)
+        (select (i32.sub (i32.const 0) (local.get $a)) (local.get $a) (i32.lt_s (local.get $a) (i32.const 0)))
+    )
+
+    (func $i64min (type $2) (param $a i64) (param $b i64) (result i64)
+        (select (local.get $a) (local.get $b) (i64.lt_s (local.get $a) (local.get $b)))
+    )
+
+    (func $i64max (type $2) (param $a i64) (param $b i64) (result i64)
+        (select (local.get $a) (local.get $b) (i64.gt_s (local.get $a) (local.get $b)))
+    )
+
+    (func $i64abs (type $3) (param $a i64) (result i64)
+        (select (i64.sub (i64.const 0) (local.get $a)) (local.get $a) (i64.lt_s (local.get $a) (i64.const 0)))
+    )
+
+    (func $f32min (type $4) (param $a f32) (param $b f32) (result f32)
+        (f32.min (local.get $a) (local.get $b))
+    )
+
+    (func $f32max (type $4) (param $a f32) (param $b f32) (result f32)
+        (f32.max (local.get $a) (local.get $b))
+    )
+
+    (func $f32abs (type $5) (param $a f32) (result f32)
+        (f32.abs (local.get $a))
+    )
+
+    (func $f64min (type $6) (param $a f64) (param $b f64) (result f64)
+        (f64.min (local.get $a) (local.get $b))
+    )
+
+    (func $f64max (type $6) (param $a f64) (param $b f64) (result f64)
+        (f64.max (local.get $a) (local.get $b))
+    )
+
+    (func $f64abs (type $7) (param $a f64) (result f64)
+        (f64.abs (local.get $a))
+    )
+
+    ;; e^x = e^r * 2^k where k = nearest(x / ln2) and r = x - k * ln2. e^r is calculated with the taylor series
+    (func $f64exp (type $7) (param $x f64) (result f64)
+        (local $k f64)
+        (local $r f64)
+        (local $p f64)
+        (local $halfK i64)
+
+        (if (f64.ne (local.get $x) (local.get $x)) (then (return (local.get $x)))) ;; NaN
+        (if (f64.gt (local.get $x) (f64.const 709.782712893384)) (then (return (f64.const inf))))
+        (if (f64.lt (local.get $x) (f64.const -745.1332191019411)) (then (return (f64.const 0))))
+
+        (local.set $k (f64.nearest (f64.mul (local.get $x) (f64.const 1.4426950408889634))))
+        (local.set $r (f64.sub (f64.sub (local.get $x) (f64.mul (local.get $k) (f64.const 0.6931471803691238))) (f64.mul (local.get $k) (f64.const 1.9082149292705877e-10)))) ;; ln2 split in two parts to keep precision
+
+        (local.set $p (f64.add (f64.const 1) (f64.div (local.get $r) (f64.const 13))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 12))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 11))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 10))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 9))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 8))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 7))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 6))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 5))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 4))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 3))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 2))))
+        (local.set $p (f64.add (f64.const 1) (f64.mul (local.get $r) (local.get $p))))
+
+        ;; Multiplying with 2^k in two steps so the exponent of each factor is in range
+        (local.set $halfK (i64.trunc_f64_s (f64.trunc (f64.mul (local.get $k) (f64.const 0.5)))))
+        (local.set $p (f64.mul (local.get $p) (f64.reinterpret_i64 (i64.shl (i64.add (local.get $halfK) (i64.const 1023)) (i64.const 52)))))
+        (f64.mul (local.get $p) (f64.reinterpret_i64 (i64.shl (i64.add (i64.sub (i64.trunc_f64_s (local.get $k)) (local.get $halfK)) (i64.const 1023)) (i64.const 52))))
+    )
+
+    ;; ln(x) = e * ln2 + ln(1 + u) where x = (1 + u) * 2^e. ln(1 + u) is calculated with the series 2 * (s + s^3/3 + s^5/5 ...) where s = u / (2 + u)
+    (func $f64log (type $7) (param $x f64) (result f64)
+        (local $bits i64)
+        (local $e i64)
+        (local $m f64)
+        (local $u f64)
+        (local $s f64)
+        (local $z f64)
+        (local $hfsq f64)
+        (local $q f64)
+
+        (if (f64.ne (local.get $x) (local.get $x)) (then (return (local.get $x)))) ;; NaN
+        (if (f64.lt (local.get $x) (f64.const 0)) (then (return (f64.const nan))))
+        (if (f64.eq (local.get $x) (f64.const 0)) (then (return (f64.const -inf))))
+        (if (f64.eq (local.get $x) (f64.const inf)) (then (return (f64.const inf))))
+
+        (local.set $bits (i64.reinterpret_f64 (local.get $x)))
+        (local.set $e (i64.and (i64.shr_u (local.get $bits) (i64.const 52)) (i64.const 0x7ff)))
+        (if (i64.eqz (local.get $e)) ;; Subnormal numbers are scaled by 2^54 to get an exponent
+            (then
+                (local.set $bits (i64.reinterpret_f64 (f64.mul (local.get $x) (f64.const 18014398509481984))))
+                (local.set $e (i64.sub (i64.and (i64.shr_u (local.get $bits) (i64.const 52)) (i64.const 0x7ff)) (i64.const 54)))
+            )
+        )
+
+        (local.set $e (i64.sub (local.get $e) (i64.const 1023)))
+        (local.set $m (f64.reinterpret_i64 (i64.or (i64.and (local.get $bits) (i64.const 0x000fffffffffffff)) (i64.const 0x3ff0000000000000))))
+        (if (f64.gt (local.get $m) (f64.const 1.4142135623730951))
+            (then
+                (local.set $m (f64.mul (local.get $m) (f64.const 0.5)))
+                (local.set $e (i64.add (local.get $e) (i64.const 1)))
+            )
+        )
+
+        (local.set $u (f64.sub (local.get $m) (f64.const 1)))
+        (local.set $s (f64.div (local.get $u) (f64.add (local.get $u) (f64.const 2))))
+        (local.set $z (f64.mul (local.get $s) (local.get $s)))
+        (local.set $hfsq (f64.mul (f64.const 0.5) (f64.mul (local.get $u) (local.get $u))))
+
+        (local.set $q (f64.div (f64.const 2) (f64.const 21)))
+        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 19)) (f64.mul (local.get $z) (local.get $q))))
+        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 17)) (f64.mul (local.get $z) (local.get $q))))
+        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 15)) (f64.mul (local.get $z) (local.get $q))))
+        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 13)) (f64.mul (local.get $z) (local.get $q))))
+        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 11)) (f64.mul (local.get $z) (local.get $q))))
+        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 9)) (f64.mul (local.get $z) (local.get $q))))
+        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 7)) (f64.mul (local.get $z) (local.get $q))))
+        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 5)) (f64.mul (local.get $z) (local.get $q))))
+        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 3)) (f64.mul (local.get $z) (local.get $q))))
+
+        ;; e * ln2 - ((hfsq - s * (hfsq + z * q)) - u) with ln2 split in two parts to keep precision
+        (f64.sub
+            (f64.mul (f64.convert_i64_s (local.get $e)) (f64.const 0.6931471803691238))
+            (f64.sub (f64.sub (local.get $hfsq) (f64.add (f64.mul (local.get $s) (f64.add (local.get $hfsq) (f64.mul (local.get $z) (local.get $q)))) (f64.mul (f64.convert_i64_s (local.get $e)) (f64.const 1.9082149292705877e-10)))) (local.get $u))
+        )
+    )
+
+    ;; Integer exponents are calculated exactly with repeated squaring, other exponents with e^(y * ln|x|).
+    ;; Functions can not call each other so the code of f64log and f64exp is repeated here.
+    (func $f64pow (type $6) (param $x f64) (param $y f64) (result f64)
+        (local $negate i32)
+        (local $ax f64)
+        (local $n i64)
+        (local $base f64)
+        (local $result f64)
+        (local $bits i64)
+        (local $e i64)
+        (local $m f64)
+        (local $u f64)
+        (local $s f64)
+        (local $z f64)
+        (local $hfsq f64)
+        (local $q f64)
+        (local $k f64)
+        (local $r f64)
+        (local $p f64)
+        (local $halfK i64)
+
+        (if (f64.eq (local.get $y) (f64.const 0)) (then (return (f64.const 1))))
+        (if (f64.ne (local.get $x) (local.get $x)) (then (return (local.get $x))))
+        (if (f64.ne (local.get $y) (local.get $y)) (then (return (local.get $y))))
+
+        (local.set $ax (f64.abs (local.get $x)))
+        (if (f64.lt (local.get $x) (f64.const 0))
+            (then
+                (if (f64.ne (f64.trunc (local.get $y)) (local.get $y)) (then (return (f64.const nan))))
+                (local.set $negate (f64.ne (f64.trunc (f64.mul (local.get $y) (f64.const 0.5))) (f64.mul (local.get $y) (f64.const 0.5))))
+            )
+        )
+
+        (if (f64.eq (local.get $ax) (f64.const 1))
+            (then (return (select (f64.const -1) (f64.const 1) (local.get $negate))))
+        )
+
+        ;; Repeated squaring
+        (if (i32.and (f64.eq (f64.trunc (local.get $y)) (local.get $y)) (f64.lt (f64.abs (local.get $y)) (f64.const 2147483648)))
+            (then
+                (local.set $n (i64.trunc_f64_s (f64.abs (local.get $y))))
+                (local.set $base (local.get $ax))
+                (local.set $result (f64.const 1))
+
+                (block $done
+                    (loop $square
+                        (br_if $done (i64.eqz (local.get $n)))
+                        (if (i32.wrap_i64 (i64.and (local.get $n) (i64.const 1)))
+                            (then (local.set $result (f64.mul (local.get $result) (local.get $base))))
+                        )
+                        (local.set $base (f64.mul (local.get $base) (local.get $base)))
+                        (local.set $n (i64.shr_u (local.get $n) (i64.const 1)))
+                        (br $square)
+                    )
+                )
+
+                (if (f64.lt (local.get $y) (f64.const 0))
+                    (then (local.set $result (f64.div (f64.const 1) (local.get $result))))
+                )
+
+                (return (select (f64.neg (local.get $result)) (local.get $result) (local.get $negate)))
+            )
+        )
+
+        ;; ln|x| (same as f64log)
+        (if (f64.eq (local.get $ax) (f64.const 0))
+            (then (local.set $r (f64.const -inf)))
+            (else
+                (if (f64.eq (local.get $ax) (f64.const inf))
+                    (then (local.set $r (f64.const inf)))
+                    (else
+                        (local.set $bits (i64.reinterpret_f64 (local.get $ax)))
+                        (local.set $e (i64.and (i64.shr_u (local.get $bits) (i64.const 52)) (i64.const 0x7ff)))
+                        (if (i64.eqz (local.get $e))
+                            (then
+                                (local.set $bits (i64.reinterpret_f64 (f64.mul (local.get $ax) (f64.const 18014398509481984))))
+                                (local.set $e (i64.sub (i64.and (i64.shr_u (local.get $bits) (i64.const 52)) (i64.const 0x7ff)) (i64.const 54)))
+                            )
+                        )
+
+                        (local.set $e (i64.sub (local.get $e) (i64.const 1023)))
+                        (local.set $m (f64.reinterpret_i64 (i64.or (i64.and (local.get $bits) (i64.const 0x000fffffffffffff)) (i64.const 0x3ff0000000000000))))
+                        (if (f64.gt (local.get $m) (f64.const 1.4142135623730951))
+                            (then
+                                (local.set $m (f64.mul (local.get $m) (f64.const 0.5)))
+                                (local.set $e (i64.add (local.get $e) (i64.const 1)))
+                            )
+                        )
+
+                        (local.set $u (f64.sub (local.get $m) (f64.const 1)))
+                        (local.set $s (f64.div (local.get $u) (f64.add (local.get $u) (f64.const 2))))
+                        (local.set $z (f64.mul (local.get $s) (local.get $s)))
+                        (local.set $hfsq (f64.mul (f64.const 0.5) (f64.mul (local.get $u) (local.get $u))))
+
+                        (local.set $q (f64.div (f64.const 2) (f64.const 21)))
+                        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 19)) (f64.mul (local.get $z) (local.get $q))))
+                        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 17)) (f64.mul (local.get $z) (local.get $q))))
+                        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 15)) (f64.mul (local.get $z) (local.get $q))))
+                        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 13)) (f64.mul (local.get $z) (local.get $q))))
+                        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 11)) (f64.mul (local.get $z) (local.get $q))))
+                        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 9)) (f64.mul (local.get $z) (local.get $q))))
+                        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 7)) (f64.mul (local.get $z) (local.get $q))))
+                        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 5)) (f64.mul (local.get $z) (local.get $q))))
+                        (local.set $q (f64.add (f64.div (f64.const 2) (f64.const 3)) (f64.mul (local.get $z) (local.get $q))))
+
+                        ;; e * ln2 - ((hfsq - s * (hfsq + z * q)) - u) with ln2 split in two parts to keep precision
+                        (local.set $r (f64.sub
+                            (f64.mul (f64.convert_i64_s (local.get $e)) (f64.const 0.6931471803691238))
+                            (f64.sub (f64.sub (local.get $hfsq) (f64.add (f64.mul (local.get $s) (f64.add (local.get $hfsq) (f64.mul (local.get $z) (local.get $q)))) (f64.mul (f64.convert_i64_s (local.get $e)) (f64.const 1.9082149292705877e-10)))) (local.get $u))
+                        ))
+                    )
+                )
+            )
+        )
+
+        ;; e^(y * ln|x|) (same as f64exp)
+        (local.set $x (f64.mul (local.get $y) (local.get $r)))
+        (if (f64.gt (local.get $x) (f64.const 709.782712893384))
+            (then (return (select (f64.const -inf) (f64.const inf) (local.get $negate))))
+        )
+        (if (f64.lt (local.get $x) (f64.const -745.1332191019411))
+            (then (return (select (f64.const -0) (f64.const 0) (local.get $negate))))
+        )
+
+        (local.set $k (f64.nearest (f64.mul (local.get $x) (f64.const 1.4426950408889634))))
+        (local.set $r (f64.sub (f64.sub (local.get $x) (f64.mul (local.get $k) (f64.const 0.6931471803691238))) (f64.mul (local.get $k) (f64.const 1.9082149292705877e-10))))
+
+        (local.set $p (f64.add (f64.const 1) (f64.div (local.get $r) (f64.const 13))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 12))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 11))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 10))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 9))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 8))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 7))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 6))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 5))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 4))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 3))))
+        (local.set $p (f64.add (f64.const 1) (f64.div (f64.mul (local.get $r) (local.get $p)) (f64.const 2))))
+        (local.set $p (f64.add (f64.const 1) (f64.mul (local.get $r) (local.get $p))))
+
+        (local.set $halfK (i64.trunc_f64_s (f64.trunc (f64.mul (local.get $k) (f64.const 0.5)))))
+        (local.set $p (f64.mul (local.get $p) (f64.reinterpret_i64 (i64.shl (i64.add (local.get $halfK) (i64.const 1023)) (i64.const 52)))))
+        (local.set $p (f64.mul (local.get $p) (f64.reinterpret_i64 (i64.shl (i64.add (i64.sub (i64.trunc_f64_s (local.get $k)) (local.get $halfK)) (i64.const 1023)) (i64.const 52)))))
+
+        (select (f64.neg (local.get $p)) (local.get $p) (local.get $negate))
+    )
+
+    ;; x is reduced to r = x - k * pi/2 where |r| <= pi/4. sin(r) and cos(r) are calculated with the taylor series and the quadrant k mod 4 decides which one to use
+    (func $f64sin (type $7) (param $x f64) (result f64)
+        (local $k f64)
+        (local $r f64)
+        (local $z f64)
+        (local $sin f64)
+        (local $cos f64)
+        (local $quadrant i32)
+
+        (if (f64.ne (f64.sub (local.get $x) (local.get $x)) (f64.const 0)) (then (return (f64.const nan)))) ;; NaN or infinity
+
+        (local.set $k (f64.nearest (f64.mul (local.get $x) (f64.const 0.6366197723675814))))
+        (local.set $r (f64.sub (f64.sub (local.get $x) (f64.mul (local.get $k) (f64.const 1.5707963267341256))) (f64.mul (local.get $k) (f64.const 6.077100506506192e-11)))) ;; pi/2 split in two parts to keep precision
+        (local.set $z (f64.mul (local.get $r) (local.get $r)))
+        (local.set $quadrant (i32.wrap_i64 (i64.and (i64.trunc_f64_s (local.get $k)) (i64.const 3))))
+
+        (local.set $sin (f64.mul (local.get $r)
+            (f64.add (f64.const 1) (f64.mul (local.get $z)
+            (f64.add (f64.const -0.16666666666666666) (f64.mul (local.get $z)
+            (f64.add (f64.const 0.008333333333333333) (f64.mul (local.get $z)
+            (f64.add (f64.const -1.984126984126984e-4) (f64.mul (local.get $z)
+            (f64.add (f64.const 2.7557319223985893e-6) (f64.mul (local.get $z)
+            (f64.add (f64.const -2.505210838544172e-8) (f64.mul (local.get $z)
+            (f64.add (f64.const 1.6059043836821613e-10) (f64.mul (local.get $z)
+            (f64.add (f64.const -7.647163731819816e-13) (f64.mul (local.get $z)
+            (f64.const 2.8114572543455206e-15)))))))))))))))))))
+
+        (local.set $cos
+            (f64.add (f64.const 1) (f64.mul (local.get $z)
+            (f64.add (f64.const -0.5) (f64.mul (local.get $z)
+            (f64.add (f64.const 0.041666666666666664) (f64.mul (local.get $z)
+            (f64.add (f64.const -0.001388888888888889) (f64.mul (local.get $z)
+            (f64.add (f64.const 2.48015873015873e-5) (f64.mul (local.get $z)
+            (f64.add (f64.const -2.755731922398589e-7) (f64.mul (local.get $z)
+            (f64.add (f64.const 2.08767569878681e-9) (f64.mul (local.get $z)
+            (f64.add (f64.const -1.1470745597729725e-11) (f64.mul (local.get $z)
+            (f64.const 4.779477332387385e-14))))))))))))))))))
+
+        (if (i32.eq (local.get $quadrant) (i32.const 0)) (then (return (local.get $sin))))
+        (if (i32.eq (local.get $quadrant) (i32.const 1)) (then (return (local.get $cos))))
+        (if (i32.eq (local.get $quadrant) (i32.const 2)) (then (return (f64.neg (local.get $sin)))))
+        (f64.neg (local.get $cos))
+    )
+
+    (func $f64cos (type $7) (param $x f64) (result f64)
+        (local $k f64)
+        (local $r f64)
+        (local $z f64)
+        (local $sin f64)
+        (local $cos f64)
+        (local $quadrant i32)
+
+        (if (f64.ne (f64.sub (local.get $x) (local.get $x)) (f64.const 0)) (then (return (f64.const nan)))) ;; NaN or infinity
+
+        (local.set $k (f64.nearest (f64.mul (local.get $x) (f64.const 0.6366197723675814))))
+        (local.set $r (f64.sub (f64.sub (local.get $x) (f64.mul (local.get $k) (f64.const 1.5707963267341256))) (f64.mul (local.get $k) (f64.const 6.077100506506192e-11))))
+        (local.set $z (f64.mul (local.get $r) (local.get $r)))
+        (local.set $quadrant (i32.wrap_i64 (i64.and (i64.trunc_f64_s (local.get $k)) (i64.const 3))))
+
+        (local.set $sin (f64.mul (local.get $r)
+            (f64.add (f64.const 1) (f64.mul (local.get $z)
+            (f64.add (f64.const -0.16666666666666666) (f64.mul (local.get $z)
+            (f64.add (f64.const 0.008333333333333333) (f64.mul (local.get $z)
+            (f64.add (f64.const -1.984126984126984e-4) (f64.mul (local.get $z)
+            (f64.add (f64.const 2.7557319223985893e-6) (f64.mul (local.get $z)
+            (f64.add (f64.const -2.505210838544172e-8) (f64.mul (local.get $z)
+            (f64.add (f64.const 1.6059043836821613e-10) (f64.mul (local.get $z)
+            (f64.add (f64.const -7.647163731819816e-13) (f64.mul (local.get $z)
+            (f64.const 2.8114572543455206e-15)))))))))))))))))))
+
+        (local.set $cos
+            (f64.add (f64.const 1) (f64.mul (local.get $z)
+            (f64.add (f64.const -0.5) (f64.mul (local.get $z)
+            (f64.add (f64.const 0.041666666666666664) (f64.mul (local.get $z)
+            (f64.add (f64.const -0.001388888888888889) (f64.mul (local.get $z)
+            (f64.add (f64.const 2.48015873015873e-5) (f64.mul (local.get $z)
+            (f64.add (f64.const -2.755731922398589e-7) (f64.mul (local.get $z)
+            (f64.add (f64.const 2.08767569878681e-9) (f64.mul (local.get $z)
+            (f64.add (f64.const -1.1470745597729725e-11) (f64.mul (local.get $z)
+            (f64.const 4.779477332387385e-14))))))))))))))))))
+
+        (if (i32.eq (local.get $quadrant) (i32.const 0)) (then (return (local.get $cos))))
+        (if (i32.eq (local.get $quadrant) (i32.const 1)) (then (return (f64.neg (local.get $sin)))))
+        (if (i32.eq (local.get $quadrant) (i32.const 2)) (then (return (f64.neg (local.get $cos)))))
+        (local.get $sin)
+    )
+)
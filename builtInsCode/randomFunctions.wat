@@ -0,0 +1,64 @@
+(module
+  ;; The state of the xorshift64* generator. The compiler imports it together with the functions that use it
+  (global $state (mut i64) (i64.const 0x2545f4914f6cdd1d))
+
+  (type $0 (func (param i32) (result i32)))
+  (type $1 (func (param i32) (param i32) (result i32)))
+  (type $2 (func (result f32)))
+
+  ;; Mixes the seed with splitmix64 so that seeds close to each other give different sequences. The state can never be zero
+  (func $seed (type $0) (param $n i32) (result i32)
+    (local $z i64)
+
+    (local.set $z (i64.add (i64.extend_i32_s (local.get $n)) (i64.const 0x9e3779b97f4a7c15)))
+    (local.set $z (i64.mul (i64.xor (local.get $z) (i64.shr_u (local.get $z) (i64.const 30))) (i64.const 0xbf58476d1ce4e5b9)))
+    (local.set $z (i64.mul (i64.xor (local.get $z) (i64.shr_u (local.get $z) (i64.const 27))) (i64.const 0x94d049bb133111eb)))
+    (local.set $z (i64.xor (local.get $z) (i64.shr_u (local.get $z) (i64.const 31))))
+
+    (if (i64.eqz (local.get $z))
+      (then (local.set $z (i64.const 0x2545f4914f6cdd1d)))
+    )
+
+    (global.set $state (local.get $z))
+    (local.get $n)
+  )
+
+  ;; Gives a number in the range lo to hi - 1. Unreachable if hi is not greater than lo
+  (func $randomInt (type $1) (param $lo i32) (param $hi i32) (result i32)
+    (local $x i64)
+
+    (if (i32.le_s (local.get $hi) (local.get $lo)) (then (unreachable)))
+
+    ;; xorshift64*
+    (local.set $x (global.get $state))
+    (local.set $x (i64.xor (local.get $x) (i64.shr_u (local.get $x) (i64.const 12))))
+    (local.set $x (i64.xor (local.get $x) (i64.shl (local.get $x) (i64.const 25))))
+    (local.set $x (i64.xor (local.get $x) (i64.shr_u (local.get $x) (i64.const 27))))
+    (global.set $state (local.get $x))
+    (local.set $x (i64.shr_u (i64.mul (local.get $x) (i64.const 0x2545f4914f6cdd1d)) (i64.const 32)))
+
+    ;; Scales the upper 32 bits to the range with a multiplication instead of a modulo. hi - lo always fits in an unsigned i32
+    (i32.add
+      (local.get $lo)
+      (i32.wrap_i64 (i64.shr_u (i64.mul (local.get $x) (i64.extend_i32_u (i32.sub (local.get $hi) (local.get $lo)))) (i64.const 32)))
+    )
+  )
+
+  ;; Gives a float in the range 0 to 1, 1 not included
+  (func $randomFloat (type $2) (result f32)
+    (local $x i64)
+
+    ;; xorshift64*
+    (local.set $x (global.get $state))
+    (local.set $x (i64.xor (local.get $x) (i64.shr_u (local.get $x) (i64.const 12))))
+    (local.set $x (i64.xor (local.get $x) (i64.shl (local.get $x) (i64.const 25))))
+    (local.set $x (i64.xor (local.get $x) (i64.shr_u (local.get $x) (i64.const 27))))
+    (global.set $state (local.get $x))
+
+    ;; The upper 24 bits fit exactly in the mantissa of a float
+    (f32.mul
+      (f32.convert_i64_u (i64.shr_u (i64.mul (local.get $x) (i64.const 0x2545f4914f6cdd1d)) (i64.const 40)))
+      (f32.const 5.9604644775390625e-8) ;; 2^-24
+    )
+  )
+)
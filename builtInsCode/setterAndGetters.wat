@@ -4,6 +4,10 @@
     (type $1 (func (param i32) (param i32) (result f32)))
     (type $2 (func (param i32) (param i32) (param i32) (result i32)))
     (type $3 (func (param i32) (param i32) (param f32) (result i32)))
+    (type $4 (func (param i32) (param i32) (result i64)))
+    (type $5 (func (param i32) (param i32) (result f64)))
+    (type $6 (func (param i32) (param i32) (param i64) (result i32)))
+    (type $7 (func (param i32) (param i32) (param f64) (result i32)))
 
     (func $i32get (type $0) (param $arrayPointer i32) (param $index i32) (result i32)
         (if (i32.ge_u (local.get $index) (i32.load (local.get $arrayPointer))) (then (unreachable)))
@@ -45,5 +49,33 @@
 
         (f32.store (i32.add (i32.add (local.get $arrayPointer) (i32.const 4)) (i32.mul (local.get $index) (i32.const 4))) (local.get $elementValue))
         (local.get $arrayPointer)
-    ) 
+    )
+
+    (func $i64get (type $4) (param $arrayPointer i32) (param $index i32) (result i64)
+        (if (i32.ge_u (local.get $index) (i32.load (local.get $arrayPointer))) (then (unreachable)))
+
+        (local.set $arrayPointer (i32.add (local.get $arrayPointer) (i32.const 4)))
+        (i64.load (i32.add (local.get $arrayPointer) (i32.mul (local.get $index) (i32.const 8))))
+    )
+
+    (func $f64get (type $5) (param $arrayPointer i32) (param $index i32) (result f64)
+        (if (i32.ge_u (local.get $index) (i32.load (local.get $arrayPointer))) (then (unreachable)))
+
+        (local.set $arrayPointer (i32.add (local.get $arrayPointer) (i32.const 4)))
+        (f64.load (i32.add (local.get $arrayPointer) (i32.mul (local.get $index) (i32.const 8))))
+    )
+
+    (func $i64set (type $6) (param $arrayPointer i32) (param $index i32) (param $elementValue i64) (result i32)
+        (if (i32.ge_u (local.get $index) (i32.load (local.get $arrayPointer))) (then (unreachable)))
+
+        (i64.store (i32.add (i32.add (local.get $arrayPointer) (i32.const 4)) (i32.mul (local.get $index) (i32.const 8))) (local.get $elementValue))
+        (local.get $arrayPointer)
+    )
+
+    (func $f64set (type $7) (param $arrayPointer i32) (param $index i32) (param $elementValue f64) (result i32)
+        (if (i32.ge_u (local.get $index) (i32.load (local.get $arrayPointer))) (then (unreachable)))
+
+        (f64.store (i32.add (i32.add (local.get $arrayPointer) (i32.const 4)) (i32.mul (local.get $index) (i32.const 8))) (local.get $elementValue))
+        (local.get $arrayPointer)
+    )
 )
\ No newline at end of file
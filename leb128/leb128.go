@@ -1,6 +1,9 @@
 package leb128
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 func Int32ToULEB128(i_ int32) []byte {
 	i := uint32(i_)
@@ -40,6 +43,24 @@ func Int32ToLEB128(n int32) []byte {
 	return leb
 }
 
+func Int64ToLEB128(n int64) []byte {
+	leb := make([]byte, 0)
+	for {
+		var (
+			b    = byte(n & 0x7F)
+			sign = byte(n & 0x40)
+		)
+		if n >>= 7; sign == 0 && n != 0 || n != -1 && (n != 0 || sign != 0) {
+			b |= 0x80
+		}
+		leb = append(leb, b)
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	return leb
+}
+
 func LEB128ToInt32(bytes []byte) (int, error) {
 	result := 0
 	shift := 0
@@ -69,3 +90,70 @@ func NumBytesInLEB128(bytes []byte) (int, error) {
 
 	return 0, fmt.Errorf("invalid LEB128 encoding")
 }
+
+// Decodes an unsigned LEB128 number of at most 32 bits. Returns the number and the number of bytes it used
+func ULEB128ToUint32(bytes []byte) (uint32, int, error) {
+	result := uint64(0)
+	shift := 0
+
+	for i := 0; i < len(bytes) && i < 5; i++ {
+		result |= uint64(bytes[i]&0x7f) << shift
+		shift += 7
+		if bytes[i]&0x80 == 0 {
+			if result > math.MaxUint32 {
+				return 0, 0, fmt.Errorf("LEB128 number is larger than 32 bits")
+			}
+
+			return uint32(result), i + 1, nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("invalid LEB128 encoding")
+}
+
+// Decodes a signed LEB128 number of at most 32 bits. Returns the number and the number of bytes it used
+func SLEB128ToInt32(bytes []byte) (int32, int, error) {
+	result, numBytes, err := decodeSignedLEB128(bytes, 32)
+	return int32(result), numBytes, err
+}
+
+// Decodes a signed LEB128 number of at most 64 bits. Returns the number and the number of bytes it used
+func SLEB128ToInt64(bytes []byte) (int64, int, error) {
+	return decodeSignedLEB128(bytes, 64)
+}
+
+// Decodes a signed LEB128 number of at most 33 bits, which is used for block types
+func SLEB128ToInt33(bytes []byte) (int64, int, error) {
+	return decodeSignedLEB128(bytes, 33)
+}
+
+func decodeSignedLEB128(bytes []byte, bits int) (int64, int, error) {
+	result := int64(0)
+	shift := 0
+	maxBytes := (bits + 6) / 7
+
+	for i := 0; i < len(bytes) && i < maxBytes; i++ {
+		result |= int64(bytes[i]&0x7f) << shift
+		shift += 7
+		if bytes[i]&0x80 != 0 {
+			continue
+		}
+
+		//The last byte of a 64 bit number holds only its top bit, and its unused bits must be the sign extension of it
+		if shift > 64 && bytes[i] != 0 && bytes[i] != 0x7f {
+			return 0, 0, fmt.Errorf("LEB128 number is larger than %v bits", bits)
+		}
+
+		if shift < 64 && bytes[i]&0x40 != 0 {
+			result |= -1 << shift
+		}
+
+		if bits < 64 && (result < -(1<<(bits-1)) || result >= 1<<(bits-1)) {
+			return 0, 0, fmt.Errorf("LEB128 number is larger than %v bits", bits)
+		}
+
+		return result, i + 1, nil
+	}
+
+	return 0, 0, fmt.Errorf("invalid LEB128 encoding")
+}
@@ -0,0 +1,138 @@
+package leb128
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestULEB128ToUint32(t *testing.T) {
+	tests := []struct {
+		name     string
+		bytes    []byte
+		expected uint32
+		numBytes int
+		err      string
+	}{
+		{"zero", []byte{0x00}, 0, 1, ""},
+		{"largest one byte", []byte{0x7f}, 127, 1, ""},
+		{"smallest two bytes", []byte{0x80, 0x01}, 128, 2, ""},
+		{"padded zero", []byte{0x80, 0x80, 0x00}, 0, 3, ""},
+		{"max uint32", []byte{0xff, 0xff, 0xff, 0xff, 0x0f}, math.MaxUint32, 5, ""},
+		{"stops at the last byte", []byte{0xe5, 0x8e, 0x26, 0xff}, 624485, 3, ""},
+		{"empty", []byte{}, 0, 0, "invalid LEB128 encoding"},
+		{"truncated", []byte{0x80, 0x80}, 0, 0, "invalid LEB128 encoding"},
+		{"more than 5 bytes", []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, 0, 0, "invalid LEB128 encoding"},
+		{"larger than 32 bits", []byte{0xff, 0xff, 0xff, 0xff, 0x1f}, 0, 0, "larger than 32 bits"},
+	}
+
+	for _, test := range tests {
+		value, numBytes, err := ULEB128ToUint32(test.bytes)
+		if !errorMatches(err, test.err) {
+			t.Errorf("%s: got error %v, expected %q", test.name, err, test.err)
+			continue
+		}
+
+		if value != test.expected || numBytes != test.numBytes {
+			t.Errorf("%s: got %v in %v bytes, expected %v in %v bytes", test.name, value, numBytes, test.expected, test.numBytes)
+		}
+	}
+}
+
+func TestSignedLEB128(t *testing.T) {
+	tests := []struct {
+		name     string
+		bytes    []byte
+		bits     int
+		expected int64
+		numBytes int
+		err      string
+	}{
+		{"zero", []byte{0x00}, 32, 0, 1, ""},
+		{"minus one", []byte{0x7f}, 32, -1, 1, ""},
+		{"largest one byte", []byte{0x3f}, 32, 63, 1, ""},
+		{"smallest one byte", []byte{0x40}, 32, -64, 1, ""},
+		{"64 needs two bytes", []byte{0xc0, 0x00}, 32, 64, 2, ""},
+		{"-65 needs two bytes", []byte{0xbf, 0x7f}, 32, -65, 2, ""},
+		{"max int32", []byte{0xff, 0xff, 0xff, 0xff, 0x07}, 32, math.MaxInt32, 5, ""},
+		{"min int32", []byte{0x80, 0x80, 0x80, 0x80, 0x78}, 32, math.MinInt32, 5, ""},
+		{"above max int32", []byte{0x80, 0x80, 0x80, 0x80, 0x08}, 32, 0, 0, "larger than 32 bits"},
+		{"below min int32", []byte{0xff, 0xff, 0xff, 0xff, 0x77}, 32, 0, 0, "larger than 32 bits"},
+		{"more than 5 bytes for 32 bits", []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, 32, 0, 0, "invalid LEB128 encoding"},
+		{"max int33", []byte{0xff, 0xff, 0xff, 0xff, 0x0f}, 33, math.MaxUint32, 5, ""},
+		{"min int33", []byte{0x80, 0x80, 0x80, 0x80, 0x70}, 33, -(1 << 32), 5, ""},
+		{"above max int33", []byte{0x80, 0x80, 0x80, 0x80, 0x10}, 33, 0, 0, "larger than 33 bits"},
+		{"empty block type", []byte{0x40}, 33, -64, 1, ""},
+		{"max int64", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00}, 64, math.MaxInt64, 10, ""},
+		{"min int64", []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x7f}, 64, math.MinInt64, 10, ""},
+		{"unused bits of the last byte of int64", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, 64, 0, 0, "larger than 64 bits"},
+		{"more than 10 bytes for 64 bits", []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, 64, 0, 0, "invalid LEB128 encoding"},
+		{"truncated", []byte{0xff}, 64, 0, 0, "invalid LEB128 encoding"},
+		{"empty", []byte{}, 64, 0, 0, "invalid LEB128 encoding"},
+	}
+
+	for _, test := range tests {
+		value, numBytes, err := decodeSignedLEB128(test.bytes, test.bits)
+		if !errorMatches(err, test.err) {
+			t.Errorf("%s: got error %v, expected %q", test.name, err, test.err)
+			continue
+		}
+
+		if value != test.expected || numBytes != test.numBytes {
+			t.Errorf("%s: got %v in %v bytes, expected %v in %v bytes", test.name, value, numBytes, test.expected, test.numBytes)
+		}
+	}
+}
+
+func TestInt64ToLEB128(t *testing.T) {
+	tests := []struct {
+		value    int64
+		expected []byte
+	}{
+		{0, []byte{0x00}},
+		{64, []byte{0xc0, 0x00}},
+		{-65, []byte{0xbf, 0x7f}},
+		{5000000000, []byte{0x80, 0xe4, 0x97, 0xd0, 0x12}},
+		{-5000000000, []byte{0x80, 0x9c, 0xe8, 0xaf, 0x6d}},
+		{math.MaxInt64, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00}},
+		{math.MinInt64, []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x7f}},
+	}
+
+	for _, test := range tests {
+		bytes := Int64ToLEB128(test.value)
+		if string(bytes) != string(test.expected) {
+			t.Errorf("%v encoded as %x, expected %x", test.value, bytes, test.expected)
+		}
+	}
+}
+
+func TestRoundTrips(t *testing.T) {
+	int32Values := []int32{0, 1, -1, 63, -64, 64, -65, 127, 128, 8191, -8192, math.MaxInt32, math.MinInt32}
+	for _, value := range int32Values {
+		decoded, numBytes, err := SLEB128ToInt32(Int32ToLEB128(value))
+		if err != nil || decoded != value || numBytes != len(Int32ToLEB128(value)) {
+			t.Errorf("signed %v decoded as %v in %v bytes with error %v", value, decoded, numBytes, err)
+		}
+
+		unsigned, numBytes, err := ULEB128ToUint32(Int32ToULEB128(value))
+		if err != nil || unsigned != uint32(value) || numBytes != len(Int32ToULEB128(value)) {
+			t.Errorf("unsigned %v decoded as %v in %v bytes with error %v", uint32(value), unsigned, numBytes, err)
+		}
+	}
+
+	int64Values := []int64{0, 1, -1, math.MaxInt32 + 1, math.MinInt32 - 1, 1 << 62, -(1 << 62), math.MaxInt64, math.MinInt64}
+	for _, value := range int64Values {
+		decoded, numBytes, err := SLEB128ToInt64(Int64ToLEB128(value))
+		if err != nil || decoded != value || numBytes != len(Int64ToLEB128(value)) {
+			t.Errorf("signed %v decoded as %v in %v bytes with error %v", value, decoded, numBytes, err)
+		}
+	}
+}
+
+func errorMatches(err error, expected string) bool {
+	if expected == "" {
+		return err == nil
+	}
+
+	return err != nil && strings.Contains(err.Error(), expected)
+}
@@ -12,8 +12,9 @@ type Lexer struct {
 	curLine      int
 }
 
+// Lines are counted from 1, so the lines in errors are the lines shown by editors
 func New(input string) Lexer {
-	return Lexer{input: []rune(input)}
+	return Lexer{input: []rune(input), curLine: 1}
 }
 
 func (l *Lexer) NextToken() token.Token {
@@ -41,11 +42,22 @@ func (l *Lexer) NextToken() token.Token {
 	}
 
 	for _, typeToken := range token.TypeTokens {
-		if l.tokenIs(typeToken) {
+		isTypeToken := l.keywordIs(typeToken)
+		if typeToken == token.ARRAY_TYPE {
+			isTypeToken = l.tokenIs(typeToken)
+		}
+
+		if isTypeToken {
 			return l.newToken(token.TYPE, typeToken, l.curLine)
 		}
 	}
 
+	for _, keyword := range token.Keywords {
+		if l.keywordIs(keyword) {
+			return l.newToken(keyword, keyword, l.curLine)
+		}
+	}
+
 	for _, tokenType := range token.TokenLiterals {
 		if l.tokenIs(tokenType) {
 			return l.newToken(tokenType, tokenType, l.curLine)
@@ -136,6 +148,20 @@ func (l *Lexer) tokenIs(token string) bool {
 	return true
 }
 
+func (l *Lexer) keywordIs(keyword string) bool {
+	if !l.tokenIs(keyword) {
+		return false
+	}
+
+	charAfterPos := l.readPosition + len([]rune(keyword))
+	if charAfterPos >= len(l.input) {
+		return true
+	}
+
+	charAfter := l.input[charAfterPos]
+	return !(unicode.IsDigit(charAfter) || unicode.IsLetter(charAfter))
+}
+
 func (l *Lexer) getCurChar() rune {
 	if l.readPosition >= len(l.input) {
 		return 0
@@ -154,7 +180,6 @@ func (l *Lexer) curCharIsValidInVariable() bool {
 }
 
 func (l *Lexer) readString() token.Token {
-	l.readPosition++
 	stringLiteral := ""
 
 	for {
@@ -214,5 +239,13 @@ func (l *Lexer) readNumber() token.Token {
 		l.readPosition++
 	}
 
+	if l.getCurChar() == 'L' && numberType == token.INT {
+		l.readPosition++
+		numberType = token.LONG
+	} else if l.getCurChar() == 'D' {
+		l.readPosition++
+		numberType = token.DOUBLE
+	}
+
 	return token.New(numberType, numberLiteral, l.curLine)
 }
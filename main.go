@@ -1,30 +1,53 @@
 package main
 
 import (
+	"compiler/modules"
 	"compiler/parser"
 	"compiler/wasmCompiler"
+	"compiler/wasmDisassembler"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
+// The options given after the file name or before it
+type options struct {
+	fileName string
+	emit     string //wasm writes main.wasm, wat prints the module in the WebAssembly text format
+	folded   bool   //The text format is printed with folded instructions instead of one instruction on every line
+}
+
 func main() {
-	if len(os.Args) <= 1 {
-		fmt.Println("no file given")
-		os.Exit(1)
+	if len(os.Args) > 1 && os.Args[1] == "disasm" {
+		disassemble(os.Args[2:])
+		return
 	}
 
-	fileData, err := os.ReadFile(os.Args[1])
+	compileOptions, err := parseArguments(os.Args[1:])
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
 
-	syntaxTree, err := parser.Parse(string(fileData))
+	syntaxTree, err := modules.Load(compileOptions.fileName, getStandardLibrary())
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
 
+	if compileOptions.emit == "wat" {
+		text, err := wasmCompiler.CompileToText(syntaxTree, compileOptions.folded)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Print(text)
+		return
+	}
+
 	byteCode, err := wasmCompiler.Compile(syntaxTree)
 	if err != nil {
 		fmt.Println(err.Error())
@@ -38,6 +61,73 @@ func main() {
 	}
 }
 
+func parseArguments(arguments []string) (options, error) {
+	parsed := options{emit: "wasm"}
+
+	for i := 0; i < len(arguments); i++ {
+		argument := arguments[i]
+
+		switch {
+		case strings.HasPrefix(argument, "--emit="):
+			parsed.emit = strings.TrimPrefix(argument, "--emit=")
+			if parsed.emit != "wasm" && parsed.emit != "wat" {
+				return options{}, fmt.Errorf("unknown output %s, expected --emit=wasm or --emit=wat", parsed.emit)
+			}
+		case argument == "--fold":
+			parsed.folded = true
+		case strings.HasPrefix(argument, "--"):
+			return options{}, fmt.Errorf("unknown option %s", argument)
+		case parsed.fileName != "":
+			return options{}, fmt.Errorf("more than one file given")
+		default:
+			parsed.fileName = argument
+		}
+	}
+
+	if parsed.fileName == "" {
+		return options{}, fmt.Errorf("no file given")
+	}
+
+	return parsed, nil
+}
+
+// Prints the sections and instructions of a wasm file, with their positions in the file
+func disassemble(arguments []string) {
+	if len(arguments) != 1 {
+		fmt.Println("usage: waffle disasm file.wasm")
+		os.Exit(1)
+	}
+
+	moduleBytes, err := os.ReadFile(arguments[0])
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	//The functions of the standard functions files are named with the names the compiler uses for them
+	text, err := wasmDisassembler.Disassemble(moduleBytes, wasmCompiler.StandardFunctionNames(arguments[0]))
+	if err != nil {
+		fmt.Printf("Error decoding %s: %s\n", arguments[0], err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Print(text)
+}
+
+// The standard library is the directory given by WAFFLE_STDLIB, or the stdlib directory next to the compiler
+func getStandardLibrary() fs.FS {
+	if path, isSet := os.LookupEnv("WAFFLE_STDLIB"); isSet {
+		return os.DirFS(path)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil
+	}
+
+	return os.DirFS(filepath.Join(filepath.Dir(executable), "stdlib"))
+}
+
 func Compile(input string) ([]byte, error) {
 	syntaxTree, err := parser.Parse(input)
 	if err != nil {
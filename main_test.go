@@ -0,0 +1,255 @@
+package main
+
+import (
+	"compiler/modules"
+	"compiler/wasmCompiler"
+	"compiler/wasmDecoder"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//The programs in testdata/programs are compiled and their exported functions are run with node. Every program has
+//lines like //run: main 3 4 = 7 giving a function, its arguments and the result it must return, or trap when it must
+//stop with a runtime error. Building with -tags debug validates every compiled module as well. The programs in
+//testdata/errors must not compile, and have a line like //error: message with the error they give. The modules
+//imported by the programs are in subdirectories, so they are not compiled as programs themselves. The programs in
+//testdata/text are printed in the text format and compared with the .wat and .folded.wat files next to them
+
+type programRun struct {
+	Function  string   `json:"function"`
+	Arguments []string `json:"arguments"`
+	Expected  string   `json:"-"` //The result printed by javascript, or trap
+}
+
+// Runs the functions in the module and prints the result of every run on its own line
+const runScript = `
+const fs = require("fs")
+const runs = JSON.parse(process.argv[2])
+WebAssembly.instantiate(fs.readFileSync(process.argv[1]), {}).then(({ instance }) => {
+  for (const run of runs) {
+    try {
+      const func = instance.exports[run.function]
+      const args = run.arguments.map((arg) => arg.endsWith("L") ? BigInt(arg.slice(0, -1)) : Number(arg))
+      console.log(String(func(...args)))
+    } catch (e) {
+      console.log(e instanceof WebAssembly.RuntimeError ? "trap" : "error: " + e.message)
+    }
+  }
+})
+`
+
+func TestPrograms(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is needed to run the compiled programs")
+	}
+
+	fileNames, err := filepath.Glob(filepath.Join("testdata", "programs", "*.waf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fileName := range fileNames {
+		fileName := fileName
+		t.Run(strings.TrimSuffix(filepath.Base(fileName), ".waf"), func(t *testing.T) {
+			runs, err := readProgramRuns(fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			results := compileAndRun(t, node, fileName, runs)
+			for i := 0; i < len(runs); i++ {
+				if results[i] != runs[i].Expected {
+					t.Errorf("%s %s gave %s, expected %s", runs[i].Function, strings.Join(runs[i].Arguments, " "), results[i], runs[i].Expected)
+				}
+			}
+		})
+	}
+}
+
+func TestProgramErrors(t *testing.T) {
+	fileNames, err := filepath.Glob(filepath.Join("testdata", "errors", "*.waf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fileName := range fileNames {
+		fileName := fileName
+		t.Run(strings.TrimSuffix(filepath.Base(fileName), ".waf"), func(t *testing.T) {
+			content, err := os.ReadFile(fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := ""
+			for _, line := range strings.Split(string(content), "\n") {
+				if strings.HasPrefix(line, "//error:") {
+					expected = strings.TrimSpace(strings.TrimPrefix(line, "//error:"))
+				}
+			}
+
+			if expected == "" {
+				t.Fatalf("%s has no //error: line", fileName)
+			}
+
+			err = compileProgram(fileName)
+			if err == nil {
+				t.Fatalf("expected error %s, the program compiled", expected)
+			}
+
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("expected error %s, got %s", expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestTextOutput(t *testing.T) {
+	fileNames, err := filepath.Glob(filepath.Join("testdata", "text", "*.waf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fileName := range fileNames {
+		fileName := fileName
+		t.Run(strings.TrimSuffix(filepath.Base(fileName), ".waf"), func(t *testing.T) {
+			syntaxTree, err := modules.Load(fileName, os.DirFS("stdlib"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, folded := range []bool{false, true} {
+				expectedFile := strings.TrimSuffix(fileName, ".waf") + ".wat"
+				if folded {
+					expectedFile = strings.TrimSuffix(fileName, ".waf") + ".folded.wat"
+				}
+
+				expected, err := os.ReadFile(expectedFile)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				text, err := wasmCompiler.CompileToText(syntaxTree, folded)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if text != string(expected) {
+					t.Errorf("the text format is not the same as %s:\n%s", expectedFile, text)
+				}
+			}
+		})
+	}
+}
+
+// The compiled programs are decoded and encoded again, which must give the same bytes
+func TestEncodeDecodedPrograms(t *testing.T) {
+	fileNames, err := filepath.Glob(filepath.Join("testdata", "programs", "*.waf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fileName := range fileNames {
+		fileName := fileName
+		t.Run(strings.TrimSuffix(filepath.Base(fileName), ".waf"), func(t *testing.T) {
+			syntaxTree, err := modules.Load(fileName, os.DirFS("stdlib"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			byteCode, err := wasmCompiler.Compile(syntaxTree)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			module, err := wasmDecoder.Decode(byteCode)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			encoded := wasmDecoder.Encode(module)
+			if string(encoded) != string(byteCode) {
+				t.Errorf("encoding the decoded module gives %v bytes, expected the %v bytes of the compiled module", len(encoded), len(byteCode))
+			}
+		})
+	}
+}
+
+func compileProgram(fileName string) error {
+	syntaxTree, err := modules.Load(fileName, os.DirFS("stdlib"))
+	if err != nil {
+		return err
+	}
+
+	_, err = wasmCompiler.Compile(syntaxTree)
+	return err
+}
+
+func readProgramRuns(fileName string) ([]programRun, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]programRun, 0)
+	for _, line := range strings.Split(string(content), "\n") {
+		if !strings.HasPrefix(line, "//run:") {
+			continue
+		}
+
+		run := strings.TrimPrefix(line, "//run:")
+		resultStart := strings.Index(run, "=")
+		if resultStart == -1 || len(strings.Fields(run[:resultStart])) == 0 {
+			return nil, fmt.Errorf("%s: expected //run: function arguments = result, got %s", fileName, line)
+		}
+
+		fields := strings.Fields(run[:resultStart])
+		runs = append(runs, programRun{Function: fields[0], Arguments: append([]string{}, fields[1:]...), Expected: strings.TrimSpace(run[resultStart+1:])})
+	}
+
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("%s has no //run: lines", fileName)
+	}
+
+	return runs, nil
+}
+
+func compileAndRun(t *testing.T, node string, fileName string, runs []programRun) []string {
+	syntaxTree, err := modules.Load(fileName, os.DirFS("stdlib"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byteCode, err := wasmCompiler.Compile(syntaxTree)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wasmFile := filepath.Join(t.TempDir(), "main.wasm")
+	err = os.WriteFile(wasmFile, byteCode, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runsJson, err := json.Marshal(runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command(node, "-e", runScript, wasmFile, string(runsJson)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("node failed: %s\n%s", err.Error(), output)
+	}
+
+	results := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(results) != len(runs) {
+		t.Fatalf("expected %v results from node, got:\n%s", len(runs), output)
+	}
+
+	return results
+}
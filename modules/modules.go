@@ -0,0 +1,140 @@
+package modules
+
+import (
+	"compiler/ast"
+	"compiler/parser"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const fileExtension = ".waf"
+
+type moduleState int
+
+const (
+	moduleNotLoaded moduleState = iota
+	moduleBeingLoaded
+	moduleLoaded
+)
+
+//Parses the file and every module it imports. Modules are searched for in the project root, which is the directory
+//of the file, and then in the standard library
+func Load(filePath string, standardLibrary fs.FS) (ast.Program, error) {
+	fileData, err := os.ReadFile(filePath)
+	if err != nil {
+		return ast.Program{}, err
+	}
+
+	program, err := parser.Parse(string(fileData))
+	if err != nil {
+		return ast.Program{}, err
+	}
+
+	l := loader{
+		projectRoot:     filepath.Dir(filePath),
+		standardLibrary: standardLibrary,
+		states:          make(map[string]moduleState),
+		modules:         make([]ast.Module, 0),
+	}
+
+	err = l.loadImports(program.Body, []string{})
+	if err != nil {
+		return ast.Program{}, err
+	}
+
+	program.Modules = l.modules
+	return program, nil
+}
+
+type loader struct {
+	projectRoot     string
+	standardLibrary fs.FS
+	states          map[string]moduleState
+
+	//Modules are added after the modules they import
+	modules []ast.Module
+}
+
+//importPath holds the modules importing the block, which are used to describe cycles of imports
+func (l *loader) loadImports(block ast.BlockStatement, importPath []string) error {
+	for i := 0; i < len(block.Statements); i++ {
+		importStatement, isImport := block.Statements[i].(ast.ImportStatement)
+		if !isImport {
+			continue
+		}
+
+		err := l.loadModule(importStatement.Path, importPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *loader) loadModule(path string, importPath []string) error {
+	switch l.states[path] {
+	case moduleLoaded:
+		return nil
+	case moduleBeingLoaded:
+		cycle := append(importPath[indexOf(path, importPath):], path)
+		return fmt.Errorf("Modules can not import each other in a cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	l.states[path] = moduleBeingLoaded
+
+	source, err := l.readModule(path)
+	if err != nil {
+		return err
+	}
+
+	program, err := parser.Parse(source)
+	if err != nil {
+		return fmt.Errorf("Error in module %s: %s", path, err.Error())
+	}
+
+	err = l.loadImports(program.Body, append(importPath, path))
+	if err != nil {
+		return err
+	}
+
+	l.modules = append(l.modules, ast.Module{Path: path, Body: program.Body})
+	l.states[path] = moduleLoaded
+
+	return nil
+}
+
+//Modules in the project are used before modules in the standard library with the same path
+func (l *loader) readModule(path string) (string, error) {
+	fileName := path + fileExtension
+
+	fileData, err := os.ReadFile(filepath.Join(l.projectRoot, filepath.FromSlash(fileName)))
+	if err == nil {
+		return string(fileData), nil
+	}
+
+	searched := []string{filepath.Join(l.projectRoot, filepath.FromSlash(fileName))}
+	if l.standardLibrary != nil {
+		fileData, err = fs.ReadFile(l.standardLibrary, fileName)
+		if err == nil {
+			return string(fileData), nil
+		}
+
+		searched = append(searched, "the standard library")
+	}
+
+	return "", fmt.Errorf("Module %s not found. Searched %s", path, strings.Join(searched, " and "))
+}
+
+func indexOf(s string, sList []string) int {
+	for i := 0; i < len(sList); i++ {
+		if sList[i] == s {
+			return i
+		}
+	}
+
+	return 0
+}
@@ -18,27 +18,32 @@ func isAssignmentStatement(tokensOnLine []token.Token) bool {
 }
 
 func (p *parser) parseAssignmentStatement(tokensBeforeAssignmentToken []token.Token) (ast.AssignmentStatement, error) {
-	statement := ast.AssignmentStatement{}
-
-	variables, err := parseListOftVariables(tokensBeforeAssignmentToken)
+	patterns, err := parsePatterns(tokensBeforeAssignmentToken)
 	if err != nil {
-		return statement, err
+		return ast.AssignmentStatement{}, err
 	}
 
 	expressionTokens, err := p.GetAllTokensInExpression()
 	if err != nil {
-		return statement, err
+		return ast.AssignmentStatement{}, err
 	}
 
 	expression, err := parseExpression(expressionTokens)
 	if err != nil {
-		return statement, err
+		return ast.AssignmentStatement{}, err
 	}
 
-	statement.Variables = variables
-	statement.Value = expression
+	return newAssignmentStatement(patterns, expression), nil
+}
 
-	return statement, nil
+// Values assigned to patterns that are not only variables are destructured
+func newAssignmentStatement(patterns []ast.Node, expression ast.Node) ast.AssignmentStatement {
+	statement := ast.AssignmentStatement{Variables: getPatternVariables(patterns), Value: expression}
+	if !isOnlyVariables(patterns) {
+		statement.Value = ast.DestructureExpression{Patterns: patterns, Value: expression}
+	}
+
+	return statement
 }
 
 func parseListOftVariables(variableTokens []token.Token) ([]ast.Variable, error) {
@@ -75,3 +80,13 @@ func parseListOftVariables(variableTokens []token.Token) ([]ast.Variable, error)
 
 	return variables, nil
 }
+
+// Assignment statements start with a variable or a pattern
+func isStartOfAssignmentStatement(t token.Token) bool {
+	switch t.Type {
+	case token.VARIABLE, token.WILDCARD, token.LEFT_PARENTHESIS, token.START_ARRAY, token.START_BLOCK:
+		return true
+	}
+
+	return false
+}
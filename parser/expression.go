@@ -46,6 +46,30 @@ func (p *parser) GetAllTokensInExpression() ([]token.Token, error) {
 	return outputTokens, nil
 }
 
+// Binary operators grouped by precedence, from lowest to highest
+var operatorPrecedenceLevels = [][]string{
+	{token.OR},
+	{token.AND},
+	{
+		token.EQUAL,
+		token.NOT_EQUAL,
+		token.GREATER_THEN,
+		token.LESS_THEN,
+		token.EQUAL_OR_GREATER_THEN,
+		token.EQUAL_OR_LESS_THEN,
+	},
+	{token.PLUS, token.MINUS, token.BIT_OR, token.BIT_XOR},
+	{
+		token.MULT,
+		token.DIV,
+		token.MOD,
+		token.BIT_AND,
+		token.SHIFT_LEFT,
+		token.SHIFT_RIGHT,
+		token.SHIFT_RIGHT_UNSIGNED,
+	},
+}
+
 func parseExpression(tokens []token.Token) (ast.Node, error) {
 
 	if len(tokens) == 0 {
@@ -75,6 +99,28 @@ func parseExpression(tokens []token.Token) (ast.Node, error) {
 			}, nil
 		}
 
+		if tokens[0].Type == token.LONG {
+			tokenValue, err := strconv.ParseInt(tokens[0].Literal, 10, 64)
+			if err != nil {
+				return ast.StringExpression{}, errors.NewInternalParserError("Token with long type not parsable as long: " + err.Error())
+			}
+
+			return ast.LongExpression{
+				Value: tokenValue,
+			}, nil
+		}
+
+		if tokens[0].Type == token.DOUBLE {
+			tokenValue, err := strconv.ParseFloat(tokens[0].Literal, 64)
+			if err != nil {
+				return ast.StringExpression{}, errors.NewInternalParserError("Token with double type not parsable as double: " + err.Error())
+			}
+
+			return ast.DoubleExpression{
+				Value: tokenValue,
+			}, nil
+		}
+
 		if tokens[0].Type == token.STRING {
 			return ast.StringExpression{
 				Value: tokens[0].Literal,
@@ -115,49 +161,64 @@ func parseExpression(tokens []token.Token) (ast.Node, error) {
 		return parseIfExpression(tokens)
 	}
 
-	//Find operator to be executed last
-	// 	leftmost comparative operator that is not in parenthesis
-	// 	leftmost + or - that is not in parenthesis
-	// 	leftmost * or / that is not in parenthesis
+	if tokens[0].Type == token.MATCH {
+		return parseMatchExpression(tokens)
+	}
 
-	operator, pos, err := findLeftmostTokenOfType([]string{
-		token.OR,
-		token.EQUAL,
-		token.NOT_EQUAL,
-		token.EQUAL_OR_GREATER_THEN,
-		token.EQUAL_OR_LESS_THEN,
-	}, tokens, true)
+	if tokens[0].Type == token.LET {
+		return parseLetExpression(tokens)
+	}
 
-	if pos != -1 {
-		return createOperatorExpression(tokens, operator, pos)
+	//where has lower precedence than all operators, since the values after it are part of the function definition
+	if isFunctionDefinitionWithWhereClause(tokens) {
+		return parseFunctionDefinitionExpression(tokens)
 	}
 
-	operator, pos, err = findLeftmostTokenOfType([]string{
-		token.PLUS,
-		token.MINUS,
-	}, tokens, true)
+	//with has lower precedence than all operators
+	if _, withPos, err := findLeftmostTokenOfType([]string{token.WITH}, tokens, true); err != nil || withPos != -1 {
+		if err != nil {
+			return ast.IntExpression{}, err
+		}
 
-	if pos != -1 {
-		return createOperatorExpression(tokens, operator, pos)
+		return parseRecordUpdateExpression(tokens, withPos)
 	}
 
-	operator, pos, err = findLeftmostTokenOfType([]string{
-		token.MULT,
-		token.DIV,
-	}, tokens, true)
+	//The < starting the type parameters of a generic function must not be parsed as an operator
+	if tokens[0].Type == token.LESS_THEN && isFunctionDefinitionExpression(tokens, 0) {
+		return parseFunctionDefinitionExpression(tokens)
+	}
+
+	//Find operator to be executed last. The levels are tried from the lowest precedence, and findLeftmostTokenOfType
+	//gives the last operator of the level it finds before a function execution, if, match or let, not the first one.
+	//Splitting at the last operator puts the operators before it in the left side, so a - b - c is parsed as
+	//(a - b) - c and operators with the same precedence are evaluated from left to right
+	for _, operators := range operatorPrecedenceLevels {
+		operator, pos, err := findLeftmostTokenOfType(operators, tokens, true)
+		if err != nil {
+			return ast.IntExpression{}, err
+		}
 
-	if pos != -1 {
-		return createOperatorExpression(tokens, operator, pos)
+		if pos != -1 {
+			return createOperatorExpression(tokens, operator, pos)
+		}
 	}
 
-	if err != nil {
-		return ast.IntExpression{}, err
+	if isPrefixOperator(tokens[0]) {
+		return parseUnaryOperatorExpression(tokens)
+	}
+
+	if isFieldAccessExpression(tokens) {
+		return parseFieldAccessExpression(tokens)
 	}
 
 	if isFunctionDefinitionExpression(tokens, 0) {
 		return parseFunctionDefinitionExpression(tokens)
 	}
 
+	if isRecordExpression(tokens, 0) {
+		return parseRecordExpression(tokens)
+	}
+
 	if tokens[0].Type == token.LEFT_PARENTHESIS {
 		return parseParenthesisExpression(tokens)
 	}
@@ -190,12 +251,17 @@ func parseIfExpression(tokens []token.Token) (ast.IfExpression, error) {
 
 	elsePos++ //Adding one because the search started at index 1
 
-	outputIfExpression := ast.IfExpression{}
+	outputIfExpression := ast.IfExpression{Line: tokens[0].Line}
+
+	if elsePos+1 == len(tokens) {
+		return ast.IfExpression{}, errors.NewGeneralError(tokens[elsePos].Line, "Expected false-expression after else")
+	}
 
 	outputIfExpression.FalseExpression, err = parseExpression(tokens[elsePos+1:])
 	if err != nil {
 		return ast.IfExpression{}, err
 	}
+	outputIfExpression.FalseLine = tokens[elsePos+1].Line
 
 	ifExpressionToken := tokens[1:elsePos]
 	ifExpressionSplit, err := splitTokensByExpression(ifExpressionToken)
@@ -223,6 +289,7 @@ func parseIfExpression(tokens []token.Token) (ast.IfExpression, error) {
 	if err != nil {
 		return ast.IfExpression{}, err
 	}
+	outputIfExpression.TrueLine = ifExpressionSplit[1][0].Line
 
 	return outputIfExpression, nil
 }
@@ -295,12 +362,16 @@ func findLeftmostTokenOfType(tokensToFind []string, tokens []token.Token, stopAt
 			continue
 		}
 
+		if isUnaryMinus(tokens, i) {
+			continue
+		}
+
 		if stopAtIfAndFunctionExecution {
 			if tokens[i].Type == token.EXECUTE_FUNCTION {
 				break
 			}
 
-			if tokens[i].Type == token.IF {
+			if tokens[i].Type == token.IF || tokens[i].Type == token.MATCH || tokens[i].Type == token.LET {
 				break
 			}
 		}
@@ -346,12 +417,75 @@ func createOperatorExpression(tokens []token.Token, operator string, operatorPos
 	}, nil
 }
 
+// Parses expressions starting with a prefix operator. Negated number literals are folded into the literal.
+func parseUnaryOperatorExpression(tokens []token.Token) (ast.Node, error) {
+	if len(tokens) < 2 {
+		return ast.UnaryOperatorExpression{}, errors.NewGeneralError(tokens[0].Line, "Expected expression after "+tokens[0].Literal)
+	}
+
+	expression, err := parseExpression(tokens[1:])
+	if err != nil {
+		return ast.UnaryOperatorExpression{}, err
+	}
+
+	if tokens[0].Type == token.MINUS {
+		switch e := expression.(type) {
+		case ast.IntExpression:
+			return ast.IntExpression{Value: -e.Value}, nil
+		case ast.FloatExpression:
+			return ast.FloatExpression{Value: -e.Value}, nil
+		case ast.LongExpression:
+			return ast.LongExpression{Value: -e.Value}, nil
+		case ast.DoubleExpression:
+			return ast.DoubleExpression{Value: -e.Value}, nil
+		}
+	}
+
+	return ast.UnaryOperatorExpression{
+		Operator:   tokens[0].Type,
+		Expression: expression,
+	}, nil
+}
+
+// A minus is unary if it is the first token or comes directly after another operator
+func isUnaryMinus(tokens []token.Token, i int) bool {
+	if tokens[i].Type != token.MINUS {
+		return false
+	}
+
+	if i == 0 {
+		return true
+	}
+
+	return isOperator(tokens[i-1]) || isPrefixOperator(tokens[i-1])
+}
+
 func parseParenthesisExpression(tokens []token.Token) (ast.Node, error) {
 	if tokens[len(tokens)-1].Type != token.RIGHT_PARENTHESIS {
 		return ast.IntExpression{}, errors.NewGeneralError(tokens[0].Line, "Expected ) before end of expression")
 	}
 
-	return parseExpression(tokens[1 : len(tokens)-1])
+	elementsTokens := splitTokenSliceByComma(tokens[1 : len(tokens)-1])
+	if len(elementsTokens) == 1 {
+		return parseExpression(elementsTokens[0])
+	}
+
+	//Values separated by comma in parenthesis create a tuple
+	tupleExpression := ast.TupleExpression{Elements: make([]ast.Node, 0)}
+	for i := 0; i < len(elementsTokens); i++ {
+		if len(elementsTokens[i]) == 0 {
+			return ast.TupleExpression{}, errors.NewGeneralError(tokens[0].Line, "Expected value between commas in tuple")
+		}
+
+		element, err := parseExpression(elementsTokens[i])
+		if err != nil {
+			return ast.TupleExpression{}, err
+		}
+
+		tupleExpression.Elements = append(tupleExpression.Elements, element)
+	}
+
+	return tupleExpression, nil
 }
 
 // v = 5 + 3 ))
@@ -56,17 +56,34 @@ func parseFunctionExecutionExpression(tokens []token.Token) (ast.ExecuteFunction
 func parseFunctionDefinitionExpression(tokens []token.Token) (ast.DefineFunctionExpression, error) {
 	outputFunction := ast.DefineFunctionExpression{}
 
-	tokensInFirstParenthesis, tokensInSecondParenthesis, functionBodyTokens, _, hasSpecifiedReturnTypes, err := getFunctionDefinitionExpressionParts(tokens, 0)
+	typeParameters, _, _, err := parseTypeParameters(tokens, 0)
 	if err != nil {
 		return outputFunction, err
 	}
+	outputFunction.TypeParameters = typeParameters
 
-	arguments, err := parseListOftVariables(tokensInFirstParenthesis)
+	tokensInFirstParenthesis, tokensInSecondParenthesis, functionBodyTokens, indexAfterDefinition, hasSpecifiedReturnTypes, err := getFunctionDefinitionExpressionParts(tokens, 0)
 	if err != nil {
 		return outputFunction, err
 	}
+
+	argumentPatterns, err := parsePatterns(removeTokenFromTokenSlice(tokensInFirstParenthesis, token.NEWLINE))
+	if err != nil {
+		return outputFunction, err
+	}
+
+	arguments, destructuringStatements := getArgumentsAndDestructuring(argumentPatterns)
 	outputFunction.Arguments = arguments
 
+	if indexAfterDefinition < len(tokens) && tokens[indexAfterDefinition].Type == token.WHERE {
+		whereStatements, err := parseWhereClause(tokens[indexAfterDefinition:], getPatternVariables(argumentPatterns))
+		if err != nil {
+			return outputFunction, err
+		}
+
+		destructuringStatements = append(destructuringStatements, whereStatements...)
+	}
+
 	if hasSpecifiedReturnTypes {
 		returnTypes, err := getTypesSeparatedByComma(tokensInSecondParenthesis)
 		if err != nil {
@@ -83,10 +100,6 @@ func parseFunctionDefinitionExpression(tokens []token.Token) (ast.DefineFunction
 		}
 	}
 
-	if !functionIsOneLine && !hasSpecifiedReturnTypes {
-		return outputFunction, fmt.Errorf("Function with multiple lines must have specified return types")
-	}
-
 	outputFunction.NoReturnTypesSpecified = !hasSpecifiedReturnTypes
 
 	if functionIsOneLine { //Adding return if the function is on one line and there is no return there previously
@@ -103,13 +116,10 @@ func parseFunctionDefinitionExpression(tokens []token.Token) (ast.DefineFunction
 	}
 
 	outputFunction.FunctionBody = functionBodyParsed.Body
+	outputFunction.FunctionBody.Statements = append(destructuringStatements, outputFunction.FunctionBody.Statements...)
 	outputFunction.FunctionType = types.FunctionType{ReturnTypes: outputFunction.ReturnTypes}
 	argumentsTypes := make([]types.Type, 0)
 	for i := 0; i < len(outputFunction.Arguments); i++ {
-		if outputFunction.Arguments[i].Type.String() == types.NONE {
-			return outputFunction, fmt.Errorf("Error on line %v: function must have defined argument types", tokens[0].Line)
-		}
-
 		argumentsTypes = append(argumentsTypes, outputFunction.Arguments[i].Type)
 	}
 	outputFunction.FunctionType.ArgumentTypes = argumentsTypes
@@ -118,6 +128,11 @@ func parseFunctionDefinitionExpression(tokens []token.Token) (ast.DefineFunction
 }
 
 func isFunctionDefinitionExpression(tokens []token.Token, i int) bool {
+	_, i, _, err := parseTypeParameters(tokens, i)
+	if err != nil {
+		return false
+	}
+
 	hasArguments, i := skipParenthesis(tokens, token.LEFT_PARENTHESIS, token.RIGHT_PARENTHESIS, i)
 	if !hasArguments {
 		return false
@@ -151,6 +166,11 @@ func isFunctionDefinitionExpression(tokens []token.Token, i int) bool {
 }
 
 func getFunctionDefinitionExpressionParts(tokens []token.Token, startIndex int) (tokensInFirstParenthesis, tokensInSecondParenthesis, functionBodyTokens []token.Token, indexAfterDefinition int, hasSpecifiedReturnTypes bool, e error) {
+	_, startIndex, _, err := parseTypeParameters(tokens, startIndex)
+	if err != nil {
+		return []token.Token{}, []token.Token{}, []token.Token{}, startIndex, false, err
+	}
+
 	tokensInFirstParenthesis, valid, curTokensIndex := getParenthesisContent(tokens, startIndex, token.LEFT_PARENTHESIS, token.RIGHT_PARENTHESIS)
 	if !valid {
 		return []token.Token{}, []token.Token{}, []token.Token{}, curTokensIndex, false, fmt.Errorf("Internal parser error: tokens given to getFunctionDefinitionExpressionParts not valid as function definition. No valid parenthesis at index 0")
@@ -184,6 +204,37 @@ func getFunctionDefinitionExpressionParts(tokens []token.Token, startIndex int)
 	return tokensInFirstParenthesis, tokensInSecondParenthesis, functionBodyTokens, curTokensIndex, true, nil
 }
 
+// Parses the type parameters of a generic function, like <a, b>. Returns the names, the index after the type parameters and if there are type parameters
+func parseTypeParameters(tokens []token.Token, i int) ([]string, int, bool, error) {
+	if i >= len(tokens) || tokens[i].Type != token.LESS_THEN {
+		return []string{}, i, false, nil
+	}
+
+	typeParameters := make([]string, 0)
+	for i++; i < len(tokens); i++ {
+		if tokens[i].Type != token.VARIABLE {
+			return []string{}, i, false, errors.NewSyntaxErrorUnexpectedToken(tokens[i].Line, tokens[i].Literal, "type parameter name")
+		}
+
+		typeParameters = append(typeParameters, tokens[i].Literal)
+		i++
+
+		if i >= len(tokens) {
+			break
+		}
+
+		if tokens[i].Type == token.GREATER_THEN {
+			return typeParameters, i + 1, true, nil
+		}
+
+		if tokens[i].Type != token.COMMA {
+			return []string{}, i, false, errors.NewSyntaxErrorUnexpectedToken(tokens[i].Line, tokens[i].Literal, token.GREATER_THEN)
+		}
+	}
+
+	return []string{}, i, false, errors.NewSyntaxErrorUnexpectedToken(tokens[len(tokens)-1].Line, "end of expression", token.GREATER_THEN)
+}
+
 func removeTokenFromTokenSlice(tokens []token.Token, tokenToRemoveLiteral string) []token.Token {
 	outputTokens := make([]token.Token, 0)
 
@@ -205,7 +256,7 @@ func splitTokensByExpression(tokens []token.Token) ([][]token.Token, error) {
 	lastWasBinaryOperator := true
 
 	for i := 0; i < len(tokens); i++ {
-		if isOperator(tokens[i]) {
+		if isOperator(tokens[i]) || tokens[i].Type == token.DOT {
 			lastWasBinaryOperator = true
 			curExpression = append(curExpression, tokens[i])
 			continue
@@ -216,9 +267,15 @@ func splitTokensByExpression(tokens []token.Token) ([][]token.Token, error) {
 			curExpression = make([]token.Token, 0)
 		}
 
+		if isPrefixOperator(tokens[i]) {
+			lastWasBinaryOperator = true
+			curExpression = append(curExpression, tokens[i])
+			continue
+		}
+
 		lastWasBinaryOperator = false
 
-		if tokens[i].Type == token.EXECUTE_FUNCTION {
+		if tokens[i].Type == token.EXECUTE_FUNCTION || tokens[i].Type == token.MATCH {
 			curExpression = append(curExpression, tokens[i:]...)
 			break
 		}
@@ -235,6 +292,13 @@ func splitTokensByExpression(tokens []token.Token) ([][]token.Token, error) {
 			continue
 		}
 
+		if isRecordExpression(tokens, i) {
+			_, indexAfterRecord := skipParenthesis(tokens, token.START_BLOCK, token.END_BLOCK, i+getTypeNameLength(tokens, i))
+			curExpression = append(curExpression, tokens[i:indexAfterRecord]...)
+			i = indexAfterRecord - 1
+			continue
+		}
+
 		tokensInArray, isValidArray, indexAfterArray := getParenthesisContent(tokens, i, token.START_ARRAY, token.END_ARRAY)
 		if isValidArray {
 			curExpression = append(curExpression, addParenthesis(tokensInArray, token.START_ARRAY, token.END_ARRAY, tokens[i].Line)...)
@@ -276,6 +340,16 @@ func isOperator(t token.Token) bool {
 	return false
 }
 
+func isPrefixOperator(t token.Token) bool {
+	for i := 0; i < len(token.PrefixOperators); i++ {
+		if t.Type == token.PrefixOperators[i] {
+			return true
+		}
+	}
+
+	return false
+}
+
 // adder = _ + _
 //
 // map (3+_) _ . range _
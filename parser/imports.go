@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/token"
+	"strings"
+)
+
+// Parses import statements like: import "math/vector"
+func (p *parser) parseImportStatement() (ast.ImportStatement, error) {
+	line := p.curToken.Line
+	p.NextToken() //Skip the import token
+
+	pathTokens, err := p.GetAllTokensInExpression()
+	if err != nil {
+		return ast.ImportStatement{}, err
+	}
+
+	if len(pathTokens) != 1 || pathTokens[0].Type != token.STRING {
+		return ast.ImportStatement{}, errors.NewGeneralError(line, "Expected the path of the module in quotes after import, like import \"math/vector\"")
+	}
+
+	path := pathTokens[0].Literal
+	if !isValidModulePath(path) {
+		return ast.ImportStatement{}, errors.NewGeneralError(line, "Module path \""+path+"\" must be names separated by /, like \"math/vector\"")
+	}
+
+	return ast.ImportStatement{Path: path, Name: path[strings.LastIndex(path, "/")+1:], Line: line}, nil
+}
+
+// Module paths are relative to the project root, and every part of the path must be a valid name since the last part is used in the code
+func isValidModulePath(path string) bool {
+	for _, part := range strings.Split(path, "/") {
+		if len(part) == 0 || (part[0] >= '0' && part[0] <= '9') {
+			return false
+		}
+
+		for _, c := range part {
+			if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != '_' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
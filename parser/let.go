@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/token"
+	"compiler/types"
+)
+
+//Let expressions and where clauses name values used in an expression. They are written like:
+//let x = a * 2, (q, r) = !divide a b in x + q
+//(a int) -> { x + q } where x = a * 2, (q, r) = !divide a b
+
+func parseLetExpression(tokens []token.Token) (ast.LetExpression, error) {
+	inPos := getLetInPosition(tokens)
+	if inPos == -1 {
+		return ast.LetExpression{}, errors.NewGeneralError(tokens[0].Line, "Expected in after the values bound by let")
+	}
+
+	if inPos+1 == len(tokens) {
+		return ast.LetExpression{}, errors.NewGeneralError(tokens[inPos].Line, "Expected expression after in")
+	}
+
+	bindings, err := parseBindings(tokens[1:inPos], tokens[0])
+	if err != nil {
+		return ast.LetExpression{}, err
+	}
+
+	expression, err := parseExpression(tokens[inPos+1:])
+	if err != nil {
+		return ast.LetExpression{}, err
+	}
+
+	return ast.LetExpression{Bindings: bindings, Expression: expression, ReturnTypes: []types.Type{}}, nil
+}
+
+// Gives the position of the in ending the let at the start of the tokens. Lets in the bound values end with their own in
+func getLetInPosition(tokens []token.Token) int {
+	depth := 0
+	numLets := 0
+
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i].Type {
+		case token.LEFT_PARENTHESIS, token.START_BLOCK, token.START_ARRAY:
+			depth++
+		case token.RIGHT_PARENTHESIS, token.END_BLOCK, token.END_ARRAY:
+			depth--
+		case token.LET:
+			if depth == 0 {
+				numLets++
+			}
+		case token.IN:
+			if depth == 0 {
+				numLets--
+				if numLets == 0 {
+					return i
+				}
+			}
+		}
+	}
+
+	return -1
+}
+
+// Parses the values bound by let or where, separated by comma. Names before a comma without = after them are given
+// values together with the names after the comma, like in: a, b = !divide x 3
+func parseBindings(tokens []token.Token, keyword token.Token) ([]ast.AssignmentStatement, error) {
+	bindings := make([]ast.AssignmentStatement, 0)
+	patternTokens := make([]token.Token, 0)
+
+	for _, bindingTokens := range splitTokenSliceByComma(tokens) {
+		assignPos := -1
+		for i := 0; i < len(bindingTokens); i++ {
+			if bindingTokens[i].Type == token.ASSIGN_VARIABLE {
+				assignPos = i
+				break
+			}
+		}
+
+		if assignPos == -1 {
+			patternTokens = append(patternTokens, bindingTokens...)
+			patternTokens = append(patternTokens, token.New(token.COMMA, token.COMMA, keyword.Line))
+			continue
+		}
+
+		patternTokens = append(patternTokens, bindingTokens[:assignPos]...)
+		if len(patternTokens) == 0 {
+			return bindings, errors.NewGeneralError(keyword.Line, "Expected names before = after "+keyword.Literal)
+		}
+
+		if assignPos+1 == len(bindingTokens) {
+			return bindings, errors.NewGeneralError(keyword.Line, "Expected value after = after "+keyword.Literal)
+		}
+
+		patterns, err := parsePatterns(patternTokens)
+		if err != nil {
+			return bindings, err
+		}
+
+		value, err := parseExpression(bindingTokens[assignPos+1:])
+		if err != nil {
+			return bindings, err
+		}
+
+		bindings = append(bindings, newAssignmentStatement(patterns, value))
+		patternTokens = make([]token.Token, 0)
+	}
+
+	if len(patternTokens) != 0 {
+		return bindings, errors.NewGeneralError(keyword.Line, "Expected names given values with = after "+keyword.Literal+", like "+keyword.Literal+" x = 2, y = 3")
+	}
+
+	return bindings, nil
+}
+
+// Checks if the tokens are a function definition with a where clause after the function body
+func isFunctionDefinitionWithWhereClause(tokens []token.Token) bool {
+	if !isFunctionDefinitionExpression(tokens, 0) {
+		return false
+	}
+
+	_, _, _, indexAfterDefinition, _, err := getFunctionDefinitionExpressionParts(tokens, 0)
+	return err == nil && indexAfterDefinition < len(tokens) && tokens[indexAfterDefinition].Type == token.WHERE
+}
+
+// The values in the where clause are assigned to variables at the start of the function body, so they can use the
+// arguments and be used in the whole body
+func parseWhereClause(tokens []token.Token, arguments []ast.Variable) ([]ast.Node, error) {
+	if len(tokens) == 1 {
+		return []ast.Node{}, errors.NewGeneralError(tokens[0].Line, "Expected values after where")
+	}
+
+	bindings, err := parseBindings(tokens[1:], tokens[0])
+	if err != nil {
+		return []ast.Node{}, err
+	}
+
+	statements := make([]ast.Node, 0)
+	for i := 0; i < len(bindings); i++ {
+		for j := 0; j < len(bindings[i].Variables); j++ {
+			for k := 0; k < len(arguments); k++ {
+				if bindings[i].Variables[j].Identifier == arguments[k].Identifier {
+					return []ast.Node{}, errors.NewGeneralError(tokens[0].Line, "Name "+arguments[k].Identifier+" in where clause is already the name of an argument")
+				}
+			}
+		}
+
+		statements = append(statements, bindings[i])
+	}
+
+	return statements, nil
+}
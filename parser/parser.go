@@ -119,7 +119,7 @@ func (p *parser) Parse() (ast.Program, error) {
 }
 
 func (p *parser) parseStatement(statementParent ast.BlockStatement) (ast.BlockStatement, error) {
-	if p.curToken.Type == token.VARIABLE {
+	if isStartOfAssignmentStatement(p.curToken) {
 		tokensOnLineBeforeStartBlock := p.getTokensBeforeToken([]string{token.ASSIGN_VARIABLE, token.NEWLINE})
 
 		if p.curToken.Type == token.ASSIGN_VARIABLE {
@@ -136,6 +136,26 @@ func (p *parser) parseStatement(statementParent ast.BlockStatement) (ast.BlockSt
 		return ast.BlockStatement{}, errors.NewSyntaxErrorUnexpectedToken(p.curToken.Line, p.curToken.Literal, "token valid at start of statement")
 	}
 
+	if p.curToken.Type == token.IMPORT {
+		statement, err := p.parseImportStatement()
+		if err != nil {
+			return ast.BlockStatement{}, err
+		}
+
+		statementParent.Statements = append(statementParent.Statements, statement)
+		return statementParent, nil
+	}
+
+	if p.curToken.Type == token.TYPE_DEFINITION || p.curToken.Type == token.NEWTYPE {
+		statement, err := p.parseTypeDefinitionStatement()
+		if err != nil {
+			return ast.BlockStatement{}, err
+		}
+
+		statementParent.Statements = append(statementParent.Statements, statement)
+		return statementParent, nil
+	}
+
 	if p.curToken.Type == token.RETURN {
 		p.NextToken() //skip return token
 		expressionsTokens, err := p.GetAllTokensInExpression()
@@ -0,0 +1,329 @@
+package parser
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/token"
+	"compiler/types"
+	"fmt"
+	"strconv"
+)
+
+//Patterns are written where variables are assigned values, and bind the parts of the value to variables
+
+// Parses patterns separated by comma, like: a, (b, c), [d, ...e]
+func parsePatterns(tokens []token.Token) ([]ast.Node, error) {
+	patterns := make([]ast.Node, 0)
+	if len(tokens) == 0 {
+		return patterns, nil
+	}
+
+	patternsTokens := splitPatternsByComma(tokens)
+	for i := 0; i < len(patternsTokens); i++ {
+		pattern, err := parsePattern(patternsTokens[i], tokens[0].Line)
+		if err != nil {
+			return patterns, err
+		}
+
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, nil
+}
+
+// Splits the tokens on the commas outside parentheses, arrays, blocks and type arguments
+func splitPatternsByComma(tokens []token.Token) [][]token.Token {
+	output := make([][]token.Token, 0)
+	curSlice := make([]token.Token, 0)
+	depth := 0
+
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i].Type {
+		case token.LEFT_PARENTHESIS, token.START_ARRAY, token.START_BLOCK, token.LESS_THEN:
+			depth++
+		case token.RIGHT_PARENTHESIS, token.END_ARRAY, token.END_BLOCK, token.GREATER_THEN:
+			depth--
+		case token.SHIFT_RIGHT: //>> ends two lists of type arguments
+			depth -= 2
+		case token.SHIFT_RIGHT_UNSIGNED:
+			depth -= 3
+		case token.COMMA:
+			if depth == 0 {
+				output = append(output, curSlice)
+				curSlice = make([]token.Token, 0)
+				continue
+			}
+		}
+
+		curSlice = append(curSlice, tokens[i])
+	}
+
+	return append(output, curSlice)
+}
+
+func parsePattern(tokens []token.Token, line int) (ast.Node, error) {
+	if len(tokens) == 0 {
+		return ast.Variable{}, fmt.Errorf("Error on line %v: Expected variable or pattern between commas", line)
+	}
+
+	switch tokens[0].Type {
+	case token.VARIABLE, token.WILDCARD:
+		if typeNameLength := getTypeNameLength(tokens, 0); typeNameLength != 0 && typeNameLength < len(tokens) && tokens[typeNameLength].Type == token.START_BLOCK {
+			pattern, indexAfter, err := parseRecordPattern(tokens, typeNameLength, types.AnyType{Name: getTypeName(tokens, 0)})
+			if err != nil {
+				return pattern, err
+			}
+
+			return parsePatternType(pattern, tokens, indexAfter)
+		}
+
+		return parsePatternVariable(tokens)
+
+	case token.LEFT_PARENTHESIS:
+		elementsTokens, isClosed, indexAfter := getParenthesisContent(tokens, 0, token.LEFT_PARENTHESIS, token.RIGHT_PARENTHESIS)
+		if !isClosed {
+			return ast.TuplePattern{}, errors.NewSyntaxErrorUnexpectedToken(tokens[0].Line, tokens[len(tokens)-1].Literal, token.RIGHT_PARENTHESIS)
+		}
+
+		elements, err := parsePatterns(elementsTokens)
+		if err != nil {
+			return ast.TuplePattern{}, err
+		}
+
+		if len(elements) == 0 {
+			return ast.TuplePattern{}, errors.NewGeneralError(tokens[0].Line, "Expected variables or patterns in tuple pattern")
+		}
+
+		if len(elements) == 1 {
+			return parsePatternType(elements[0], tokens, indexAfter)
+		}
+
+		return parsePatternType(ast.TuplePattern{Type: types.StandardType{Name: types.NONE}, Elements: elements}, tokens, indexAfter)
+
+	case token.START_ARRAY:
+		pattern, indexAfter, err := parseArrayPattern(tokens)
+		if err != nil {
+			return pattern, err
+		}
+
+		return parsePatternType(pattern, tokens, indexAfter)
+
+	case token.START_BLOCK:
+		pattern, indexAfter, err := parseRecordPattern(tokens, 0, types.StandardType{Name: types.NONE})
+		if err != nil {
+			return pattern, err
+		}
+
+		return parsePatternType(pattern, tokens, indexAfter)
+	}
+
+	return ast.Variable{}, errors.NewSyntaxErrorUnexpectedToken(tokens[0].Line, tokens[0].Literal, "identifier or pattern")
+}
+
+// A variable in a pattern can have a type after the name. Variables named _ are not bound to any value
+func parsePatternVariable(tokens []token.Token) (ast.Variable, error) {
+	variable := ast.Variable{Identifier: tokens[0].Literal, Type: types.StandardType{Name: types.NONE}}
+	if len(tokens) == 1 {
+		return variable, nil
+	}
+
+	pattern, err := parsePatternType(variable, tokens, 1)
+	if err != nil {
+		return ast.Variable{}, err
+	}
+
+	return pattern.(ast.Variable), nil
+}
+
+// Parses the type written after a pattern, like: (a, b) (int, bool)
+func parsePatternType(pattern ast.Node, tokens []token.Token, i int) (ast.Node, error) {
+	if i == len(tokens) {
+		return pattern, nil
+	}
+
+	patternType, indexAfter, isValidType, err := parseTypeLiteral(tokens, i)
+	if err != nil {
+		return pattern, err
+	}
+
+	if !isValidType {
+		return pattern, errors.NewSyntaxErrorUnexpectedToken(tokens[i].Line, tokens[i].Literal, "type")
+	}
+
+	if indexAfter != len(tokens) {
+		return pattern, errors.NewSyntaxErrorUnexpectedToken(tokens[indexAfter].Line, tokens[indexAfter].Literal, token.COMMA)
+	}
+
+	switch p := pattern.(type) {
+	case ast.Variable:
+		if p.Type.String() != types.NONE {
+			return pattern, errors.NewGeneralError(tokens[i].Line, "Variable "+p.Identifier+" is given more than one type")
+		}
+
+		p.Type = patternType
+		return p, nil
+
+	case ast.TuplePattern:
+		p.Type = patternType
+		return p, nil
+
+	case ast.ArrayPattern:
+		p.Type = patternType
+		return p, nil
+
+	case ast.RecordPattern:
+		if p.Type.String() != types.NONE {
+			return pattern, errors.NewGeneralError(tokens[i].Line, "Record pattern is given more than one type")
+		}
+
+		p.Type = patternType
+		return p, nil
+	}
+
+	return pattern, nil
+}
+
+// Parses patterns like: [first, second, ...rest]
+func parseArrayPattern(tokens []token.Token) (ast.ArrayPattern, int, error) {
+	elementsTokens, isClosed, indexAfter := getParenthesisContent(tokens, 0, token.START_ARRAY, token.END_ARRAY)
+	if !isClosed {
+		return ast.ArrayPattern{}, indexAfter, errors.NewSyntaxErrorUnexpectedToken(tokens[0].Line, tokens[len(tokens)-1].Literal, token.END_ARRAY)
+	}
+
+	if len(elementsTokens) == 0 {
+		return ast.ArrayPattern{}, indexAfter, errors.NewGeneralError(tokens[0].Line, "Expected variables or patterns in array pattern")
+	}
+
+	pattern := ast.ArrayPattern{Type: types.StandardType{Name: types.NONE}, Elements: make([]ast.Node, 0)}
+
+	split := splitPatternsByComma(elementsTokens)
+	for i := 0; i < len(split); i++ {
+		if len(split[i]) != 0 && split[i][0].Type == token.REST {
+			if i != len(split)-1 {
+				return ast.ArrayPattern{}, indexAfter, errors.NewGeneralError(split[i][0].Line, "The variable after ... must be the last in the array pattern")
+			}
+
+			if len(split[i]) != 2 || (split[i][1].Type != token.VARIABLE && split[i][1].Type != token.WILDCARD) {
+				return ast.ArrayPattern{}, indexAfter, errors.NewGeneralError(split[i][0].Line, "Expected one name after ... in array pattern")
+			}
+
+			pattern.Rest = ast.Variable{Identifier: split[i][1].Literal, Type: types.StandardType{Name: types.NONE}}
+			continue
+		}
+
+		element, err := parsePattern(split[i], tokens[0].Line)
+		if err != nil {
+			return ast.ArrayPattern{}, indexAfter, err
+		}
+
+		pattern.Elements = append(pattern.Elements, element)
+	}
+
+	return pattern, indexAfter, nil
+}
+
+// Parses patterns like: Point { x, y }. The variables get the names of the fields
+func parseRecordPattern(tokens []token.Token, i int, recordType types.Type) (ast.RecordPattern, int, error) {
+	fieldTokens, isClosed, indexAfter := getParenthesisContent(tokens, i, token.START_BLOCK, token.END_BLOCK)
+	if !isClosed {
+		return ast.RecordPattern{}, indexAfter, errors.NewSyntaxErrorUnexpectedToken(tokens[0].Line, tokens[len(tokens)-1].Literal, token.END_BLOCK)
+	}
+
+	pattern := ast.RecordPattern{Type: recordType, Fields: make([]ast.Variable, 0)}
+
+	fieldTokens = removeTokenFromTokenSlice(fieldTokens, token.NEWLINE)
+	if len(fieldTokens) == 0 {
+		return ast.RecordPattern{}, indexAfter, errors.NewGeneralError(tokens[0].Line, "Expected field names in record pattern")
+	}
+
+	split := splitTokenSliceByComma(fieldTokens)
+	for j := 0; j < len(split); j++ {
+		if len(split[j]) != 1 || split[j][0].Type != token.VARIABLE {
+			return ast.RecordPattern{}, indexAfter, errors.NewGeneralError(tokens[0].Line, "Expected field names separated by comma in record pattern, like { x, y }")
+		}
+
+		pattern.Fields = append(pattern.Fields, ast.Variable{Identifier: split[j][0].Literal, Type: types.StandardType{Name: types.NONE}})
+	}
+
+	return pattern, indexAfter, nil
+}
+
+// Checks if the patterns are only variables, which are assigned values without destructuring
+func isOnlyVariables(patterns []ast.Node) bool {
+	for i := 0; i < len(patterns); i++ {
+		variable, isVariable := patterns[i].(ast.Variable)
+		if !isVariable || variable.Identifier == token.WILDCARD {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Gives the variables bound by the patterns, in the order their values are given by ast.DestructureExpression
+func getPatternVariables(patterns []ast.Node) []ast.Variable {
+	variables := make([]ast.Variable, 0)
+	for i := 0; i < len(patterns); i++ {
+		switch pattern := patterns[i].(type) {
+		case ast.Variable:
+			if pattern.Identifier != token.WILDCARD {
+				variables = append(variables, pattern)
+			}
+
+		case ast.TuplePattern:
+			variables = append(variables, getPatternVariables(pattern.Elements)...)
+
+		case ast.ArrayPattern:
+			variables = append(variables, getPatternVariables(pattern.Elements)...)
+			if pattern.Rest != nil {
+				variables = append(variables, getPatternVariables([]ast.Node{pattern.Rest})...)
+			}
+
+		case ast.RecordPattern:
+			variables = append(variables, pattern.Fields...)
+		}
+	}
+
+	return variables
+}
+
+// Patterns in the arguments of a function are replaced by arguments that can not be named in the code. The arguments
+// are destructured by assignment statements added to the start of the function body
+func getArgumentsAndDestructuring(patterns []ast.Node) ([]ast.Variable, []ast.Node) {
+	arguments := make([]ast.Variable, 0)
+	statements := make([]ast.Node, 0)
+
+	for i := 0; i < len(patterns); i++ {
+		if variable, isVariable := patterns[i].(ast.Variable); isVariable {
+			if variable.Identifier == token.WILDCARD {
+				variable.Identifier = "#argument" + strconv.Itoa(i)
+			}
+
+			arguments = append(arguments, variable)
+			continue
+		}
+
+		argument := ast.Variable{Identifier: "#argument" + strconv.Itoa(i), Type: getPatternType(patterns[i])}
+		arguments = append(arguments, argument)
+		statements = append(statements, ast.AssignmentStatement{
+			Variables: getPatternVariables(patterns[i : i+1]),
+			Value:     ast.DestructureExpression{Patterns: patterns[i : i+1], Value: argument},
+		})
+	}
+
+	return arguments, statements
+}
+
+// Gives the type written with the pattern, which is the type of the argument replacing it
+func getPatternType(pattern ast.Node) types.Type {
+	switch p := pattern.(type) {
+	case ast.TuplePattern:
+		return p.Type
+	case ast.ArrayPattern:
+		return p.Type
+	case ast.RecordPattern:
+		return p.Type
+	}
+
+	return types.StandardType{Name: types.NONE}
+}
@@ -0,0 +1,290 @@
+package parser
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/token"
+	"compiler/types"
+	"fmt"
+)
+
+// Parses type definitions like: type Point = { x float, y float }
+func (p *parser) parseTypeDefinitionStatement() (ast.TypeDefinitionStatement, error) {
+	line := p.curToken.Line
+	isNewType := p.curToken.Type == token.NEWTYPE
+	p.NextToken() //Skip the type or newtype token
+
+	nameTokens := p.getTokensBeforeToken([]string{token.ASSIGN_VARIABLE, token.NEWLINE})
+	if len(nameTokens) == 0 || nameTokens[0].Type != token.VARIABLE {
+		return ast.TypeDefinitionStatement{}, errors.NewSyntaxErrorUnexpectedToken(line, p.curToken.Literal, "type name")
+	}
+	typeName := nameTokens[0].Literal
+
+	typeParameters, indexAfter, _, err := parseTypeParameters(nameTokens, 1)
+	if err != nil {
+		return ast.TypeDefinitionStatement{}, err
+	}
+
+	if indexAfter != len(nameTokens) {
+		return ast.TypeDefinitionStatement{}, errors.NewSyntaxErrorUnexpectedToken(nameTokens[indexAfter].Line, nameTokens[indexAfter].Literal, token.ASSIGN_VARIABLE)
+	}
+
+	if p.curToken.Type != token.ASSIGN_VARIABLE {
+		return ast.TypeDefinitionStatement{}, errors.NewSyntaxErrorUnexpectedToken(p.curToken.Line, p.curToken.Literal, token.ASSIGN_VARIABLE)
+	}
+	p.NextToken()
+
+	typeTokens, err := p.GetAllTokensInExpression()
+	if err != nil {
+		return ast.TypeDefinitionStatement{}, err
+	}
+
+	if len(typeTokens) == 0 {
+		return ast.TypeDefinitionStatement{}, fmt.Errorf("Error on line %v: Expected type after = in definition of type %s", line, typeName)
+	}
+
+	if isNewType {
+		if len(typeParameters) != 0 {
+			return ast.TypeDefinitionStatement{}, fmt.Errorf("Error on line %v: Newtype %s can not have type parameters", line, typeName)
+		}
+
+		heldType, err := parseTypeInDefinition(typeTokens, typeName)
+		if err != nil {
+			return ast.TypeDefinitionStatement{}, err
+		}
+
+		return ast.TypeDefinitionStatement{Name: typeName, Type: heldType, IsNewType: true}, nil
+	}
+
+	if isTypeAlias(typeTokens) {
+		aliasedType, err := parseTypeInDefinition(typeTokens, typeName)
+		if err != nil {
+			return ast.TypeDefinitionStatement{}, err
+		}
+
+		return ast.TypeDefinitionStatement{Name: typeName, TypeParameters: typeParameters, Type: aliasedType, IsAlias: true}, nil
+	}
+
+	if typeTokens[0].Type != token.START_BLOCK {
+		unionType, err := parseUnionType(typeName, typeTokens)
+		if err != nil {
+			return ast.TypeDefinitionStatement{}, err
+		}
+
+		unionType.TypeParameters = typeParameters
+		return ast.TypeDefinitionStatement{Name: typeName, TypeParameters: typeParameters, Type: unionType}, nil
+	}
+
+	if len(typeParameters) != 0 {
+		return ast.TypeDefinitionStatement{}, fmt.Errorf("Error on line %v: Only union types can have type parameters", line)
+	}
+
+	fieldTokens, isRecord, indexAfter := getParenthesisContent(typeTokens, 0, token.START_BLOCK, token.END_BLOCK)
+	if !isRecord || indexAfter != len(typeTokens) {
+		return ast.TypeDefinitionStatement{}, fmt.Errorf("Error on line %v: Expected record type like { x float, y float } after = in definition of type %s", line, typeName)
+	}
+
+	fields, err := parseListOftVariables(removeTokenFromTokenSlice(fieldTokens, token.NEWLINE))
+	if err != nil {
+		return ast.TypeDefinitionStatement{}, err
+	}
+
+	if len(fields) == 0 {
+		return ast.TypeDefinitionStatement{}, fmt.Errorf("Error on line %v: Record type %s must have at least one field", line, typeName)
+	}
+
+	recordType := types.RecordType{Name: typeName, Fields: make([]types.RecordField, 0)}
+	for i := 0; i < len(fields); i++ {
+		if fields[i].Type.String() == types.NONE {
+			return ast.TypeDefinitionStatement{}, fmt.Errorf("Error on line %v: Field %s in record type %s must have a type", line, fields[i].Identifier, typeName)
+		}
+
+		recordType.Fields = append(recordType.Fields, types.RecordField{Name: fields[i].Identifier, Type: fields[i].Type})
+	}
+
+	return ast.TypeDefinitionStatement{Name: typeName, Type: recordType}, nil
+}
+
+// Type definitions with a type literal after =, like type BinOp = (int, int) -> (int), define type aliases, and so do
+// type names, like type Position = Point. A union type with one variant holding no values starts with |, like type A = | B,
+// so a misspelled type name is not taken as the name of a variant
+func isTypeAlias(tokens []token.Token) bool {
+	if tokens[0].Type == token.VARIABLE {
+		return len(tokens) == getTypeNameLength(tokens, 0) || tokens[1].Type == token.LESS_THEN
+	}
+
+	return tokens[0].Type != token.START_BLOCK && tokens[0].Type != token.BIT_OR
+}
+
+func parseTypeInDefinition(tokens []token.Token, typeName string) (types.Type, error) {
+	parsedType, indexAfter, isValidType, err := parseTypeLiteral(tokens, 0)
+	if err != nil {
+		return parsedType, err
+	}
+
+	if !isValidType {
+		return parsedType, errors.NewSyntaxErrorUnexpectedToken(tokens[0].Line, tokens[0].Literal, "type")
+	}
+
+	if indexAfter != len(tokens) {
+		return parsedType, fmt.Errorf("Error on line %v: Unexpected %s after the type in definition of type %s", tokens[indexAfter].Line, tokens[indexAfter].Literal, typeName)
+	}
+
+	return parsedType, nil
+}
+
+// A record expression is a type name followed by field assignments in {}: Point { x = 1.0, y = 2.0 }
+func isRecordExpression(tokens []token.Token, i int) bool {
+	typeNameLength := getTypeNameLength(tokens, i)
+	if typeNameLength == 0 || i+typeNameLength >= len(tokens) {
+		return false
+	}
+
+	isBlock, _ := skipParenthesis(tokens, token.START_BLOCK, token.END_BLOCK, i+typeNameLength)
+	return isBlock
+}
+
+// Gives the number of tokens in the type name starting at index i, and 0 if no name starts there. Types defined in
+// other modules are written with the name of the import before the name of the type: vector.Vec
+func getTypeNameLength(tokens []token.Token, i int) int {
+	if i >= len(tokens) || tokens[i].Type != token.VARIABLE {
+		return 0
+	}
+
+	if i+2 < len(tokens) && tokens[i+1].Type == token.DOT && tokens[i+2].Type == token.VARIABLE {
+		return 3
+	}
+
+	return 1
+}
+
+func getTypeName(tokens []token.Token, i int) string {
+	name := ""
+	for j := i; j < i+getTypeNameLength(tokens, i); j++ {
+		name += tokens[j].Literal
+	}
+
+	return name
+}
+
+func parseRecordExpression(tokens []token.Token) (ast.RecordExpression, error) {
+	fieldTokens, isRecord, indexAfter := getParenthesisContent(tokens, getTypeNameLength(tokens, 0), token.START_BLOCK, token.END_BLOCK)
+	if !isRecord || indexAfter != len(tokens) {
+		return ast.RecordExpression{}, errors.NewSyntaxErrorUnexpectedToken(tokens[len(tokens)-1].Line, tokens[len(tokens)-1].Literal, "end of record expression")
+	}
+
+	fieldNames, fieldValues, err := parseFieldAssignments(fieldTokens)
+	if err != nil {
+		return ast.RecordExpression{}, err
+	}
+
+	return ast.RecordExpression{
+		Type:        types.AnyType{Name: getTypeName(tokens, 0)},
+		FieldNames:  fieldNames,
+		FieldValues: fieldValues,
+	}, nil
+}
+
+// Parses expressions like: p with { x = 1.0 }
+func parseRecordUpdateExpression(tokens []token.Token, withPos int) (ast.RecordUpdateExpression, error) {
+	if withPos == 0 {
+		return ast.RecordUpdateExpression{}, errors.NewSyntaxErrorUnexpectedToken(tokens[0].Line, token.WITH, "record before with")
+	}
+
+	fieldTokens, isBlock, indexAfter := getParenthesisContent(tokens, withPos+1, token.START_BLOCK, token.END_BLOCK)
+	if !isBlock || indexAfter != len(tokens) {
+		return ast.RecordUpdateExpression{}, fmt.Errorf("Error on line %v: Expected field assignments in {} after with", tokens[withPos].Line)
+	}
+
+	record, err := parseExpression(tokens[:withPos])
+	if err != nil {
+		return ast.RecordUpdateExpression{}, err
+	}
+
+	fieldNames, fieldValues, err := parseFieldAssignments(fieldTokens)
+	if err != nil {
+		return ast.RecordUpdateExpression{}, err
+	}
+
+	return ast.RecordUpdateExpression{
+		Type:        types.StandardType{Name: types.NONE},
+		Record:      record,
+		FieldNames:  fieldNames,
+		FieldValues: fieldValues,
+	}, nil
+}
+
+// Parses expressions like: p.x
+func parseFieldAccessExpression(tokens []token.Token) (ast.FieldAccessExpression, error) {
+	record, err := parseExpression(tokens[:len(tokens)-2])
+	if err != nil {
+		return ast.FieldAccessExpression{}, err
+	}
+
+	return ast.FieldAccessExpression{
+		Type:   types.StandardType{Name: types.NONE},
+		Record: record,
+		Field:  tokens[len(tokens)-1].Literal,
+	}, nil
+}
+
+func isFieldAccessExpression(tokens []token.Token) bool {
+	return len(tokens) >= 3 && tokens[len(tokens)-2].Type == token.DOT && tokens[len(tokens)-1].Type == token.VARIABLE
+}
+
+// Parses field assignments separated by comma, like: x = 1.0, y = 2.0
+func parseFieldAssignments(tokens []token.Token) ([]string, []ast.Node, error) {
+	fieldNames := make([]string, 0)
+	fieldValues := make([]ast.Node, 0)
+
+	tokens = removeNewlinesOutsideBlocks(tokens)
+	if len(tokens) == 0 {
+		return fieldNames, fieldValues, nil
+	}
+
+	assignments := splitTokenSliceByComma(tokens)
+	for i := 0; i < len(assignments); i++ {
+		if len(assignments[i]) < 3 || assignments[i][0].Type != token.VARIABLE || assignments[i][1].Type != token.ASSIGN_VARIABLE {
+			line := tokens[0].Line
+			if len(assignments[i]) != 0 {
+				line = assignments[i][0].Line
+			}
+
+			return fieldNames, fieldValues, fmt.Errorf("Error on line %v: Expected field assignment like x = 1.0", line)
+		}
+
+		value, err := parseExpression(assignments[i][2:])
+		if err != nil {
+			return fieldNames, fieldValues, err
+		}
+
+		fieldNames = append(fieldNames, assignments[i][0].Literal)
+		fieldValues = append(fieldValues, value)
+	}
+
+	return fieldNames, fieldValues, nil
+}
+
+// Newlines in function bodies separate statements and must be kept
+func removeNewlinesOutsideBlocks(tokens []token.Token) []token.Token {
+	outputTokens := make([]token.Token, 0)
+	depth := 0
+
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].Type == token.START_BLOCK {
+			depth++
+		}
+
+		if tokens[i].Type == token.END_BLOCK {
+			depth--
+		}
+
+		if depth == 0 && tokens[i].Type == token.NEWLINE {
+			continue
+		}
+
+		outputTokens = append(outputTokens, tokens[i])
+	}
+
+	return outputTokens
+}
@@ -22,9 +22,116 @@ func parseTypeLiteral(tokens []token.Token, i int) (types.Type, int, bool, error
 		return functionType, indexAfter, isFunctionType, err
 	}
 
+	if tupleType, indexAfter, isTupleType, err := parseTupleTypeLiteral(tokens, i); err != nil || isTupleType {
+		return tupleType, indexAfter, isTupleType, err
+	}
+
+	if namedType, indexAfter, isNamedType, err := parseTypeParameterLiteral(tokens, i); err != nil || isNamedType {
+		return namedType, indexAfter, isNamedType, err
+	}
+
 	return types.StandardType{}, i, false, nil
 }
 
+//Type parameters of generic functions and types defined with type definitions are written as names. The validator checks that the name is defined.
+//Only union types can have type arguments, so names with type arguments are parsed as union types
+func parseTypeParameterLiteral(tokens []token.Token, i int) (types.Type, int, bool, error) {
+	typeNameLength := getTypeNameLength(tokens, i)
+	if typeNameLength == 0 {
+		return types.StandardType{}, i, false, nil
+	}
+
+	typeName := getTypeName(tokens, i)
+	typeArguments, indexAfter, hasTypeArguments, err := parseTypeArguments(tokens, i+typeNameLength)
+	if err != nil {
+		return types.StandardType{}, i, false, err
+	}
+
+	if hasTypeArguments {
+		return types.UnionType{Name: typeName, TypeArguments: typeArguments}, indexAfter, true, nil
+	}
+
+	return types.AnyType{Name: typeName}, i + typeNameLength, true, nil
+}
+
+//Parses type arguments like <int, []float>. The lexer reads >> and >>> as shift operators, so they can end more than one list of type arguments
+func parseTypeArguments(tokens []token.Token, i int) ([]types.Type, int, bool, error) {
+	if i >= len(tokens) || tokens[i].Type != token.LESS_THEN {
+		return []types.Type{}, i, false, nil
+	}
+
+	depth := 0
+	for j := i; j < len(tokens); j++ {
+		closedLists := 0
+		switch tokens[j].Type {
+		case token.LESS_THEN:
+			depth++
+		case token.GREATER_THEN:
+			closedLists = 1
+		case token.SHIFT_RIGHT:
+			closedLists = 2
+		case token.SHIFT_RIGHT_UNSIGNED:
+			closedLists = 3
+		}
+
+		if closedLists == 0 {
+			continue
+		}
+
+		if closedLists > depth {
+			return []types.Type{}, i, false, errors.NewSyntaxErrorUnexpectedToken(tokens[j].Line, tokens[j].Literal, token.GREATER_THEN)
+		}
+
+		if closedLists < depth {
+			depth -= closedLists
+			continue
+		}
+
+		//The lists inside this list closed by the last token get their own >
+		argumentTokens := append([]token.Token{}, tokens[i+1:j]...)
+		for k := 1; k < closedLists; k++ {
+			argumentTokens = append(argumentTokens, token.New(token.GREATER_THEN, token.GREATER_THEN, tokens[j].Line))
+		}
+
+		typeArguments, err := getTypeArguments(argumentTokens, tokens[i].Line)
+		if err != nil {
+			return []types.Type{}, i, false, err
+		}
+
+		return typeArguments, j + 1, true, nil
+	}
+
+	return []types.Type{}, i, false, errors.NewSyntaxErrorUnexpectedToken(tokens[len(tokens)-1].Line, "end of type", token.GREATER_THEN)
+}
+
+func getTypeArguments(tokens []token.Token, line int) ([]types.Type, error) {
+	typeArguments := make([]types.Type, 0)
+	if len(tokens) == 0 {
+		return typeArguments, errors.NewGeneralError(line, "Expected type arguments between < and >")
+	}
+
+	for i := 0; i < len(tokens); {
+		typeArgument, indexAfter, isValidType, err := parseTypeLiteral(tokens, i)
+		if err != nil {
+			return typeArguments, err
+		}
+
+		if !isValidType {
+			return typeArguments, errors.NewSyntaxErrorUnexpectedToken(tokens[i].Line, tokens[i].Literal, "type argument")
+		}
+
+		typeArguments = append(typeArguments, typeArgument)
+
+		if indexAfter < len(tokens) && tokens[indexAfter].Type != token.COMMA {
+			return typeArguments, errors.NewSyntaxErrorUnexpectedToken(tokens[indexAfter].Line, tokens[indexAfter].Literal, token.COMMA)
+		}
+
+		i = indexAfter + 1
+	}
+
+	return typeArguments, nil
+}
+
 func parseStandardTypeLiteral(tokens []token.Token, i int) (types.Type, int, bool, error) {
 	if i >= len(tokens) {
 		return types.StandardType{}, i, false, nil
@@ -68,7 +175,7 @@ func parseFunctionTypeLiteral(tokens []token.Token, curTokensIndex int) (types.F
 		return types.FunctionType{}, curTokensIndex, false, nil
 	}
 
-	if tokens[curTokensIndex].Type != token.FUNCTION_ARROW {
+	if curTokensIndex >= len(tokens) || tokens[curTokensIndex].Type != token.FUNCTION_ARROW {
 		return types.FunctionType{}, curTokensIndex, false, nil
 	}
 	curTokensIndex++
@@ -94,6 +201,29 @@ func parseFunctionTypeLiteral(tokens []token.Token, curTokensIndex int) (types.F
 	return outputFunctionType, curTokensIndex, true, nil
 }
 
+//Types in parenthesis not followed by -> are tuple types like (int, float). A single type in parenthesis is the type itself
+func parseTupleTypeLiteral(tokens []token.Token, i int) (types.Type, int, bool, error) {
+	tokensInParenthesis, isValidParenthesis, indexAfter := getParenthesisContent(tokens, i, token.LEFT_PARENTHESIS, token.RIGHT_PARENTHESIS)
+	if !isValidParenthesis {
+		return types.StandardType{}, i, false, nil
+	}
+
+	if len(tokensInParenthesis) == 0 {
+		return types.StandardType{}, i, false, errors.NewGeneralError(tokens[i].Line, "Expected types in tuple type")
+	}
+
+	elementTypes, err := getTypesSeparatedByComma(tokensInParenthesis)
+	if err != nil {
+		return types.StandardType{}, i, false, err
+	}
+
+	if len(elementTypes) == 1 {
+		return elementTypes[0], indexAfter, true, nil
+	}
+
+	return types.TupleType{ElementTypes: elementTypes}, indexAfter, true, nil
+}
+
 func getTypesSeparatedByComma(tokens []token.Token) ([]types.Type, error) {
 	outputTypes := make([]types.Type, 0)
 
@@ -214,6 +344,7 @@ func getParenthesisContent(tokens []token.Token, i int, leftParenthesis, rightPa
 func splitTokenSliceByComma(tokens []token.Token) [][]token.Token {
 	output := make([][]token.Token, 0)
 	curSlice := make([]token.Token, 0)
+	letDepth := 0 //Commas between let and in separate the values bound by the let
 
 	for i := 0; i < len(tokens); i++ {
 		parenthesisContent, isParenthesisStart, indexAfter := getParenthesisContent(tokens, i, token.LEFT_PARENTHESIS, token.RIGHT_PARENTHESIS)
@@ -237,10 +368,17 @@ func splitTokenSliceByComma(tokens []token.Token) [][]token.Token {
 			continue
 		}
 
-		if tokens[i].Type == token.COMMA {
-			output = append(output, curSlice)
-			curSlice = make([]token.Token, 0)
-			continue
+		switch tokens[i].Type {
+		case token.LET:
+			letDepth++
+		case token.IN:
+			letDepth--
+		case token.COMMA:
+			if letDepth == 0 {
+				output = append(output, curSlice)
+				curSlice = make([]token.Token, 0)
+				continue
+			}
 		}
 
 		curSlice = append(curSlice, tokens[i])
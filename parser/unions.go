@@ -0,0 +1,194 @@
+package parser
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/token"
+	"compiler/types"
+	"fmt"
+)
+
+// Parses the variants of a union type separated by |, like: Circle float | Rect float float
+// The variants can start with |, which is needed for a single variant holding no values: | Empty
+func parseUnionType(typeName string, tokens []token.Token) (types.UnionType, error) {
+	unionType := types.UnionType{Name: typeName, Variants: make([]types.UnionVariant, 0)}
+	if tokens[0].Type == token.BIT_OR && len(tokens) > 1 {
+		tokens = tokens[1:]
+	}
+
+	for _, variantTokens := range splitTokensAtDepthZero(tokens, token.BIT_OR) {
+		if len(variantTokens) == 0 {
+			return types.UnionType{}, fmt.Errorf("Error on line %v: Expected variant name between | in definition of type %s", tokens[0].Line, typeName)
+		}
+
+		if variantTokens[0].Type != token.VARIABLE {
+			return types.UnionType{}, errors.NewSyntaxErrorUnexpectedToken(variantTokens[0].Line, variantTokens[0].Literal, "variant name")
+		}
+
+		variant := types.UnionVariant{Name: variantTokens[0].Literal, Types: make([]types.Type, 0)}
+		for i := 1; i < len(variantTokens); {
+			variantType, indexAfter, isValidType, err := parseTypeLiteral(variantTokens, i)
+			if err != nil {
+				return types.UnionType{}, err
+			}
+
+			if !isValidType {
+				return types.UnionType{}, errors.NewSyntaxErrorUnexpectedToken(variantTokens[i].Line, variantTokens[i].Literal, "type")
+			}
+
+			variant.Types = append(variant.Types, variantType)
+			i = indexAfter
+		}
+
+		unionType.Variants = append(unionType.Variants, variant)
+	}
+
+	return unionType, nil
+}
+
+// Parses match expressions like: match s { Circle r -> r * r, Rect w h -> w * h }
+// The arms are separated by newlines or commas, and _ matches all variants not matched before it
+func parseMatchExpression(tokens []token.Token) (ast.MatchExpression, error) {
+	if tokens[len(tokens)-1].Type != token.END_BLOCK {
+		return ast.MatchExpression{}, errors.NewSyntaxErrorUnexpectedToken(tokens[len(tokens)-1].Line, tokens[len(tokens)-1].Literal, "arms in {} at the end of match expression")
+	}
+
+	//Finding the { starting the arms by going backwards from the last }
+	armsStart := len(tokens) - 1
+	for depth := 0; armsStart > 0; armsStart-- {
+		if tokens[armsStart].Type == token.END_BLOCK {
+			depth++
+		}
+
+		if tokens[armsStart].Type == token.START_BLOCK {
+			depth--
+		}
+
+		if depth == 0 {
+			break
+		}
+	}
+
+	if armsStart <= 1 {
+		return ast.MatchExpression{}, fmt.Errorf("Error on line %v: Expected value to match between match and {", tokens[0].Line)
+	}
+
+	value, err := parseExpression(tokens[1:armsStart])
+	if err != nil {
+		return ast.MatchExpression{}, err
+	}
+
+	arms := make([]ast.MatchArm, 0)
+	for _, armTokens := range splitTokensAtDepthZero(tokens[armsStart+1:len(tokens)-1], token.NEWLINE, token.COMMA) {
+		if len(armTokens) == 0 {
+			continue
+		}
+
+		arm, err := parseMatchArm(armTokens)
+		if err != nil {
+			return ast.MatchExpression{}, err
+		}
+
+		arms = append(arms, arm)
+	}
+
+	if len(arms) == 0 {
+		return ast.MatchExpression{}, fmt.Errorf("Error on line %v: Match expression must have at least one arm", tokens[0].Line)
+	}
+
+	return ast.MatchExpression{
+		Type:        types.StandardType{Name: types.NONE},
+		Value:       value,
+		Arms:        arms,
+		ReturnTypes: []types.Type{},
+	}, nil
+}
+
+// Parses an arm like: Rect w _ -> w * 2.0
+func parseMatchArm(tokens []token.Token) (ast.MatchArm, error) {
+	arrowPos := -1
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].Type == token.FUNCTION_ARROW {
+			arrowPos = i
+			break
+		}
+	}
+
+	if arrowPos == -1 {
+		return ast.MatchArm{}, fmt.Errorf("Error on line %v: Expected -> after the pattern in match arm", tokens[0].Line)
+	}
+
+	if arrowPos == 0 {
+		return ast.MatchArm{}, errors.NewSyntaxErrorUnexpectedToken(tokens[0].Line, token.FUNCTION_ARROW, "pattern")
+	}
+
+	if arrowPos+1 >= len(tokens) {
+		return ast.MatchArm{}, fmt.Errorf("Error on line %v: Expected expression after -> in match arm", tokens[arrowPos].Line)
+	}
+
+	expression, err := parseExpression(tokens[arrowPos+1:])
+	if err != nil {
+		return ast.MatchArm{}, err
+	}
+
+	arm := ast.MatchArm{Bindings: make([]ast.Variable, 0), Expression: expression}
+
+	if tokens[0].Type == token.WILDCARD {
+		if arrowPos != 1 {
+			return ast.MatchArm{}, errors.NewSyntaxErrorUnexpectedToken(tokens[1].Line, tokens[1].Literal, token.FUNCTION_ARROW)
+		}
+
+		return arm, nil
+	}
+
+	if tokens[0].Type != token.VARIABLE {
+		return ast.MatchArm{}, errors.NewSyntaxErrorUnexpectedToken(tokens[0].Line, tokens[0].Literal, "variant name")
+	}
+
+	arm.Variant = tokens[0].Literal
+	for i := 1; i < arrowPos; i++ {
+		if tokens[i].Type != token.VARIABLE && tokens[i].Type != token.WILDCARD {
+			return ast.MatchArm{}, errors.NewSyntaxErrorUnexpectedToken(tokens[i].Line, tokens[i].Literal, "variable name")
+		}
+
+		arm.Bindings = append(arm.Bindings, ast.Variable{Identifier: tokens[i].Literal, Type: types.StandardType{Name: types.NONE}})
+	}
+
+	return arm, nil
+}
+
+// Splits the tokens at the separators not inside parenthesis, blocks or arrays
+func splitTokensAtDepthZero(tokens []token.Token, separators ...string) [][]token.Token {
+	output := make([][]token.Token, 0)
+	curSlice := make([]token.Token, 0)
+	depth := 0
+
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i].Type {
+		case token.LEFT_PARENTHESIS, token.START_BLOCK, token.START_ARRAY:
+			depth++
+		case token.RIGHT_PARENTHESIS, token.END_BLOCK, token.END_ARRAY:
+			depth--
+		}
+
+		if depth == 0 && isInStringList(tokens[i].Type, separators) {
+			output = append(output, curSlice)
+			curSlice = make([]token.Token, 0)
+			continue
+		}
+
+		curSlice = append(curSlice, tokens[i])
+	}
+
+	return append(output, curSlice)
+}
+
+func isInStringList(s string, list []string) bool {
+	for i := 0; i < len(list); i++ {
+		if list[i] == s {
+			return true
+		}
+	}
+
+	return false
+}
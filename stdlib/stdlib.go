@@ -0,0 +1,12 @@
+package stdlib
+
+import "embed"
+
+// The standard library is written in Waffle and embedded in the compiler. Every .waf file in the prelude directory is
+// added to every program, so the standard library is extended by adding Waffle files there. Globals of the prelude are
+// only compiled if the program uses them.
+//
+//go:embed prelude/*.waf
+var Prelude embed.FS
+
+const PreludeDirectory = "prelude"
@@ -2,76 +2,206 @@ package symbolTable
 
 import (
 	"compiler/types"
+	"fmt"
+	"strings"
 )
 
 type SymbolController struct {
 	NumGlobals    int32
 	NumFunctions  int32
 	globalScope   *symbolTable
-	functionScope *symbolTableStack
+	functionScope *functionScopeStack
+
+	//Globals are stored with the path of the module defining them before the name, so every module has its own globals
+	module        string
+	sharedGlobals map[string]string
+
+	//The validator forbids shadowing. The compiler uses the names checked by the validator, where locals can have the
+	//names of globals of other modules
+	forbidsShadowing bool
 }
 
 func NewSymbolController() *SymbolController {
 	return &SymbolController{
-		globalScope: &symbolTable{
-			store:          make(map[string]Symbol),
-			numDefinitions: 0,
-		},
-		functionScope: &symbolTableStack{
-			stack:        make([]*symbolTable, 0),
+		globalScope: newSymbolTable(),
+		functionScope: &functionScopeStack{
+			stack:        make([]*functionScope, 0),
 			stackPointer: -1,
 		},
+		sharedGlobals: make(map[string]string),
+	}
+}
+
+//Gives the name a global defined in the module is stored with. Globals of the main file keep their names
+func GetModuleGlobalName(module string, name string) string {
+	if module == "" {
+		return name
+	}
+
+	return module + "." + name
+}
+
+//Sets the module that globals are defined in and resolved from
+func (s *SymbolController) SetModule(module string) {
+	s.module = module
+}
+
+func (s *SymbolController) GetGlobalName(name string) string {
+	return GetModuleGlobalName(s.module, name)
+}
+
+//Makes the globals defined in the current module available in every module by their names, like the functions of
+//the prelude. Globals defined in a module are used instead of shared globals with the same name
+func (s *SymbolController) ShareModuleGlobals() {
+	prefix := s.GetGlobalName("")
+	for storedName := range s.globalScope.store {
+		if strings.HasPrefix(storedName, prefix) {
+			s.sharedGlobals[strings.TrimPrefix(storedName, prefix)] = storedName
+		}
 	}
 }
 
+//Resolves a global by the name it is stored with, like math/vector.dot
+func (s *SymbolController) ResolveGlobal(qualifiedName string) (Symbol, bool) {
+	return s.globalScope.Resolve(qualifiedName)
+}
+
 func (s *SymbolController) DefineAnonymousFunction() int {
 	s.NumFunctions++
 	return int(s.NumFunctions) - 1
 }
 
-//Defines variable in global scope if no symbol tables on function stack and in current local scope if there exists a function symbol table on the function stack
+func (s *SymbolController) DefineAnonymousGlobal() int {
+	s.NumGlobals++
+	return int(s.NumGlobals) - 1
+}
+
+//Defines variable in global scope if no function is on the function stack, and in the innermost block of the current
+//function if there is. Functions are stored in the table of functions and all other values as global values
 func (s *SymbolController) DefineVariable(variableName string, variableType types.Type) (Symbol, int) {
-	currentLocalSymbolTable, isInFunction := s.functionScope.getCur()
+	currentFunctionScope, isInFunction := s.functionScope.getCur()
 	if isInFunction {
-		return currentLocalSymbolTable.Define(variableName, variableType, currentLocalSymbolTable.numDefinitions)
+		return currentFunctionScope.define(variableName, variableType)
 	}
 
-	switch variableType.(type) {
-	case types.StandardType:
-		symbol, _ := s.globalScope.Define(variableName, variableType, s.NumGlobals)
-		s.NumGlobals++
-		return symbol, int(s.NumGlobals) - 1
-	case types.FunctionType:
-		symbol, _ := s.globalScope.Define(variableName, variableType, s.NumFunctions)
+	if _, isFunction := variableType.(types.FunctionType); isFunction {
+		symbol := s.globalScope.Define(s.GetGlobalName(variableName), variableType, s.NumFunctions)
 		s.NumFunctions++
 		return symbol, int(s.NumFunctions) - 1
 	}
 
-	return Symbol{}, -1
+	symbol := s.globalScope.Define(s.GetGlobalName(variableName), variableType, s.NumGlobals)
+	s.NumGlobals++
+	return symbol, int(s.NumGlobals) - 1
+}
+
+//Makes arguments and variables defined with DefineUniqueVariable give an error when they shadow another variable
+func (s *SymbolController) ForbidShadowing() {
+	s.forbidsShadowing = true
+}
+
+//Defines a local variable that can not shadow another variable, like the names in match arms. Let uses DefineVariable,
+//as it is the only way to shadow variables
+func (s *SymbolController) DefineUniqueVariable(variableName string, variableType types.Type) (Symbol, int, error) {
+	currentFunctionScope, isInFunction := s.functionScope.getCur()
+	if !isInFunction {
+		return Symbol{}, -1, fmt.Errorf("Internal error: variable %s defined outside of a function", variableName)
+	}
+
+	err := s.checkShadowing(currentFunctionScope, variableName)
+	if err != nil {
+		return Symbol{}, -1, err
+	}
+
+	symbol, index := currentFunctionScope.define(variableName, variableType)
+	return symbol, index, nil
+}
+
+//Names must be different from the variables of the function and the globals of the module. Globals shared by the
+//prelude can be shadowed, so adding a global to the prelude does not break programs using its name
+func (s *SymbolController) checkShadowing(scope *functionScope, variableName string) error {
+	if !s.forbidsShadowing {
+		return nil
+	}
+
+	if _, isDefined := scope.resolve(variableName); isDefined {
+		return fmt.Errorf("Name %s is already the name of a variable. Use another name, or let to shadow the variable", variableName)
+	}
+
+	if _, isGlobal := s.globalScope.Resolve(s.GetGlobalName(variableName)); isGlobal {
+		return fmt.Errorf("Name %s is already the name of a global. Use another name, or let to shadow the global", variableName)
+	}
+
+	return nil
+}
+
+func (s *SymbolController) IsInFunction() bool {
+	_, isInFunction := s.functionScope.getCur()
+	return isInFunction
 }
 
-func (s *SymbolController) PushFunction(arguments []Variable) {
-	s.functionScope.push(newFunctionSymbolTable(arguments))
+//Adds a function with the given arguments, which can not shadow each other or globals
+func (s *SymbolController) PushFunction(arguments []Variable) error {
+	scope := &functionScope{blocks: []*symbolTable{newSymbolTable()}}
+	s.functionScope.push(scope)
+
+	for i := 0; i < len(arguments); i++ {
+		_, _, err := s.DefineUniqueVariable(arguments[i].Identifier, arguments[i].Type)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (s *SymbolController) PopFunction() {
 	s.functionScope.pop()
 }
 
+//Adds a block to the current function. Variables defined in the block can only be resolved until the block is popped,
+//and are resolved before variables with the same names in the blocks around it
+func (s *SymbolController) PushBlock() {
+	if currentFunctionScope, isInFunction := s.functionScope.getCur(); isInFunction {
+		currentFunctionScope.blocks = append(currentFunctionScope.blocks, newSymbolTable())
+	}
+}
+
+func (s *SymbolController) PopBlock() {
+	if currentFunctionScope, isInFunction := s.functionScope.getCur(); isInFunction && len(currentFunctionScope.blocks) > 1 {
+		currentFunctionScope.blocks = currentFunctionScope.blocks[:len(currentFunctionScope.blocks)-1]
+	}
+}
+
 func (s *SymbolController) Resolve(variableName string) (symbol Symbol, exists bool, isGlobal bool) {
-	functionScope, isInFunction := s.functionScope.getCur()
+	currentFunctionScope, isInFunction := s.functionScope.getCur()
 	if isInFunction {
-		symbol, exists := functionScope.Resolve(variableName)
+		symbol, exists := currentFunctionScope.resolve(variableName)
 		if exists {
 			return symbol, true, false
 		}
 	}
 
-	symbol, exists = s.globalScope.Resolve(variableName)
+	symbol, exists = s.globalScope.Resolve(s.GetGlobalName(variableName))
 	if exists {
 		return symbol, true, true
 	}
 
+	if sharedName, isShared := s.sharedGlobals[variableName]; isShared {
+		symbol, exists = s.globalScope.Resolve(sharedName)
+		if exists {
+			return symbol, true, true
+		}
+	}
+
+	//Other globals of other modules are only used by their stored names, since names in the code can not contain dots
+	if strings.Contains(variableName, ".") {
+		symbol, exists = s.globalScope.Resolve(variableName)
+		if exists {
+			return symbol, true, true
+		}
+	}
+
 	return Symbol{}, false, false
 }
 
@@ -82,8 +212,7 @@ type Symbol struct {
 }
 
 type symbolTable struct {
-	store          map[string]Symbol
-	numDefinitions int32
+	store map[string]Symbol
 }
 
 type Variable struct {
@@ -91,24 +220,14 @@ type Variable struct {
 	Type       types.Type
 }
 
-func newFunctionSymbolTable(arguments []Variable) *symbolTable {
-	table := &symbolTable{
-		store:          make(map[string]Symbol),
-		numDefinitions: 0,
-	}
-
-	for i := 0; i < len(arguments); i++ {
-		table.Define(arguments[i].Identifier, arguments[i].Type, table.numDefinitions)
-	}
-
-	return table
+func newSymbolTable() *symbolTable {
+	return &symbolTable{store: make(map[string]Symbol)}
 }
 
-func (s *symbolTable) Define(name string, symbolType types.Type, index int32) (Symbol, int) {
-	symbol := Symbol{Name: name, Index: int32(index), Type: symbolType}
+func (s *symbolTable) Define(name string, symbolType types.Type, index int32) Symbol {
+	symbol := Symbol{Name: name, Index: index, Type: symbolType}
 	s.store[name] = symbol
-	s.numDefinitions++
-	return symbol, int(index)
+	return symbol
 }
 
 func (s *symbolTable) Resolve(name string) (Symbol, bool) {
@@ -116,29 +235,52 @@ func (s *symbolTable) Resolve(name string) (Symbol, bool) {
 	return symbol, ok
 }
 
-type symbolTableStack struct {
-	stack        []*symbolTable
+//The variables of a function are stored in blocks, where the first block holds the arguments and the variables of the
+//function body. Every variable defined in the function is given a new local index, also when it shadows another variable
+type functionScope struct {
+	blocks         []*symbolTable
+	numDefinitions int32
+}
+
+func (f *functionScope) define(name string, symbolType types.Type) (Symbol, int) {
+	symbol := f.blocks[len(f.blocks)-1].Define(name, symbolType, f.numDefinitions)
+	f.numDefinitions++
+	return symbol, int(symbol.Index)
+}
+
+func (f *functionScope) resolve(name string) (Symbol, bool) {
+	for i := len(f.blocks) - 1; i >= 0; i-- {
+		if symbol, isDefined := f.blocks[i].Resolve(name); isDefined {
+			return symbol, true
+		}
+	}
+
+	return Symbol{}, false
+}
+
+type functionScopeStack struct {
+	stack        []*functionScope
 	stackPointer int
 }
 
-func (s *symbolTableStack) getCur() (*symbolTable, bool) {
+func (s *functionScopeStack) getCur() (*functionScope, bool) {
 	if s.stackPointer >= len(s.stack) || s.stackPointer < 0 {
-		return &symbolTable{}, false
+		return &functionScope{}, false
 	}
 
 	return s.stack[s.stackPointer], true
 }
 
-func (s *symbolTableStack) push(table *symbolTable) {
+func (s *functionScopeStack) push(scope *functionScope) {
 	s.stackPointer++
 	if s.stackPointer >= len(s.stack) {
-		s.stack = append(s.stack, table)
+		s.stack = append(s.stack, scope)
 		return
 	}
 
-	s.stack[s.stackPointer] = table
+	s.stack[s.stackPointer] = scope
 }
 
-func (s *symbolTableStack) pop() {
+func (s *functionScopeStack) pop() {
 	s.stackPointer--
 }
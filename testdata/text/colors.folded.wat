@@ -0,0 +1,182 @@
+(module
+  (type (;0;) (func (param i32) (result i32)))
+  (type (;1;) (func (param i32) (result i32)))
+  (type (;2;) (func (result i32)))
+  (type (;3;) (func))
+  (func $value (;0;) (type 0) (param $c i32) (result i32)
+    (local i32)
+    (local.set 1
+      (local.get $c))
+    (return
+      (block (type 2)
+        (block
+          (block
+            (block
+              (br_table 0 1 2 2
+                (i32.load align=1
+                  (local.get 1))))
+            (i32.const 1)
+            (br 2))
+          (i32.const 2)
+          (br 1))
+        (i32.const 4)
+        (br 0))))
+  (func $main (;1;) (type 1) (param $n i32) (result i32)
+    (local $total i32)
+    (local.set $total
+      (i32.add
+        (call_indirect (type 0)
+          (global.get $Green)
+          (i32.const 0))
+        (local.get $n)))
+    (return
+      (i32.mul
+        (local.get $total)
+        (global.get $offset))))
+  (func $start_function (;2;) (type 3)
+    (local i32)
+    (local i32)
+    (local i32)
+    (local.set 0
+      (call_indirect (type 1)
+        (i32.const 4)
+        (i32.const 3)))
+    (i32.store align=1
+      (local.get 0)
+      (i32.const 0))
+    (global.set $Red
+      (local.get 0))
+    (local.set 1
+      (call_indirect (type 1)
+        (i32.const 4)
+        (i32.const 3)))
+    (i32.store align=1
+      (local.get 1)
+      (i32.const 1))
+    (global.set $Green
+      (local.get 1))
+    (local.set 2
+      (call_indirect (type 1)
+        (i32.const 4)
+        (i32.const 3)))
+    (i32.store align=1
+      (local.get 2)
+      (i32.const 2))
+    (global.set $Blue
+      (local.get 2)))
+  (func $allocate (;3;) (type 1) (param i32) (result i32)
+    (local i32)
+    (local i32)
+    (local i32)
+    (local i32)
+    (local.set 4
+      (i32.const 0))
+    (block
+      (loop
+        (if
+          (i32.eq
+            (i32.load align=4
+              (local.get 4))
+            (i32.const 0))
+          (then
+            (if
+              (i32.eq
+                (i32.load align=4
+                  (i32.add
+                    (local.get 4)
+                    (i32.const 4)))
+                (i32.const 0))
+              (then
+                (br 3)))
+            (if
+              (i32.le_u
+                (local.get 0)
+                (i32.load align=4
+                  (i32.add
+                    (local.get 4)
+                    (i32.const 4))))
+              (then
+                (br 3)))))
+        (local.set 4
+          (i32.add
+            (i32.add
+              (local.get 4)
+              (i32.load align=4
+                (i32.add
+                  (local.get 4)
+                  (i32.const 4))))
+            (i32.const 8)))
+        (br 0)))
+    (i32.store align=4
+      (local.get 4)
+      (i32.const 1))
+    (local.set 3
+      (i32.load align=4
+        (i32.add
+          (local.get 4)
+          (i32.const 4))))
+    (if
+      (i32.eq
+        (local.get 3)
+        (i32.const 0))
+      (then
+        (i32.store align=4
+          (i32.add
+            (local.get 4)
+            (i32.const 4))
+          (local.get 0))
+        (return
+          (i32.add
+            (local.get 4)
+            (i32.const 8)))))
+    (if
+      (i32.gt_u
+        (i32.add
+          (local.get 0)
+          (i32.const 24))
+        (i32.load align=4
+          (i32.add
+            (local.get 4)
+            (i32.const 4))))
+      (then
+        (return
+          (i32.add
+            (local.get 4)
+            (i32.const 8)))))
+    (i32.store align=4
+      (i32.add
+        (local.get 4)
+        (i32.const 4))
+      (local.get 0))
+    (local.set 1
+      (i32.add
+        (i32.add
+          (local.get 0)
+          (local.get 4))
+        (i32.const 8)))
+    (i32.store align=4
+      (local.get 1)
+      (i32.const 0))
+    (i32.store align=4
+      (i32.add
+        (local.get 1)
+        (i32.const 4))
+      (i32.sub
+        (i32.sub
+          (local.get 3)
+          (local.get 0))
+        (i32.const 8)))
+    (return
+      (i32.add
+        (local.get 4)
+        (i32.const 8))))
+  (table (;0;) 4 4 funcref)
+  (memory (;0;) 1 1)
+  (global $Red (;0;) (mut i32) (i32.const 0))
+  (global $Green (;1;) (mut i32) (i32.const 0))
+  (global $Blue (;2;) (mut i32) (i32.const 0))
+  (global $offset (;3;) i32 (i32.const 3))
+  (export "value" (func $value))
+  (export "main" (func $main))
+  (start $start_function)
+  (elem (;0;) (table 0) (i32.const 0) func $value $main $start_function $allocate))
@@ -0,0 +1,188 @@
+(module
+  (type (;0;) (func (param i32) (result i32)))
+  (type (;1;) (func (param i32) (result i32)))
+  (type (;2;) (func (result i32)))
+  (type (;3;) (func))
+  (func $value (;0;) (type 0) (param $c i32) (result i32)
+    (local i32)
+    local.get $c
+    local.set 1
+    block (type 2)
+      block
+        block
+          block
+            local.get 1
+            i32.load align=1
+            br_table 0 1 2 2
+          end
+          i32.const 1
+          br 2
+        end
+        i32.const 2
+        br 1
+      end
+      i32.const 4
+      br 0
+    end
+    return)
+  (func $main (;1;) (type 1) (param $n i32) (result i32)
+    (local $total i32)
+    global.get $Green
+    i32.const 0
+    call_indirect (type 0)
+    local.get $n
+    i32.add
+    local.set $total
+    local.get $total
+    global.get $offset
+    i32.mul
+    return)
+  (func $start_function (;2;) (type 3)
+    (local i32)
+    (local i32)
+    (local i32)
+    i32.const 4
+    i32.const 3
+    call_indirect (type 1)
+    local.set 0
+    local.get 0
+    i32.const 0
+    i32.store align=1
+    local.get 0
+    global.set $Red
+    i32.const 4
+    i32.const 3
+    call_indirect (type 1)
+    local.set 1
+    local.get 1
+    i32.const 1
+    i32.store align=1
+    local.get 1
+    global.set $Green
+    i32.const 4
+    i32.const 3
+    call_indirect (type 1)
+    local.set 2
+    local.get 2
+    i32.const 2
+    i32.store align=1
+    local.get 2
+    global.set $Blue)
+  (func $allocate (;3;) (type 1) (param i32) (result i32)
+    (local i32)
+    (local i32)
+    (local i32)
+    (local i32)
+    i32.const 0
+    local.set 4
+    block
+      loop
+        local.get 4
+        i32.load align=4
+        i32.const 0
+        i32.eq
+        if
+          local.get 4
+          i32.const 4
+          i32.add
+          i32.load align=4
+          i32.const 0
+          i32.eq
+          if
+            br 3
+          end
+          local.get 0
+          local.get 4
+          i32.const 4
+          i32.add
+          i32.load align=4
+          i32.le_u
+          if
+            br 3
+          end
+        end
+        local.get 4
+        local.get 4
+        i32.const 4
+        i32.add
+        i32.load align=4
+        i32.add
+        i32.const 8
+        i32.add
+        local.set 4
+        br 0
+      end
+    end
+    local.get 4
+    i32.const 1
+    i32.store align=4
+    local.get 4
+    i32.const 4
+    i32.add
+    i32.load align=4
+    local.set 3
+    local.get 3
+    i32.const 0
+    i32.eq
+    if
+      local.get 4
+      i32.const 4
+      i32.add
+      local.get 0
+      i32.store align=4
+      local.get 4
+      i32.const 8
+      i32.add
+      return
+    end
+    local.get 0
+    i32.const 24
+    i32.add
+    local.get 4
+    i32.const 4
+    i32.add
+    i32.load align=4
+    i32.gt_u
+    if
+      local.get 4
+      i32.const 8
+      i32.add
+      return
+    end
+    local.get 4
+    i32.const 4
+    i32.add
+    local.get 0
+    i32.store align=4
+    local.get 0
+    local.get 4
+    i32.add
+    i32.const 8
+    i32.add
+    local.set 1
+    local.get 1
+    i32.const 0
+    i32.store align=4
+    local.get 1
+    i32.const 4
+    i32.add
+    local.get 3
+    local.get 0
+    i32.sub
+    i32.const 8
+    i32.sub
+    i32.store align=4
+    local.get 4
+    i32.const 8
+    i32.add
+    return)
+  (table (;0;) 4 4 funcref)
+  (memory (;0;) 1 1)
+  (global $Red (;0;) (mut i32) (i32.const 0))
+  (global $Green (;1;) (mut i32) (i32.const 0))
+  (global $Blue (;2;) (mut i32) (i32.const 0))
+  (global $offset (;3;) i32 (i32.const 3))
+  (export "value" (func $value))
+  (export "main" (func $main))
+  (start $start_function)
+  (elem (;0;) (table 0) (i32.const 0) func $value $main $start_function $allocate))
@@ -27,6 +27,8 @@ const (
 	STRING     = "string"
 	FLOAT      = "float"
 	INT        = "int"
+	LONG       = "long"
+	DOUBLE     = "double"
 	BOOL       = "bool"
 	TRUE       = "true"
 	FALSE      = "false"
@@ -37,6 +39,17 @@ const (
 	ASSIGN_VARIABLE  = "="
 	FUNCTION_ARROW   = "->"
 	EXECUTE_FUNCTION = "!"
+	TYPE_DEFINITION  = "type"
+	NEWTYPE          = "newtype"
+	IMPORT           = "import"
+	WITH             = "with"
+	DOT              = "."
+	REST             = "..."
+	MATCH            = "match"
+	LET              = "let"
+	IN               = "in"
+	WHERE            = "where"
+	WILDCARD         = "_"
 
 	COMMA = ","
 
@@ -44,6 +57,17 @@ const (
 	MINUS = "-"
 	MULT  = "*"
 	DIV   = "/"
+	MOD   = "%"
+
+	BIT_AND              = "&"
+	BIT_OR               = "|"
+	BIT_XOR              = "^"
+	SHIFT_LEFT           = "<<"
+	SHIFT_RIGHT          = ">>"
+	SHIFT_RIGHT_UNSIGNED = ">>>"
+
+	NOT        = "not"
+	COMPLEMENT = "~"
 
 	EQUAL                 = "=="
 	NOT_EQUAL             = "!="
@@ -71,6 +95,8 @@ var TypeTokens []string = []string{
 	STRING,
 	FLOAT,
 	INT,
+	LONG,
+	DOUBLE,
 	BOOL,
 }
 
@@ -81,17 +107,28 @@ var TokenLiterals []string = []string{
 	NEWLINE,
 
 	COMMA,
+	REST,
+	DOT,
+	WILDCARD,
 	FUNCTION_ARROW,
 
 	PLUS,
 	MINUS,
 	MULT,
 	DIV,
+	MOD,
+	COMPLEMENT,
 
 	EQUAL,
 	NOT_EQUAL,
 	OR,
 	AND,
+	BIT_AND,
+	BIT_OR,
+	BIT_XOR,
+	SHIFT_RIGHT_UNSIGNED,
+	SHIFT_RIGHT,
+	SHIFT_LEFT,
 	EQUAL_OR_GREATER_THEN,
 	GREATER_THEN,
 	EQUAL_OR_LESS_THEN,
@@ -114,6 +151,15 @@ var Operators []string = []string{
 	MINUS,
 	MULT,
 	DIV,
+	MOD,
+	BIT_AND,
+	BIT_OR,
+	BIT_XOR,
+	SHIFT_LEFT,
+	SHIFT_RIGHT,
+	SHIFT_RIGHT_UNSIGNED,
+	AND,
+	OR,
 	EQUAL,
 	NOT_EQUAL,
 	LESS_THEN,
@@ -121,3 +167,22 @@ var Operators []string = []string{
 	EQUAL_OR_GREATER_THEN,
 	EQUAL_OR_LESS_THEN,
 }
+
+// Keywords are only lexed as keywords when they are not the start of a longer identifier
+var Keywords []string = []string{
+	NOT,
+	TYPE_DEFINITION,
+	NEWTYPE,
+	IMPORT,
+	WITH,
+	MATCH,
+	LET,
+	IN,
+	WHERE,
+}
+
+var PrefixOperators []string = []string{
+	MINUS,
+	NOT,
+	COMPLEMENT,
+}
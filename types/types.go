@@ -28,6 +28,10 @@ func (t StandardType) ByteCode() uint8 {
 		return code.I32
 	case token.FLOAT:
 		return code.F32
+	case token.LONG:
+		return code.I64
+	case token.DOUBLE:
+		return code.F64
 	case token.BOOL:
 		return code.I32
 	default:
@@ -90,6 +94,124 @@ func (t ArrayType) ByteCode() uint8 {
 	return code.I32
 }
 
+type RecordType struct {
+	Name   string
+	Fields []RecordField
+}
+
+type RecordField struct {
+	Name string
+	Type Type
+}
+
+func (p RecordType) node() {}
+
+func (t RecordType) String() string {
+	return t.Name
+}
+
+func (t RecordType) ByteCode() uint8 {
+	return code.I32
+}
+
+// Returns the index of the field and false if the record has no field with the name
+func (t RecordType) GetField(name string) (int, bool) {
+	for i := 0; i < len(t.Fields); i++ {
+		if t.Fields[i].Name == name {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
+
+// Union types are tagged unions where every variant has a name and the types of the values it holds. Union types used
+// as the type of a value only need the name and type arguments, the variants are looked up from the type definition when needed.
+type UnionType struct {
+	Name           string
+	TypeParameters []string
+	TypeArguments  []Type
+	Variants       []UnionVariant
+}
+
+type UnionVariant struct {
+	Name  string
+	Types []Type
+}
+
+func (p UnionType) node() {}
+
+func (t UnionType) String() string {
+	if len(t.TypeArguments) == 0 {
+		return t.Name
+	}
+
+	output := t.Name + "<"
+	for i := 0; i < len(t.TypeArguments); i++ {
+		output += t.TypeArguments[i].String()
+		if i+1 != len(t.TypeArguments) {
+			output += ", "
+		}
+	}
+
+	return output + ">"
+}
+
+func (t UnionType) ByteCode() uint8 {
+	return code.I32
+}
+
+// Returns the index of the variant, which is also the tag of the variant, and false if the union has no variant with the name
+func (t UnionType) GetVariant(name string) (int, bool) {
+	for i := 0; i < len(t.Variants); i++ {
+		if t.Variants[i].Name == name {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
+
+// Tuples hold a fixed number of values that can have different types. They are stored in memory like records
+type TupleType struct {
+	ElementTypes []Type
+}
+
+func (p TupleType) node() {}
+
+func (t TupleType) String() string {
+	output := "("
+	for i := 0; i < len(t.ElementTypes); i++ {
+		output += t.ElementTypes[i].String()
+		if i+1 != len(t.ElementTypes) {
+			output += ", "
+		}
+	}
+
+	return output + ")"
+}
+
+func (t TupleType) ByteCode() uint8 {
+	return code.I32
+}
+
+// Types defined with newtype hold a value of another type, but can not be used where that type is expected.
+// The validator replaces them with the type they hold after type checking
+type NewType struct {
+	Name string
+	Type Type
+}
+
+func (p NewType) node() {}
+
+func (t NewType) String() string {
+	return t.Name
+}
+
+func (t NewType) ByteCode() uint8 {
+	return t.Type.ByteCode()
+}
+
 type AnyType struct {
 	Name string
 }
@@ -97,7 +219,7 @@ type AnyType struct {
 func (p AnyType) node() {}
 
 func (t AnyType) String() string {
-	return "any" + t.Name
+	return t.Name
 }
 
 func (t AnyType) ByteCode() uint8 {
@@ -108,6 +230,8 @@ func (t AnyType) ByteCode() uint8 {
 const (
 	INT    = "int"
 	FLOAT  = "float"
+	LONG   = "long"
+	DOUBLE = "double"
 	STRING = "string"
 	BOOL   = "bool"
 	NONE   = "NONE"
@@ -116,6 +240,8 @@ const (
 var ValidTypes []string = []string{
 	INT,
 	FLOAT,
+	LONG,
+	DOUBLE,
 	STRING,
 	BOOL,
 }
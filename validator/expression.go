@@ -2,6 +2,7 @@ package validator
 
 import (
 	"compiler/ast"
+	"compiler/errors"
 	"compiler/symbolTable"
 	"compiler/token"
 	"compiler/types"
@@ -15,13 +16,23 @@ func (v *validator) validateExpression(expression ast.Node) (ast.Node, []types.T
 	case ast.IfExpression:
 		return v.validateIfExpression(e)
 	case ast.ExecuteFunctionExpression:
+		if isUnwrapFunctionVariable(e.Function, v.symbolController) {
+			return v.validateUnwrapExpression(e)
+		}
+
 		return v.validateExecuteFunctionExpression(e)
 	case ast.OperatorExpression:
 		return v.validateOperatorExpression(e)
+	case ast.UnaryOperatorExpression:
+		return v.validateUnaryOperatorExpression(e)
 	case ast.IntExpression:
 		return v.validateLiteral(e, token.INT)
 	case ast.FloatExpression:
 		return v.validateLiteral(e, token.FLOAT)
+	case ast.LongExpression:
+		return v.validateLiteral(e, token.LONG)
+	case ast.DoubleExpression:
+		return v.validateLiteral(e, token.DOUBLE)
 	case ast.BoolExpression:
 		return v.validateLiteral(e, token.BOOL)
 	case ast.StringExpression:
@@ -30,12 +41,50 @@ func (v *validator) validateExpression(expression ast.Node) (ast.Node, []types.T
 		return v.validateVariable(e)
 	case ast.ArrayExpression:
 		return v.validateArrayExpression(e)
+	case ast.RecordExpression:
+		return v.validateRecordExpression(e)
+	case ast.RecordUpdateExpression:
+		return v.validateRecordUpdateExpression(e)
+	case ast.FieldAccessExpression:
+		importedGlobal, isImportedGlobal, err := v.getImportedGlobal(e)
+		if err != nil {
+			return expression, []types.Type{}, err
+		}
+
+		if isImportedGlobal {
+			return v.validateVariable(importedGlobal)
+		}
+
+		return v.validateFieldAccessExpression(e)
+	case ast.VariantExpression:
+		return v.validateVariantExpression(e)
+	case ast.MatchExpression:
+		return v.validateMatchExpression(e)
+	case ast.LetExpression:
+		return v.validateLetExpression(e)
+	case ast.TupleExpression:
+		return v.validateTupleExpression(e)
+	case ast.DestructureExpression:
+		return v.validateDestructureExpression(e)
+	case ast.NewTypeExpression:
+		return v.validateNewTypeExpression(e)
 	}
 
 	return expression, []types.Type{}, fmt.Errorf("Node given to validate expression not valid in expression")
 }
 
 func (v *validator) validateDefineFunctionExpression(function ast.DefineFunctionExpression) (ast.DefineFunctionExpression, []types.Type, error) {
+	if len(function.TypeParameters) != 0 && (v.symbolController.IsInFunction() || len(v.typeParameters) == 0) {
+		return ast.DefineFunctionExpression{}, []types.Type{}, fmt.Errorf("Generic functions can only be defined in the global scope")
+	}
+
+	function, err := v.resolveFunctionDefinitionTypes(function)
+	if err != nil {
+		return ast.DefineFunctionExpression{}, []types.Type{}, err
+	}
+
+	function = v.addArgumentTypeVariables(function)
+
 	argumentVariables := make([]symbolTable.Variable, 0)
 	for i := 0; i < len(function.Arguments); i++ {
 		argumentVariables = append(argumentVariables, symbolTable.Variable{
@@ -44,7 +93,11 @@ func (v *validator) validateDefineFunctionExpression(function ast.DefineFunction
 		})
 	}
 
-	v.symbolController.PushFunction(argumentVariables)
+	err = v.symbolController.PushFunction(argumentVariables)
+	if err != nil {
+		return ast.DefineFunctionExpression{}, []types.Type{}, err
+	}
+
 	validated, returnStatementsExpressionsTypes, err := v.validateBlockStatement(function.FunctionBody, true)
 	if err != nil {
 		return ast.DefineFunctionExpression{}, []types.Type{}, err
@@ -53,8 +106,8 @@ func (v *validator) validateDefineFunctionExpression(function ast.DefineFunction
 	returnTypes := make([]types.Type, 0)
 
 	if function.NoReturnTypesSpecified {
-		if len(returnStatementsExpressionsTypes) != 1 {
-			return ast.DefineFunctionExpression{}, []types.Type{}, fmt.Errorf("Function with no specified return type must have one and only one return statement")
+		if len(returnStatementsExpressionsTypes) == 0 {
+			return ast.DefineFunctionExpression{}, []types.Type{}, fmt.Errorf("Function with no specified return types must have a return statement")
 		}
 
 		returnTypes = returnStatementsExpressionsTypes[0]
@@ -62,6 +115,13 @@ func (v *validator) validateDefineFunctionExpression(function ast.DefineFunction
 		returnTypes = function.ReturnTypes
 	}
 
+	for i := 0; i < len(returnStatementsExpressionsTypes); i++ {
+		if v.unifyLists(returnTypes, returnStatementsExpressionsTypes[i]) != nil {
+			shown := v.typesForError(returnStatementsExpressionsTypes[i], returnTypes)
+			return ast.DefineFunctionExpression{}, []types.Type{}, fmt.Errorf("Return statement returning %s does not match the function return types %s", typeListString(shown[0]), typeListString(shown[1]))
+		}
+	}
+
 	function.FunctionBody = validated
 
 	functionType := types.FunctionType{ArgumentTypes: VariablesToTypeList(function.Arguments), ReturnTypes: returnTypes}
@@ -71,6 +131,18 @@ func (v *validator) validateDefineFunctionExpression(function ast.DefineFunction
 	return function, []types.Type{functionType}, nil
 }
 
+// Gives arguments without types new type variables and updates the function type to use them
+func (v *validator) addArgumentTypeVariables(function ast.DefineFunctionExpression) ast.DefineFunctionExpression {
+	for i := 0; i < len(function.Arguments); i++ {
+		if function.Arguments[i].Type.String() == types.NONE {
+			function.Arguments[i].Type = v.newTypeVariable()
+		}
+	}
+
+	function.FunctionType.ArgumentTypes = VariablesToTypeList(function.Arguments)
+	return function
+}
+
 func (v *validator) validateExecuteFunctionExpression(expression ast.ExecuteFunctionExpression) (ast.ExecuteFunctionExpression, []types.Type, error) {
 	argumentReturnTypes := make([]types.Type, 0)
 
@@ -94,95 +166,84 @@ func (v *validator) validateExecuteFunctionExpression(expression ast.ExecuteFunc
 		return ast.ExecuteFunctionExpression{}, []types.Type{}, fmt.Errorf("No function after function execution symbol")
 	}
 
-	functionType, isFunction := functionExpressionTypes[0].(types.FunctionType)
+	functionExpressionType := v.resolveType(functionExpressionTypes[0])
+	if isTypeVariable(functionExpressionType) { //A variable of unknown type that is executed is a function returning one value
+		inferredFunctionType := types.FunctionType{ArgumentTypes: argumentReturnTypes, ReturnTypes: []types.Type{v.newTypeVariable()}}
+		err := v.unify(functionExpressionType, inferredFunctionType)
+		if err != nil {
+			return ast.ExecuteFunctionExpression{}, []types.Type{}, err
+		}
+
+		functionExpressionType = inferredFunctionType
+	}
+
+	functionType, isFunction := functionExpressionType.(types.FunctionType)
 	if !isFunction {
 		return ast.ExecuteFunctionExpression{}, []types.Type{}, fmt.Errorf("Expression after function execution symbol does not return function")
 	}
 
-	anyTypeToRealType, err := validateFunctionExecutionTypes(argumentReturnTypes, functionType.ArgumentTypes)
+	//Generic global functions are instantiated when validating the variable. Any types in the types of local variables are type parameters of the function being validated, which must not be instantiated
+	instantiatedFunctionType, anyTypeToTypeVariable := functionType, map[string]types.Type{}
+	if isStandardFunctionVariable(expression.Function, v.symbolController) {
+		instantiatedFunctionType, anyTypeToTypeVariable = v.instantiate(functionType)
+	}
+
+	err = v.validateFunctionExecutionTypes(argumentReturnTypes, instantiatedFunctionType.ArgumentTypes)
 	if err != nil {
 		return ast.ExecuteFunctionExpression{}, []types.Type{}, err
 	}
 
-	returnTypes := make([]types.Type, 0)
-	for i := 0; i < len(functionType.ReturnTypes); i++ {
-		curReturnType, err := insertAnyTypeRealType(functionType.ReturnTypes[i], anyTypeToRealType)
-		if err != nil {
-			return ast.ExecuteFunctionExpression{}, []types.Type{}, fmt.Errorf("No function after function execution symbol")
-		}
-		returnTypes = append(returnTypes, curReturnType)
+	err = v.validateStandardFunctionConstraints(expression.Function, anyTypeToTypeVariable)
+	if err != nil {
+		return ast.ExecuteFunctionExpression{}, []types.Type{}, err
 	}
 
+	returnTypes := v.resolveTypes(instantiatedFunctionType.ReturnTypes)
+
 	expression.ReturnTypes = returnTypes
 	return expression, returnTypes, nil
 }
 
-func insertAnyTypeRealType(returnType types.Type, anyTypeIdentifierToRealType map[string]types.Type) (types.Type, error) {
-	if returnTypeAnyType, isAnyType := returnType.(types.AnyType); isAnyType {
-		anyTypeRealType, ok := anyTypeIdentifierToRealType[returnTypeAnyType.Name]
+// Checks that the any types of a standard function are given types the standard function supports
+func (v *validator) validateStandardFunctionConstraints(function ast.Node, anyTypeIdentifierToRealType map[string]types.Type) error {
+	if !isStandardFunctionVariable(function, v.symbolController) {
+		return nil
+	}
+	functionVariable := function.(ast.Variable)
+
+	constraints := standardFunctionsAnyTypeConstraints[functionVariable.Identifier]
+	for anyTypeIdentifier, constraint := range constraints {
+		realType, ok := anyTypeIdentifierToRealType[anyTypeIdentifier]
 		if !ok {
-			return types.StandardType{}, fmt.Errorf("Any type with identifier %v not used in function definition", returnTypeAnyType.Name)
+			continue
 		}
 
-		return anyTypeRealType, nil
-	}
-
-	if returnTypeArrayType, isArrayType := returnType.(types.ArrayType); isArrayType {
-		arrayTypeElementRealType, err := insertAnyTypeRealType(returnTypeArrayType.ElementType, anyTypeIdentifierToRealType)
+		description := constraint.description
+		err := v.requireConstraint(realType, constraint, func(realType types.Type) error {
+			return fmt.Errorf("Function %s expects argument of type %s, got %s", functionVariable.Identifier, description, realType.String())
+		})
 		if err != nil {
-			return types.StandardType{}, err
+			return err
 		}
-
-		return types.ArrayType{ElementType: arrayTypeElementRealType}, nil
 	}
 
-	return returnType, nil
+	return nil
 }
 
-func validateFunctionExecutionTypes(argumentTypes []types.Type, expectedArgumentTypes []types.Type) (map[string]types.Type, error) {
+func (v *validator) validateFunctionExecutionTypes(argumentTypes []types.Type, expectedArgumentTypes []types.Type) error {
 	if len(argumentTypes) != len(expectedArgumentTypes) {
-		return map[string]types.Type{}, fmt.Errorf("Amount of arguments given to function does not match expected number of arguments")
+		return fmt.Errorf("Amount of arguments given to function does not match expected number of arguments")
 	}
 
-	anyTypeIdentifierToRealType := make(map[string]types.Type)
-
 	for i := 0; i < len(argumentTypes); i++ {
-		isEquivalent, newAnyTypeIdentifierToRealType := isActualTypeEquivalentToExpectedType(argumentTypes[i], expectedArgumentTypes[i])
-		if !isEquivalent {
-			return anyTypeIdentifierToRealType, fmt.Errorf("Argument %v does not match expected argument %v in function. Expected type: %v. Actual type: %v", i, i, argumentTypes[i].String(), expectedArgumentTypes[i].String())
-		}
-
-		for anyTypeIdentifier, curAnyTypeRealType := range newAnyTypeIdentifierToRealType {
-			curAnyTypeIdentifierToRealTypeValue, hasAnyTypePreviously := anyTypeIdentifierToRealType[anyTypeIdentifier]
-			if !hasAnyTypePreviously {
-				anyTypeIdentifierToRealType[anyTypeIdentifier] = curAnyTypeRealType
-				continue
-			}
-
-			if curAnyTypeIdentifierToRealTypeValue.String() != curAnyTypeRealType.String() {
-				return anyTypeIdentifierToRealType, fmt.Errorf("Argument %v does not match expected argument %v in function. Expected type: %v. Actual type: %v", i, i, argumentTypes[i].String(), expectedArgumentTypes[i].String())
-			}
-		}
-	}
-
-	return anyTypeIdentifierToRealType, nil
-}
-
-func isActualTypeEquivalentToExpectedType(actualType, expectedType types.Type) (bool, map[string]types.Type) {
-	if expectedTypeAnyType, expectedTypeIsAnyType := expectedType.(types.AnyType); expectedTypeIsAnyType {
-		return true, map[string]types.Type{expectedTypeAnyType.Name: actualType}
-	}
-
-	if expectedTypeArrayType, expectedTypeIsArrayType := expectedType.(types.ArrayType); expectedTypeIsArrayType {
-		actualTypeArraytype, actualTypeIsArrayType := actualType.(types.ArrayType)
-		if !actualTypeIsArrayType {
-			return false, make(map[string]types.Type)
+		err := v.unify(argumentTypes[i], expectedArgumentTypes[i])
+		if err != nil {
+			shown := v.typesForError([]types.Type{expectedArgumentTypes[i], argumentTypes[i]})[0]
+			return fmt.Errorf("Argument %v does not match expected argument %v in function. Expected type: %v. Actual type: %v", i, i, shown[0].String(), shown[1].String())
 		}
-
-		return isActualTypeEquivalentToExpectedType(actualTypeArraytype.ElementType, expectedTypeArrayType.ElementType)
 	}
 
-	return actualType.String() == expectedType.String(), make(map[string]types.Type)
+	return nil
 }
 
 func (v *validator) validateOperatorExpression(expression ast.OperatorExpression) (ast.OperatorExpression, []types.Type, error) {
@@ -200,60 +261,90 @@ func (v *validator) validateOperatorExpression(expression ast.OperatorExpression
 	expression.RightSide = rightSideValidated
 
 	if len(leftTypes) != 1 || len(rightTypes) != 1 {
-		return ast.OperatorExpression{}, []types.Type{}, generateOperatorError(expression.Operator, leftTypes, rightTypes)
+		shown := v.typesForError(leftTypes, rightTypes)
+		return ast.OperatorExpression{}, []types.Type{}, generateOperatorError(expression.Operator, shown[0], shown[1])
 	}
 
-	leftType, isStandardType := leftTypes[0].(types.StandardType)
-	if !isStandardType {
-		return ast.OperatorExpression{}, []types.Type{}, generateOperatorError(expression.Operator, leftTypes, rightTypes)
+	if v.unify(leftTypes[0], rightTypes[0]) != nil {
+		shown := v.typesForError(leftTypes, rightTypes)
+		return ast.OperatorExpression{}, []types.Type{}, generateOperatorError(expression.Operator, shown[0], shown[1])
 	}
 
-	rightType, isStandardType := rightTypes[0].(types.StandardType)
-	if !isStandardType {
-		return ast.OperatorExpression{}, []types.Type{}, generateOperatorError(expression.Operator, leftTypes, rightTypes)
+	operandType := v.resolveType(leftTypes[0])
+	constraint, returnsBool, isValidOperator := getOperatorConstraint(expression.Operator)
+	if !isValidOperator {
+		return expression, []types.Type{operandType}, fmt.Errorf("Operator in operator expression not valid")
 	}
 
-	if leftType.String() != rightType.String() {
-		return ast.OperatorExpression{}, []types.Type{}, generateOperatorError(expression.Operator, leftTypes, rightTypes)
+	err = v.requireConstraint(operandType, constraint, func(realType types.Type) error {
+		return generateOperatorError(expression.Operator, []types.Type{realType}, []types.Type{realType})
+	})
+	if err != nil {
+		return ast.OperatorExpression{}, []types.Type{}, err
 	}
 
-	if expression.Operator == token.AND || expression.Operator == token.OR {
-		if leftType.Name != token.BOOL {
-			return ast.OperatorExpression{}, []types.Type{}, generateOperatorError(expression.Operator, leftTypes, rightTypes)
-		}
-
-		expression.Type = leftTypes[0]
+	expression.Type = operandType
+	if returnsBool {
 		return expression, []types.Type{types.StandardType{Name: token.BOOL}}, nil
 	}
 
-	if expression.Operator == token.EQUAL || expression.Operator == token.NOT_EQUAL {
-		if leftType.Name == token.INT || leftType.Name == token.FLOAT || leftType.Name == token.BOOL {
-			expression.Type = leftType
-			return expression, []types.Type{types.StandardType{Name: token.BOOL}}, nil
-		}
+	return expression, []types.Type{operandType}, nil
+}
+
+// Gives the constraint on the operand types of the operator and if the operator returns bool instead of the operand type
+func getOperatorConstraint(operator string) (typeConstraint, bool, bool) {
+	switch {
+	case operator == token.AND || operator == token.OR:
+		return boolConstraint, true, true
+	case operator == token.EQUAL || operator == token.NOT_EQUAL:
+		return numberOrBoolConstraint, true, true
+	case isInList(operator, []string{token.GREATER_THEN, token.LESS_THEN, token.EQUAL_OR_GREATER_THEN, token.EQUAL_OR_LESS_THEN}):
+		return numberConstraint, true, true
+	case isInList(operator, []string{token.PLUS, token.MINUS, token.DIV, token.MULT}):
+		return numberConstraint, false, true
+	case isInList(operator, []string{token.BIT_AND, token.BIT_OR, token.BIT_XOR}):
+		return integerOrBoolConstraint, false, true
+	case isInList(operator, []string{token.MOD, token.SHIFT_LEFT, token.SHIFT_RIGHT, token.SHIFT_RIGHT_UNSIGNED}):
+		return integerConstraint, false, true
+	}
+
+	return typeConstraint{}, false, false
+}
 
-		return ast.OperatorExpression{}, []types.Type{}, generateOperatorError(expression.Operator, leftTypes, rightTypes)
+func (v *validator) validateUnaryOperatorExpression(expression ast.UnaryOperatorExpression) (ast.UnaryOperatorExpression, []types.Type, error) {
+	validated, expressionTypes, err := v.validateExpression(expression.Expression)
+	if err != nil {
+		return ast.UnaryOperatorExpression{}, []types.Type{}, err
 	}
 
-	if isInList(expression.Operator, []string{token.PLUS, token.MINUS, token.DIV, token.MULT}) {
-		if leftType.Name == token.INT || leftType.Name == token.FLOAT {
-			expression.Type = leftType
-			return expression, []types.Type{types.StandardType{Name: leftType.Name}}, nil
-		}
+	expression.Expression = validated
 
-		return ast.OperatorExpression{}, []types.Type{}, generateOperatorError(expression.Operator, leftTypes, rightTypes)
+	if len(expressionTypes) != 1 {
+		return ast.UnaryOperatorExpression{}, []types.Type{}, generateUnaryOperatorError(expression.Operator, v.typesForError(expressionTypes)[0])
 	}
 
-	if isInList(expression.Operator, []string{token.GREATER_THEN, token.LESS_THEN, token.EQUAL_OR_GREATER_THEN, token.EQUAL_OR_LESS_THEN}) {
-		if leftType.Name == token.INT || leftType.Name == token.FLOAT {
-			expression.Type = leftType
-			return expression, []types.Type{types.StandardType{Name: token.BOOL}}, nil
-		}
+	var constraint typeConstraint
+	switch expression.Operator {
+	case token.MINUS:
+		constraint = numberConstraint
+	case token.NOT:
+		constraint = boolConstraint
+	case token.COMPLEMENT:
+		constraint = integerConstraint
+	default:
+		return expression, []types.Type{}, fmt.Errorf("Operator in unary operator expression not valid")
+	}
 
-		return ast.OperatorExpression{}, []types.Type{}, generateOperatorError(expression.Operator, leftTypes, rightTypes)
+	expressionType := v.resolveType(expressionTypes[0])
+	err = v.requireConstraint(expressionType, constraint, func(realType types.Type) error {
+		return generateUnaryOperatorError(expression.Operator, []types.Type{realType})
+	})
+	if err != nil {
+		return ast.UnaryOperatorExpression{}, []types.Type{}, err
 	}
 
-	return expression, []types.Type{leftType}, fmt.Errorf("Operator in operator expression not valid")
+	expression.Type = expressionType
+	return expression, []types.Type{expressionType}, nil
 }
 
 func (v *validator) validateLiteral(expression ast.Node, literalType string) (ast.Node, []types.Type, error) {
@@ -261,7 +352,7 @@ func (v *validator) validateLiteral(expression ast.Node, literalType string) (as
 }
 
 func (v *validator) validateVariable(expression ast.Variable) (ast.Variable, []types.Type, error) {
-	variableSymbol, exists, _ := v.symbolController.Resolve(expression.Identifier)
+	variableSymbol, exists, isGlobal := v.symbolController.Resolve(expression.Identifier)
 	if !exists {
 		if standardFunctionType, isStandardFunction := standardFunctions[expression.Identifier]; isStandardFunction {
 			expression.Type = standardFunctionType
@@ -270,10 +361,44 @@ func (v *validator) validateVariable(expression ast.Variable) (ast.Variable, []t
 
 		return ast.Variable{}, []types.Type{}, fmt.Errorf("Identifier " + expression.Identifier + " is not defined")
 	}
+
+	//Globals are used by the names they are stored with, which include the module defining them
+	if isGlobal {
+		expression.Identifier = variableSymbol.Name
+	}
+
+	//The types of generalized global functions are resolved to their type parameters
+	if functionType, isFunction := v.resolveType(variableSymbol.Type).(types.FunctionType); isFunction && isGlobal && containsTypeParameter(functionType) {
+		instantiated, _ := v.instantiate(functionType)
+		expression.Type = instantiated
+		return expression, []types.Type{instantiated}, nil
+	}
+
+	//Variants of generic union types holding no values can be used as values of all instances of the union type
+	if isGlobal && containsTypeParameter(variableSymbol.Type) {
+		instantiated := v.instantiateType(variableSymbol.Type, make(map[string]types.Type))
+		expression.Type = instantiated
+		return expression, []types.Type{instantiated}, nil
+	}
+
 	expression.Type = variableSymbol.Type
 	return expression, []types.Type{variableSymbol.Type}, nil
 }
 
+func isStandardFunctionVariable(function ast.Node, symbolController *symbolTable.SymbolController) bool {
+	functionVariable, isVariable := function.(ast.Variable)
+	if !isVariable {
+		return false
+	}
+
+	if _, isDefined, _ := symbolController.Resolve(functionVariable.Identifier); isDefined {
+		return false
+	}
+
+	_, isStandardFunction := standardFunctions[functionVariable.Identifier]
+	return isStandardFunction
+}
+
 func (v *validator) validateArrayExpression(expression ast.ArrayExpression) (ast.ArrayExpression, []types.Type, error) {
 	if len(expression.ElementsExpressions) == 0 {
 		return expression, []types.Type{}, fmt.Errorf("Array literal with zero expressions not valid. Use make")
@@ -298,13 +423,15 @@ func (v *validator) validateArrayExpression(expression ast.ArrayExpression) (ast
 			continue
 		}
 
-		if curElementType[0].String() != arrayElementsType.String() {
-			return expression, []types.Type{}, fmt.Errorf("Can not add element of type %s to array of type []%s", curElementType[0].String(), arrayElementsType.String())
+		if v.unify(curElementType[0], arrayElementsType) != nil {
+			shown := v.typesForError([]types.Type{curElementType[0], arrayElementsType})[0]
+			return expression, []types.Type{}, fmt.Errorf("Can not add element of type %s to array of type []%s", shown[0].String(), shown[1].String())
 		}
 	}
 
-	expression.Type = types.ArrayType{ElementType: arrayElementsType}
-	return expression, []types.Type{types.ArrayType{ElementType: arrayElementsType}}, nil
+	arrayType := types.ArrayType{ElementType: v.resolveType(arrayElementsType)}
+	expression.Type = arrayType
+	return expression, []types.Type{arrayType}, nil
 }
 
 func (v *validator) validateIfExpression(expression ast.IfExpression) (ast.IfExpression, []types.Type, error) {
@@ -323,30 +450,58 @@ func (v *validator) validateIfExpression(expression ast.IfExpression) (ast.IfExp
 		return ast.IfExpression{}, []types.Type{}, err
 	}
 
-	if len(conditionReturnTypes) != 1 {
-		return ast.IfExpression{}, []types.Type{}, fmt.Errorf("Condition expression must return one value in if expression")
+	if len(conditionReturnTypes) != 1 || v.unify(conditionReturnTypes[0], types.StandardType{Name: token.BOOL}) != nil {
+		return ast.IfExpression{}, []types.Type{}, errors.NewGeneralError(expression.Line, fmt.Sprintf("The condition of if expression must give one bool, got %s", typesString(v.typesForError(conditionReturnTypes)[0])))
 	}
 
-	if conditionReturnTypes[0].String() != token.BOOL {
-		return ast.IfExpression{}, []types.Type{}, fmt.Errorf("Condition expression must return a value of type bool in if expression")
+	err = v.matchIfBranchTypes(expression, trueExpressionReturnTypes, falseExpressionReturnTypes)
+	if err != nil {
+		return ast.IfExpression{}, []types.Type{}, err
 	}
 
-	if len(trueExpressionReturnTypes) != len(falseExpressionReturnTypes) {
-		return ast.IfExpression{}, []types.Type{}, fmt.Errorf("Return values of true and false expressions in if expression must be the same ")
+	expression.ReturnType = v.resolveTypes(trueExpressionReturnTypes)
+	expression.Condition = conditionValidated
+	expression.TrueExpression = trueValidated
+	expression.FalseExpression = falseValidated
+
+	return expression, expression.ReturnType, nil
+}
+
+// The true and false branch must give the same number of values with the same types. The error gives the types of
+// both branches, and the first value where they differ when the branches give more than one value
+func (v *validator) matchIfBranchTypes(expression ast.IfExpression, trueTypes, falseTypes []types.Type) error {
+	shown := v.typesForError(trueTypes, falseTypes)
+	branches := fmt.Sprintf("the true branch on line %v gives %s and the false branch on line %v gives %s", expression.TrueLine, typesString(shown[0]), expression.FalseLine, typesString(shown[1]))
+
+	if len(trueTypes) != len(falseTypes) {
+		return errors.NewGeneralError(expression.Line, fmt.Sprintf("The branches of if expression give different numbers of values: %s", branches))
 	}
 
-	for i := 0; i < len(trueExpressionReturnTypes); i++ {
-		if trueExpressionReturnTypes[i].String() != falseExpressionReturnTypes[i].String() {
-			return ast.IfExpression{}, []types.Type{}, fmt.Errorf("Return values of true and false expressions in if expression must be the same ")
+	for i := 0; i < len(trueTypes); i++ {
+		if v.unify(trueTypes[i], falseTypes[i]) == nil {
+			continue
+		}
+
+		if len(trueTypes) == 1 {
+			return errors.NewGeneralError(expression.Line, fmt.Sprintf("The branches of if expression give different types: %s", branches))
 		}
+
+		return errors.NewGeneralError(expression.Line, fmt.Sprintf("The branches of if expression give different types for value %v: %s", i+1, branches))
 	}
 
-	expression.ReturnType = trueExpressionReturnTypes
-	expression.Condition = conditionValidated
-	expression.TrueExpression = trueValidated
-	expression.FalseExpression = falseValidated
+	return nil
+}
 
-	return expression, expression.ReturnType, nil
+func typesString(typeList []types.Type) string {
+	if len(typeList) == 1 {
+		return typeList[0].String()
+	}
+
+	if len(typeList) == 0 {
+		return "no values"
+	}
+
+	return typeListString(typeList)
 }
 
 func VariablesToTypeList(variables []ast.Variable) []types.Type {
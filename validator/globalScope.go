@@ -0,0 +1,341 @@
+package validator
+
+import (
+	"compiler/ast"
+	"fmt"
+)
+
+type globalStatementState int
+
+const (
+	statementNotValidated globalStatementState = iota
+	statementBeingValidated
+	statementValidated
+)
+
+// Functions in the global scope can be used before they are defined. Functions with specified return types are added
+// to the symbol controller before any statement is validated. Functions without specified return types get their
+// return types when validated, so they are validated before the first statement that uses them.
+func (v *validator) validateGlobalScope(block ast.BlockStatement) (ast.BlockStatement, error) {
+	err := v.addTypeDefinitions(block)
+	if err != nil {
+		return ast.BlockStatement{}, err
+	}
+
+	block, err = v.addConstructors(block)
+	if err != nil {
+		return ast.BlockStatement{}, err
+	}
+
+	err = v.addGlobalFunctionSignatures(block)
+	if err != nil {
+		return ast.BlockStatement{}, err
+	}
+
+	functionsWithoutSignature := make(map[string]int)
+	for i := 0; i < len(block.Statements); i++ {
+		if functionName, isFunction := getGlobalFunctionName(block.Statements[i]); isFunction && !v.isGlobalFunctionSignature(functionName, i) {
+			functionsWithoutSignature[functionName] = i
+		}
+	}
+
+	states := make([]globalStatementState, len(block.Statements))
+	for i := 0; i < len(block.Statements); i++ {
+		err := v.validateGlobalStatement(block.Statements, i, functionsWithoutSignature, states)
+		if err != nil {
+			return ast.BlockStatement{}, err
+		}
+	}
+
+	err = checkGlobalValueOrder(block.Statements)
+	if err != nil {
+		return ast.BlockStatement{}, err
+	}
+
+	// Types are inferred from the whole program, so type variables are replaced with the inferred types after all statements are validated
+	err = v.checkDeferredConstraints()
+	if err != nil {
+		return ast.BlockStatement{}, err
+	}
+
+	for i := 0; i < len(block.Statements); i++ {
+		resolved, err := v.resolveNode(block.Statements[i])
+		if err != nil {
+			return ast.BlockStatement{}, err
+		}
+
+		block.Statements[i] = resolved
+	}
+
+	return block, nil
+}
+
+// Validates the functions without signature used by the statement and then the statement
+func (v *validator) validateGlobalStatement(statements []ast.Node, i int, functionsWithoutSignature map[string]int, states []globalStatementState) error {
+	if states[i] == statementValidated {
+		return nil
+	}
+
+	if _, isTypeDefinition := statements[i].(ast.TypeDefinitionStatement); isTypeDefinition {
+		states[i] = statementValidated
+		return nil
+	}
+
+	assignStatement, isAssignStatement := statements[i].(ast.AssignmentStatement)
+	if !isAssignStatement {
+		return fmt.Errorf("Return statement in global scope")
+	}
+
+	states[i] = statementBeingValidated
+
+	functionName, _ := getGlobalFunctionName(assignStatement)
+	for _, identifier := range getUsedIdentifiers(assignStatement.Value) {
+		usedFunctionIndex, isFunctionWithoutSignature := functionsWithoutSignature[identifier]
+		if !isFunctionWithoutSignature || identifier == functionName {
+			continue
+		}
+
+		if states[usedFunctionIndex] == statementBeingValidated {
+			return fmt.Errorf("Function %s must have specified return types since it is part of a cycle of functions using each other", identifier)
+		}
+
+		err := v.validateGlobalStatement(statements, usedFunctionIndex, functionsWithoutSignature, states)
+		if err != nil {
+			return err
+		}
+	}
+
+	v.typeParameters = getTypeParameters(assignStatement)
+	defer func() { v.typeParameters = nil }()
+
+	if v.isGlobalFunctionSignature(functionName, i) {
+		validatedFunction, functionTypes, err := v.validateExpression(assignStatement.Value)
+		if err != nil {
+			return err
+		}
+
+		functionSymbol, _, _ := v.symbolController.Resolve(functionName)
+		err = v.unify(functionSymbol.Type, functionTypes[0])
+		if err != nil {
+			return fmt.Errorf("Function %s does not match its signature: %s", functionName, err.Error())
+		}
+
+		assignStatement.Value = validatedFunction
+		assignStatement.Variables[0].Type = functionTypes[0]
+		statements[i] = assignStatement
+		states[i] = statementValidated
+
+		return nil
+	}
+
+	validatedStatement, err := v.validateAssignmentStatement(assignStatement)
+	if err != nil {
+		return err
+	}
+
+	if _, isFunctionWithoutSignature := functionsWithoutSignature[functionName]; isFunctionWithoutSignature {
+		validatedStatement = v.generalizeGlobalFunction(statements, i, validatedStatement)
+	}
+
+	statements[i] = validatedStatement
+	states[i] = statementValidated
+
+	return nil
+}
+
+// Global values that are not constants are set in the order of their statements when the module is instantiated.
+// Functions can use globals defined before them, so a function called by the value of a global could use the global
+// itself or a global set after it, before they are set. The functions called by every value are followed to find them
+func checkGlobalValueOrder(statements []ast.Node) error {
+	functions := make(map[string]ast.Node)
+	setValues := make(map[string]int)
+	for i := 0; i < len(statements); i++ {
+		if functionName, isFunction := getGlobalFunctionName(statements[i]); isFunction {
+			functions[functionName] = statements[i].(ast.AssignmentStatement).Value
+			continue
+		}
+
+		assignStatement, isAssignStatement := statements[i].(ast.AssignmentStatement)
+		if !isAssignStatement || len(assignStatement.Variables) == 1 && isConstantValue(assignStatement.Value) {
+			continue
+		}
+
+		for j := 0; j < len(assignStatement.Variables); j++ {
+			setValues[assignStatement.Variables[j].Identifier] = i
+		}
+	}
+
+	for i := 0; i < len(statements); i++ {
+		assignStatement, isAssignStatement := statements[i].(ast.AssignmentStatement)
+		if _, isSet := setValues[getFirstVariable(assignStatement)]; !isAssignStatement || !isSet {
+			continue
+		}
+
+		calledFunctions := make(map[string]bool)
+		for _, calledFunction := range getFreeIdentifiers(assignStatement.Value, map[string]bool{}) {
+			if _, isFunction := functions[calledFunction]; !isFunction || calledFunctions[calledFunction] {
+				continue
+			}
+
+			calledFunctions[calledFunction] = true
+
+			//The functions used by the called function are called by the value as well
+			toVisit := []string{calledFunction}
+			for len(toVisit) != 0 {
+				functionName := toVisit[len(toVisit)-1]
+				toVisit = toVisit[:len(toVisit)-1]
+
+				for _, identifier := range getFreeIdentifiers(functions[functionName], map[string]bool{}) {
+					if valueIndex, isSet := setValues[identifier]; isSet && valueIndex == i {
+						return fmt.Errorf("Global %s is used by its own value, as it calls function %s which uses %s", identifier, calledFunction, identifier)
+					} else if isSet && valueIndex > i {
+						return fmt.Errorf("Global %s is used before it is set, as the value of %s calls function %s which uses %s", identifier, getFirstVariable(assignStatement), calledFunction, identifier)
+					}
+
+					if _, isFunction := functions[identifier]; isFunction && !calledFunctions[identifier] {
+						calledFunctions[identifier] = true
+						toVisit = append(toVisit, identifier)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func getFirstVariable(statement ast.AssignmentStatement) string {
+	if len(statement.Variables) == 0 {
+		return ""
+	}
+
+	return statement.Variables[0].Identifier
+}
+
+// Values given by literals are set in the global section, before any value is set by the start function
+func isConstantValue(expression ast.Node) bool {
+	switch expression.(type) {
+	case ast.IntExpression, ast.FloatExpression, ast.LongExpression, ast.DoubleExpression, ast.BoolExpression:
+		return true
+	}
+
+	return false
+}
+
+func (v *validator) addGlobalFunctionSignatures(block ast.BlockStatement) error {
+	for i := 0; i < len(block.Statements); i++ {
+		functionName, isFunction := getGlobalFunctionName(block.Statements[i])
+		if !isFunction {
+			continue
+		}
+
+		assignStatement := block.Statements[i].(ast.AssignmentStatement)
+		functionDefinition := assignStatement.Value.(ast.DefineFunctionExpression)
+		if functionDefinition.NoReturnTypesSpecified {
+			continue
+		}
+
+		if symbol, isDefined, _ := v.symbolController.Resolve(functionName); isDefined && symbol.Name == v.symbolController.GetGlobalName(functionName) {
+			return fmt.Errorf("Double declaration of %s in global scope", functionName)
+		}
+
+		v.typeParameters = functionDefinition.TypeParameters
+		functionDefinition, err := v.resolveFunctionDefinitionTypes(functionDefinition)
+		v.typeParameters = nil
+		if err != nil {
+			return err
+		}
+
+		functionDefinition = v.addArgumentTypeVariables(functionDefinition)
+		assignStatement.Value = functionDefinition
+		block.Statements[i] = assignStatement
+
+		v.symbolController.DefineVariable(functionName, functionDefinition.FunctionType)
+		v.globalFunctionSignatures[v.symbolController.GetGlobalName(functionName)] = i
+	}
+
+	return nil
+}
+
+// Checks if the function defined by the statement with the given index was added to the symbol controller before validation
+func (v *validator) isGlobalFunctionSignature(functionName string, statementIndex int) bool {
+	signatureStatementIndex, hasSignature := v.globalFunctionSignatures[v.symbolController.GetGlobalName(functionName)]
+	return hasSignature && signatureStatementIndex == statementIndex
+}
+
+// Gives the name of the function if the statement assigns a function definition to one variable
+func getGlobalFunctionName(statement ast.Node) (string, bool) {
+	assignStatement, isAssignStatement := statement.(ast.AssignmentStatement)
+	if !isAssignStatement || len(assignStatement.Variables) != 1 {
+		return "", false
+	}
+
+	if _, isFunctionDefinition := assignStatement.Value.(ast.DefineFunctionExpression); !isFunctionDefinition {
+		return "", false
+	}
+
+	return assignStatement.Variables[0].Identifier, true
+}
+
+// Gives the type parameters if the statement defines a generic function
+func getTypeParameters(statement ast.AssignmentStatement) []string {
+	if functionDefinition, isFunctionDefinition := statement.Value.(ast.DefineFunctionExpression); isFunctionDefinition {
+		return functionDefinition.TypeParameters
+	}
+
+	return nil
+}
+
+// Gives the used identifiers that are not defined by let expressions around them, which are the only local variables
+// that can have the name of a global
+func getFreeIdentifiers(expression ast.Node, letVariables map[string]bool) []string {
+	if variable, isVariable := expression.(ast.Variable); isVariable {
+		if letVariables[variable.Identifier] {
+			return []string{}
+		}
+
+		return []string{variable.Identifier}
+	}
+
+	letExpression, isLetExpression := expression.(ast.LetExpression)
+	if !isLetExpression {
+		identifiers := make([]string, 0)
+		childExpressions := expression.GetChildNodes()
+		for i := 0; i < len(childExpressions); i++ {
+			identifiers = append(identifiers, getFreeIdentifiers(childExpressions[i], letVariables)...)
+		}
+
+		return identifiers
+	}
+
+	//Every binding can use the variables of the bindings before it
+	innerVariables := make(map[string]bool)
+	for name := range letVariables {
+		innerVariables[name] = true
+	}
+
+	identifiers := make([]string, 0)
+	for i := 0; i < len(letExpression.Bindings); i++ {
+		identifiers = append(identifiers, getFreeIdentifiers(letExpression.Bindings[i].Value, innerVariables)...)
+
+		for j := 0; j < len(letExpression.Bindings[i].Variables); j++ {
+			innerVariables[letExpression.Bindings[i].Variables[j].Identifier] = true
+		}
+	}
+
+	return append(identifiers, getFreeIdentifiers(letExpression.Expression, innerVariables)...)
+}
+
+func getUsedIdentifiers(expression ast.Node) []string {
+	if variable, isVariable := expression.(ast.Variable); isVariable {
+		return []string{variable.Identifier}
+	}
+
+	identifiers := make([]string, 0)
+	childExpressions := expression.GetChildNodes()
+	for i := 0; i < len(childExpressions); i++ {
+		identifiers = append(identifiers, getUsedIdentifiers(childExpressions[i])...)
+	}
+
+	return identifiers
+}
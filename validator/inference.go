@@ -0,0 +1,878 @@
+package validator
+
+import (
+	"compiler/ast"
+	"compiler/types"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Type inference uses any types as type variables. Type variables created by the validator have names starting with
+// typeVariablePrefix, which can not be written in the source code. Any types with other names are generic types that
+// are given new type variables every time the function using them is executed.
+const typeVariablePrefix = "?"
+
+// Requirement on a type variable that could not be checked when the type variable was unknown
+type deferredConstraint struct {
+	typeVariable types.Type
+	constraint   typeConstraint
+	newError     func(realType types.Type) error
+}
+
+func (v *validator) newTypeVariable() types.AnyType {
+	v.numTypeVariables++
+	return types.AnyType{Name: typeVariablePrefix + strconv.Itoa(v.numTypeVariables)}
+}
+
+func isTypeVariable(t types.Type) bool {
+	anyType, isAnyType := t.(types.AnyType)
+	return isAnyType && strings.HasPrefix(anyType.Name, typeVariablePrefix)
+}
+
+// Replaces all type variables with known types with the known types
+func (v *validator) resolveType(t types.Type) types.Type {
+	switch resolved := t.(type) {
+	case types.AnyType:
+		if boundType, isBound := v.typeVariables[resolved.Name]; isBound {
+			return v.resolveType(boundType)
+		}
+
+	case types.ArrayType:
+		return types.ArrayType{ElementType: v.resolveType(resolved.ElementType)}
+
+	case types.FunctionType:
+		return types.FunctionType{
+			TypeIndex:     resolved.TypeIndex,
+			ArgumentTypes: v.resolveTypes(resolved.ArgumentTypes),
+			ReturnTypes:   v.resolveTypes(resolved.ReturnTypes),
+		}
+
+	case types.UnionType:
+		return mapUnionType(resolved, v.resolveTypes)
+
+	case types.TupleType:
+		return types.TupleType{ElementTypes: v.resolveTypes(resolved.ElementTypes)}
+	}
+
+	return t
+}
+
+func (v *validator) resolveTypes(typeList []types.Type) []types.Type {
+	resolved := make([]types.Type, 0)
+	for i := 0; i < len(typeList); i++ {
+		resolved = append(resolved, v.resolveType(typeList[i]))
+	}
+
+	return resolved
+}
+
+// Resolves the types shown in an error. Type variables that are still unknown are named a, b, c and so on in the order
+// they appear in the message, instead of with their internal names. Letters used by generic types in the message are
+// skipped, so the new names can not be confused with them
+func (v *validator) typesForError(typeLists ...[]types.Type) [][]types.Type {
+	resolved := make([][]types.Type, 0)
+	usedNames := make(map[string]bool)
+	for i := 0; i < len(typeLists); i++ {
+		resolved = append(resolved, v.resolveTypes(typeLists[i]))
+		for j := 0; j < len(typeLists[i]); j++ {
+			mapAnyTypes(resolved[i][j], func(anyType types.AnyType) types.Type {
+				usedNames[anyType.Name] = true
+				return anyType
+			})
+		}
+	}
+
+	names := make(map[string]string)
+	nextName := 0
+	renameTypeVariable := func(anyType types.AnyType) types.Type {
+		if !isTypeVariable(anyType) {
+			return anyType
+		}
+
+		if _, isNamed := names[anyType.Name]; !isNamed {
+			for usedNames[typeVariableName(nextName)] {
+				nextName++
+			}
+
+			names[anyType.Name] = typeVariableName(nextName)
+			nextName++
+		}
+
+		return types.AnyType{Name: names[anyType.Name]}
+	}
+
+	for i := 0; i < len(resolved); i++ {
+		for j := 0; j < len(resolved[i]); j++ {
+			resolved[i][j] = mapAnyTypes(resolved[i][j], renameTypeVariable)
+		}
+	}
+
+	return resolved
+}
+
+func (v *validator) typeForError(t types.Type) types.Type {
+	return v.typesForError([]types.Type{t})[0][0]
+}
+
+// Gives a, b, ..., z, then a1, b1 and so on
+func typeVariableName(index int) string {
+	name := string(rune('a' + index%26))
+	if index >= 26 {
+		name += strconv.Itoa(index / 26)
+	}
+
+	return name
+}
+
+// Replaces every any type in the type with the type given by mapAnyType
+func mapAnyTypes(t types.Type, mapAnyType func(types.AnyType) types.Type) types.Type {
+	mapTypes := func(typeList []types.Type) []types.Type {
+		mapped := make([]types.Type, 0)
+		for i := 0; i < len(typeList); i++ {
+			mapped = append(mapped, mapAnyTypes(typeList[i], mapAnyType))
+		}
+
+		return mapped
+	}
+
+	switch mapped := t.(type) {
+	case types.AnyType:
+		return mapAnyType(mapped)
+
+	case types.ArrayType:
+		return types.ArrayType{ElementType: mapAnyTypes(mapped.ElementType, mapAnyType)}
+
+	case types.FunctionType:
+		return types.FunctionType{TypeIndex: mapped.TypeIndex, ArgumentTypes: mapTypes(mapped.ArgumentTypes), ReturnTypes: mapTypes(mapped.ReturnTypes)}
+
+	case types.UnionType:
+		return mapUnionType(mapped, mapTypes)
+
+	case types.TupleType:
+		return types.TupleType{ElementTypes: mapTypes(mapped.ElementTypes)}
+	}
+
+	return t
+}
+
+// Makes the two types equal by giving type variables known types. Returns an error if that is not possible
+func (v *validator) unify(type1, type2 types.Type) error {
+	type1 = v.resolveType(type1)
+	type2 = v.resolveType(type2)
+
+	if isTypeVariable(type1) {
+		return v.bindTypeVariable(type1.(types.AnyType), type2)
+	}
+
+	if isTypeVariable(type2) {
+		return v.bindTypeVariable(type2.(types.AnyType), type1)
+	}
+
+	switch t1 := type1.(type) {
+	case types.ArrayType:
+		t2, isArrayType := type2.(types.ArrayType)
+		if !isArrayType {
+			break
+		}
+
+		if v.unify(t1.ElementType, t2.ElementType) != nil {
+			break
+		}
+
+		return nil
+
+	case types.FunctionType:
+		t2, isFunctionType := type2.(types.FunctionType)
+		if !isFunctionType {
+			break
+		}
+
+		if v.unifyLists(t1.ArgumentTypes, t2.ArgumentTypes) != nil || v.unifyLists(t1.ReturnTypes, t2.ReturnTypes) != nil {
+			break
+		}
+
+		return nil
+
+	case types.UnionType:
+		t2, isUnionType := type2.(types.UnionType)
+		if !isUnionType || t1.Name != t2.Name {
+			break
+		}
+
+		if v.unifyLists(t1.TypeArguments, t2.TypeArguments) != nil {
+			break
+		}
+
+		return nil
+
+	case types.TupleType:
+		t2, isTupleType := type2.(types.TupleType)
+		if !isTupleType || v.unifyLists(t1.ElementTypes, t2.ElementTypes) != nil {
+			break
+		}
+
+		return nil
+
+	default:
+		if type1.String() == type2.String() {
+			return nil
+		}
+	}
+
+	shown := v.typesForError([]types.Type{type1, type2})[0]
+	return fmt.Errorf("Type %s does not match type %s", shown[0].String(), shown[1].String())
+}
+
+func (v *validator) unifyLists(typeList1, typeList2 []types.Type) error {
+	if len(typeList1) != len(typeList2) {
+		return fmt.Errorf("Number of types does not match")
+	}
+
+	for i := 0; i < len(typeList1); i++ {
+		err := v.unify(typeList1[i], typeList2[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) bindTypeVariable(typeVariable types.AnyType, t types.Type) error {
+	if anyType, isAnyType := t.(types.AnyType); isAnyType && anyType.Name == typeVariable.Name {
+		return nil
+	}
+
+	if containsTypeVariable(t, typeVariable.Name) {
+		return fmt.Errorf("Type %s can not contain itself", t.String())
+	}
+
+	v.typeVariables[typeVariable.Name] = t
+	return nil
+}
+
+// Checks if the type contains the type variable with the given name. An empty name matches all type variables
+func containsTypeVariable(t types.Type, typeVariableName string) bool {
+	switch containing := t.(type) {
+	case types.AnyType:
+		return isTypeVariable(containing) && (typeVariableName == "" || containing.Name == typeVariableName)
+
+	case types.ArrayType:
+		return containsTypeVariable(containing.ElementType, typeVariableName)
+
+	case types.FunctionType:
+		for i := 0; i < len(containing.ArgumentTypes); i++ {
+			if containsTypeVariable(containing.ArgumentTypes[i], typeVariableName) {
+				return true
+			}
+		}
+
+		for i := 0; i < len(containing.ReturnTypes); i++ {
+			if containsTypeVariable(containing.ReturnTypes[i], typeVariableName) {
+				return true
+			}
+		}
+
+	case types.UnionType:
+		unionTypes := getUnionTypeTypes(containing)
+		for i := 0; i < len(unionTypes); i++ {
+			if containsTypeVariable(unionTypes[i], typeVariableName) {
+				return true
+			}
+		}
+
+	case types.TupleType:
+		for i := 0; i < len(containing.ElementTypes); i++ {
+			if containsTypeVariable(containing.ElementTypes[i], typeVariableName) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Checks if the type contains type parameters of a generic function
+func containsTypeParameter(t types.Type) bool {
+	switch containing := t.(type) {
+	case types.AnyType:
+		return !isTypeVariable(containing)
+
+	case types.ArrayType:
+		return containsTypeParameter(containing.ElementType)
+
+	case types.FunctionType:
+		for i := 0; i < len(containing.ArgumentTypes); i++ {
+			if containsTypeParameter(containing.ArgumentTypes[i]) {
+				return true
+			}
+		}
+
+		for i := 0; i < len(containing.ReturnTypes); i++ {
+			if containsTypeParameter(containing.ReturnTypes[i]) {
+				return true
+			}
+		}
+
+	case types.UnionType:
+		unionTypes := getUnionTypeTypes(containing)
+		for i := 0; i < len(unionTypes); i++ {
+			if containsTypeParameter(unionTypes[i]) {
+				return true
+			}
+		}
+
+	case types.TupleType:
+		for i := 0; i < len(containing.ElementTypes); i++ {
+			if containsTypeParameter(containing.ElementTypes[i]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Global functions without specified types are generic in the types that could not be inferred from them, so
+// id = (x) -> { x } can be used with all types. They are validated before the statements using them, so a type
+// variable of the function type becomes a type parameter when no other global has it in its type. Type variables with
+// deferred constraints are not generalized, since constraints are only checked on known types
+func (v *validator) generalizeGlobalFunction(statements []ast.Node, statementIndex int, statement ast.AssignmentStatement) ast.AssignmentStatement {
+	function := statement.Value.(ast.DefineFunctionExpression)
+
+	usedElsewhere := make([]string, 0)
+	for i := 0; i < len(statements); i++ {
+		assignStatement, isAssignStatement := statements[i].(ast.AssignmentStatement)
+		if !isAssignStatement || i == statementIndex {
+			continue
+		}
+
+		for j := 0; j < len(assignStatement.Variables); j++ {
+			usedElsewhere = v.addTypeVariableNames(assignStatement.Variables[j].Type, usedElsewhere)
+		}
+
+		if functionDefinition, isFunctionDefinition := assignStatement.Value.(ast.DefineFunctionExpression); isFunctionDefinition {
+			usedElsewhere = v.addTypeVariableNames(functionDefinition.FunctionType, usedElsewhere)
+		}
+	}
+
+	for i := 0; i < len(v.deferredConstraints); i++ {
+		usedElsewhere = v.addTypeVariableNames(v.deferredConstraints[i].typeVariable, usedElsewhere)
+	}
+
+	typeParameters := append([]string{}, function.TypeParameters...)
+	for _, typeVariableName := range v.addTypeVariableNames(function.FunctionType, []string{}) {
+		if isInList(typeVariableName, usedElsewhere) {
+			continue
+		}
+
+		typeParameter := getGeneralizedTypeParameterName(len(typeParameters) - len(function.TypeParameters))
+		v.typeVariables[typeVariableName] = types.AnyType{Name: typeParameter}
+		typeParameters = append(typeParameters, typeParameter)
+	}
+
+	function.TypeParameters = typeParameters
+	statement.Value = function
+	return statement
+}
+
+// Type parameters of generalized functions are named 'a, 'b and so on, which can not be written in the source code
+func getGeneralizedTypeParameterName(index int) string {
+	if index < 26 {
+		return "'" + string(rune('a'+index))
+	}
+
+	return "'t" + strconv.Itoa(index)
+}
+
+// Adds the names of the type variables in the resolved type that are not in the list yet
+func (v *validator) addTypeVariableNames(t types.Type, names []string) []string {
+	switch containing := v.resolveType(t).(type) {
+	case types.AnyType:
+		if isTypeVariable(containing) && !isInList(containing.Name, names) {
+			return append(names, containing.Name)
+		}
+
+	case types.ArrayType:
+		return v.addTypeVariableNames(containing.ElementType, names)
+
+	case types.FunctionType:
+		for i := 0; i < len(containing.ArgumentTypes); i++ {
+			names = v.addTypeVariableNames(containing.ArgumentTypes[i], names)
+		}
+
+		for i := 0; i < len(containing.ReturnTypes); i++ {
+			names = v.addTypeVariableNames(containing.ReturnTypes[i], names)
+		}
+
+	case types.UnionType:
+		unionTypes := getUnionTypeTypes(containing)
+		for i := 0; i < len(unionTypes); i++ {
+			names = v.addTypeVariableNames(unionTypes[i], names)
+		}
+
+	case types.TupleType:
+		for i := 0; i < len(containing.ElementTypes); i++ {
+			names = v.addTypeVariableNames(containing.ElementTypes[i], names)
+		}
+	}
+
+	return names
+}
+
+// Replaces the generic any types in the function type with new type variables. Returns the new function type and the type variable used for each generic any type
+func (v *validator) instantiate(functionType types.FunctionType) (types.FunctionType, map[string]types.Type) {
+	anyTypeToTypeVariable := make(map[string]types.Type)
+
+	instantiated := types.FunctionType{
+		TypeIndex:     functionType.TypeIndex,
+		ArgumentTypes: v.instantiateTypes(functionType.ArgumentTypes, anyTypeToTypeVariable),
+		ReturnTypes:   v.instantiateTypes(functionType.ReturnTypes, anyTypeToTypeVariable),
+	}
+
+	return instantiated, anyTypeToTypeVariable
+}
+
+func (v *validator) instantiateTypes(typeList []types.Type, anyTypeToTypeVariable map[string]types.Type) []types.Type {
+	instantiated := make([]types.Type, 0)
+	for i := 0; i < len(typeList); i++ {
+		instantiated = append(instantiated, v.instantiateType(typeList[i], anyTypeToTypeVariable))
+	}
+
+	return instantiated
+}
+
+func (v *validator) instantiateType(t types.Type, anyTypeToTypeVariable map[string]types.Type) types.Type {
+	switch generic := t.(type) {
+	case types.AnyType:
+		if isTypeVariable(generic) {
+			return generic
+		}
+
+		if typeVariable, isInstantiated := anyTypeToTypeVariable[generic.Name]; isInstantiated {
+			return typeVariable
+		}
+
+		typeVariable := v.newTypeVariable()
+		anyTypeToTypeVariable[generic.Name] = typeVariable
+		return typeVariable
+
+	case types.ArrayType:
+		return types.ArrayType{ElementType: v.instantiateType(generic.ElementType, anyTypeToTypeVariable)}
+
+	case types.FunctionType:
+		return types.FunctionType{
+			TypeIndex:     generic.TypeIndex,
+			ArgumentTypes: v.instantiateTypes(generic.ArgumentTypes, anyTypeToTypeVariable),
+			ReturnTypes:   v.instantiateTypes(generic.ReturnTypes, anyTypeToTypeVariable),
+		}
+
+	case types.UnionType:
+		return mapUnionType(generic, func(typeList []types.Type) []types.Type {
+			return v.instantiateTypes(typeList, anyTypeToTypeVariable)
+		})
+
+	case types.TupleType:
+		return types.TupleType{ElementTypes: v.instantiateTypes(generic.ElementTypes, anyTypeToTypeVariable)}
+	}
+
+	return t
+}
+
+// Gives the type arguments of the union type followed by the types held by the variants. The type parameters of the
+// union type definition are not included, since they are replaced by the type arguments
+func getUnionTypeTypes(unionType types.UnionType) []types.Type {
+	unionTypes := append([]types.Type{}, unionType.TypeArguments...)
+	if len(unionType.TypeArguments) != 0 || len(unionType.TypeParameters) == 0 {
+		for i := 0; i < len(unionType.Variants); i++ {
+			unionTypes = append(unionTypes, unionType.Variants[i].Types...)
+		}
+	}
+
+	return unionTypes
+}
+
+// Replaces the type arguments and the types held by the variants using mapTypes
+func mapUnionType(unionType types.UnionType, mapTypes func([]types.Type) []types.Type) types.UnionType {
+	if len(unionType.TypeParameters) != 0 && len(unionType.TypeArguments) == 0 {
+		return unionType
+	}
+
+	mapped := types.UnionType{Name: unionType.Name, TypeParameters: unionType.TypeParameters, TypeArguments: mapTypes(unionType.TypeArguments)}
+	for i := 0; i < len(unionType.Variants); i++ {
+		mapped.Variants = append(mapped.Variants, types.UnionVariant{Name: unionType.Variants[i].Name, Types: mapTypes(unionType.Variants[i].Types)})
+	}
+
+	return mapped
+}
+
+// Checks the constraint if the type is known, otherwise the constraint is checked when the global statement has been validated
+func (v *validator) requireConstraint(t types.Type, constraint typeConstraint, newError func(realType types.Type) error) error {
+	resolved := v.resolveType(t)
+	if isTypeVariable(resolved) {
+		v.deferredConstraints = append(v.deferredConstraints, deferredConstraint{
+			typeVariable: resolved,
+			constraint:   constraint,
+			newError:     newError,
+		})
+		return nil
+	}
+
+	if !constraint.isSatisfiedBy(resolved) {
+		return newError(v.typeForError(resolved))
+	}
+
+	return nil
+}
+
+// Checks the deferred constraints that now have known types. Constraints on types still unknown are reported when resolving the syntax tree
+func (v *validator) checkDeferredConstraints() error {
+	constraints := v.deferredConstraints
+	v.deferredConstraints = make([]deferredConstraint, 0)
+
+	for i := 0; i < len(constraints); i++ {
+		resolved := v.resolveType(constraints[i].typeVariable)
+		if isTypeVariable(resolved) {
+			continue
+		}
+
+		if !constraints[i].constraint.isSatisfiedBy(resolved) {
+			return constraints[i].newError(v.typeForError(resolved))
+		}
+	}
+
+	return nil
+}
+
+// Replaces all type variables in the syntax tree with the inferred types. Returns an error if a type could not be inferred
+func (v *validator) resolveNode(node ast.Node) (ast.Node, error) {
+	var err error
+
+	switch n := node.(type) {
+	case ast.AssignmentStatement:
+		n.Value, err = v.resolveNode(n.Value)
+		if err != nil {
+			return n, err
+		}
+
+		for i := 0; i < len(n.Variables); i++ {
+			n.Variables[i].Type, err = v.resolveInferredType(n.Variables[i].Type)
+			if err != nil {
+				return n, fmt.Errorf("Could not infer the type of variable %s: %s", n.Variables[i].Identifier, err.Error())
+			}
+		}
+
+		return n, nil
+
+	case ast.ReturnStatement:
+		n.Expressions, err = v.resolveNodes(n.Expressions)
+		return n, err
+
+	case ast.FunctionStatement:
+		n.Expression, err = v.resolveNode(n.Expression)
+		return n, err
+
+	case ast.BlockStatement:
+		n.Statements, err = v.resolveNodes(n.Statements)
+		return n, err
+
+	case ast.Variable:
+		n.Type, err = v.resolveInferredType(n.Type)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the type of %s: %s", n.Identifier, err.Error())
+		}
+
+		return n, nil
+
+	case ast.DefineFunctionExpression:
+		for i := 0; i < len(n.Arguments); i++ {
+			n.Arguments[i].Type, err = v.resolveInferredType(n.Arguments[i].Type)
+			if err != nil {
+				return n, fmt.Errorf("Could not infer the type of argument %s. Add the type after the argument name", n.Arguments[i].Identifier)
+			}
+		}
+
+		n.ReturnTypes, err = v.resolveInferredTypes(n.ReturnTypes)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the return types of function. Add return types to the function definition")
+		}
+
+		functionType, err := v.resolveInferredType(n.FunctionType)
+		if err != nil {
+			return n, err
+		}
+		n.FunctionType = functionType.(types.FunctionType)
+
+		body, err := v.resolveNode(n.FunctionBody)
+		if err != nil {
+			return n, err
+		}
+		n.FunctionBody = body.(ast.BlockStatement)
+
+		return n, nil
+
+	case ast.ExecuteFunctionExpression:
+		n.Function, err = v.resolveNode(n.Function)
+		if err != nil {
+			return n, err
+		}
+
+		n.Arguments, err = v.resolveNodes(n.Arguments)
+		if err != nil {
+			return n, err
+		}
+
+		n.ReturnTypes, err = v.resolveInferredTypes(n.ReturnTypes)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the return types of function execution: %s", err.Error())
+		}
+
+		return n, nil
+
+	case ast.IfExpression:
+		n.Condition, err = v.resolveNode(n.Condition)
+		if err != nil {
+			return n, err
+		}
+
+		n.TrueExpression, err = v.resolveNode(n.TrueExpression)
+		if err != nil {
+			return n, err
+		}
+
+		n.FalseExpression, err = v.resolveNode(n.FalseExpression)
+		if err != nil {
+			return n, err
+		}
+
+		n.ReturnType, err = v.resolveInferredTypes(n.ReturnType)
+		return n, err
+
+	case ast.OperatorExpression:
+		n.LeftSide, err = v.resolveNode(n.LeftSide)
+		if err != nil {
+			return n, err
+		}
+
+		n.RightSide, err = v.resolveNode(n.RightSide)
+		if err != nil {
+			return n, err
+		}
+
+		n.Type, err = v.resolveInferredType(n.Type)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the type used with operator %s: %s", n.Operator, err.Error())
+		}
+
+		return n, nil
+
+	case ast.UnaryOperatorExpression:
+		n.Expression, err = v.resolveNode(n.Expression)
+		if err != nil {
+			return n, err
+		}
+
+		n.Type, err = v.resolveInferredType(n.Type)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the type used with operator %s: %s", n.Operator, err.Error())
+		}
+
+		return n, nil
+
+	case ast.ArrayExpression:
+		n.ElementsExpressions, err = v.resolveNodes(n.ElementsExpressions)
+		if err != nil {
+			return n, err
+		}
+
+		n.Type, err = v.resolveInferredType(n.Type)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the type of array: %s", err.Error())
+		}
+
+		return n, nil
+
+	case ast.RecordExpression:
+		n.Type = eraseNewTypes(n.Type)
+		n.FieldValues, err = v.resolveNodes(n.FieldValues)
+		return n, err
+
+	case ast.RecordUpdateExpression:
+		n.Type = eraseNewTypes(n.Type)
+		n.Record, err = v.resolveNode(n.Record)
+		if err != nil {
+			return n, err
+		}
+
+		n.FieldValues, err = v.resolveNodes(n.FieldValues)
+		return n, err
+
+	case ast.NewTypeExpression:
+		n.Value, err = v.resolveNode(n.Value)
+		if err != nil {
+			return n, err
+		}
+
+		n.Type, err = v.resolveInferredType(n.Type)
+		return n, err
+
+	case ast.FieldAccessExpression:
+		n.Record, err = v.resolveNode(n.Record)
+		if err != nil {
+			return n, err
+		}
+
+		n.Type, err = v.resolveInferredType(n.Type)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the type of field %s: %s", n.Field, err.Error())
+		}
+
+		return n, nil
+
+	case ast.VariantExpression:
+		n.Type = eraseNewTypesInUnion(n.Type)
+		n.Arguments, err = v.resolveNodes(n.Arguments)
+		return n, err
+
+	case ast.TupleExpression:
+		n.Elements, err = v.resolveNodes(n.Elements)
+		if err != nil {
+			return n, err
+		}
+
+		tupleType, err := v.resolveInferredType(n.Type)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the type of tuple: %s", err.Error())
+		}
+
+		n.Type = tupleType.(types.TupleType)
+		return n, nil
+
+	case ast.DestructureExpression:
+		n.Value, err = v.resolveNode(n.Value)
+		if err != nil {
+			return n, err
+		}
+
+		n.Patterns, err = v.resolvePatterns(n.Patterns)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the type of the value in pattern: %s", err.Error())
+		}
+
+		n.ReturnTypes, err = v.resolveInferredTypes(n.ReturnTypes)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the types of the variables in pattern: %s", err.Error())
+		}
+
+		return n, nil
+
+	case ast.TupleUnpackExpression:
+		n.Tuple, err = v.resolveNode(n.Tuple)
+		if err != nil {
+			return n, err
+		}
+
+		tupleType, err := v.resolveInferredType(n.Type)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the type of tuple: %s", err.Error())
+		}
+
+		n.Type = tupleType.(types.TupleType)
+		return n, nil
+
+	case ast.MatchExpression:
+		n.Value, err = v.resolveNode(n.Value)
+		if err != nil {
+			return n, err
+		}
+
+		matchedType, err := v.resolveInferredType(n.Type)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the type of the value after match: %s", err.Error())
+		}
+		n.Type = matchedType
+
+		for i := 0; i < len(n.Arms); i++ {
+			n.Arms[i].Expression, err = v.resolveNode(n.Arms[i].Expression)
+			if err != nil {
+				return n, err
+			}
+
+			for j := 0; j < len(n.Arms[i].Bindings); j++ {
+				n.Arms[i].Bindings[j].Type = eraseNewTypes(v.resolveType(n.Arms[i].Bindings[j].Type))
+			}
+		}
+
+		n.ReturnTypes, err = v.resolveInferredTypes(n.ReturnTypes)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the return types of match expression: %s", err.Error())
+		}
+
+		return n, nil
+
+	case ast.LetExpression:
+		for i := 0; i < len(n.Bindings); i++ {
+			binding, err := v.resolveNode(n.Bindings[i])
+			if err != nil {
+				return n, err
+			}
+
+			n.Bindings[i] = binding.(ast.AssignmentStatement)
+		}
+
+		n.Expression, err = v.resolveNode(n.Expression)
+		if err != nil {
+			return n, err
+		}
+
+		n.ReturnTypes, err = v.resolveInferredTypes(n.ReturnTypes)
+		if err != nil {
+			return n, fmt.Errorf("Could not infer the types of let expression: %s", err.Error())
+		}
+
+		return n, nil
+	}
+
+	return node, nil
+}
+
+func (v *validator) resolveNodes(nodes []ast.Node) ([]ast.Node, error) {
+	for i := 0; i < len(nodes); i++ {
+		resolved, err := v.resolveNode(nodes[i])
+		if err != nil {
+			return nodes, err
+		}
+
+		nodes[i] = resolved
+	}
+
+	return nodes, nil
+}
+
+func (v *validator) resolveInferredType(t types.Type) (types.Type, error) {
+	if t == nil {
+		return t, nil
+	}
+
+	resolved := v.resolveType(t)
+	if containsTypeVariable(resolved, "") {
+		return resolved, fmt.Errorf("the type is ambiguous")
+	}
+
+	return eraseNewTypes(resolved), nil
+}
+
+func (v *validator) resolveInferredTypes(typeList []types.Type) ([]types.Type, error) {
+	resolved := make([]types.Type, 0)
+	for i := 0; i < len(typeList); i++ {
+		resolvedType, err := v.resolveInferredType(typeList[i])
+		if err != nil {
+			return typeList, err
+		}
+
+		resolved = append(resolved, resolvedType)
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,92 @@
+package validator
+
+import (
+	"compiler/ast"
+	"compiler/symbolTable"
+	"compiler/types"
+	"fmt"
+)
+
+// The names bound by the let are defined in a new block, so they can only be used in the let. Let is the only way
+// to shadow local variables
+func (v *validator) validateLetExpression(expression ast.LetExpression) (ast.LetExpression, []types.Type, error) {
+	names := make([]string, 0)
+	for i := 0; i < len(expression.Bindings); i++ {
+		for j := 0; j < len(expression.Bindings[i].Variables); j++ {
+			name := expression.Bindings[i].Variables[j].Identifier
+			if isInList(name, names) {
+				return ast.LetExpression{}, []types.Type{}, fmt.Errorf("Name %s is bound more than once in let expression", name)
+			}
+
+			names = append(names, name)
+		}
+	}
+
+	//Outside functions the names are added to a new function scope, so they are not added to the global scope
+	isInFunction := v.symbolController.IsInFunction()
+	if !isInFunction {
+		err := v.symbolController.PushFunction([]symbolTable.Variable{})
+		if err != nil {
+			return ast.LetExpression{}, []types.Type{}, err
+		}
+	}
+
+	v.symbolController.PushBlock()
+	validated, returnTypes, err := v.validateLetBindings(expression)
+
+	v.symbolController.PopBlock()
+	if !isInFunction {
+		v.symbolController.PopFunction()
+	}
+
+	return validated, returnTypes, err
+}
+
+// Every value is validated before its names are defined, so a value can use variables with the names it shadows
+func (v *validator) validateLetBindings(expression ast.LetExpression) (ast.LetExpression, []types.Type, error) {
+	for i := 0; i < len(expression.Bindings); i++ {
+		binding := expression.Bindings[i]
+
+		value, valueTypes, err := v.validateExpression(binding.Value)
+		if err != nil {
+			return ast.LetExpression{}, []types.Type{}, err
+		}
+
+		value, valueTypes, err = v.matchTupleAssignment(len(binding.Variables), value, valueTypes)
+		if err != nil {
+			return ast.LetExpression{}, []types.Type{}, err
+		}
+
+		if len(binding.Variables) != len(valueTypes) {
+			return ast.LetExpression{}, []types.Type{}, fmt.Errorf("Expected %v values for the names bound by let, got %v values", len(binding.Variables), len(valueTypes))
+		}
+
+		for j := 0; j < len(binding.Variables); j++ {
+			if binding.Variables[j].Type.String() != types.NONE {
+				variableType, err := v.resolveNamedType(binding.Variables[j].Type)
+				if err != nil {
+					return ast.LetExpression{}, []types.Type{}, err
+				}
+
+				if v.unify(variableType, valueTypes[j]) != nil {
+					return ast.LetExpression{}, []types.Type{}, fmt.Errorf("Given variable type does not match return type from expression")
+				}
+			}
+
+			v.symbolController.DefineVariable(binding.Variables[j].Identifier, valueTypes[j])
+			binding.Variables[j].Type = valueTypes[j]
+		}
+
+		binding.Value = value
+		expression.Bindings[i] = binding
+	}
+
+	validated, returnTypes, err := v.validateExpression(expression.Expression)
+	if err != nil {
+		return ast.LetExpression{}, []types.Type{}, err
+	}
+
+	expression.Expression = validated
+	expression.ReturnTypes = returnTypes
+	return expression, returnTypes, nil
+}
@@ -0,0 +1,140 @@
+package validator
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/symbolTable"
+	"compiler/types"
+	"fmt"
+	"strings"
+)
+
+//Every module is validated on its own, after the modules it imports. Globals and types defined in a module are stored
+//with the path of the module before their names, like math/vector.dot, so modules can use the same names. Other modules
+//use them with the name of the import before the name: vector.dot
+
+func (v *validator) validateModule(module string, block ast.BlockStatement) (ast.BlockStatement, error) {
+	v.module = module
+	v.symbolController.SetModule(module)
+
+	block, err := v.addImports(block)
+	if err != nil {
+		return ast.BlockStatement{}, err
+	}
+
+	validated, err := v.validateGlobalScope(block)
+	if err != nil {
+		return ast.BlockStatement{}, err
+	}
+
+	//The compiler sees all modules as one program, so the globals are given the names they are stored with
+	for i := 0; i < len(validated.Statements); i++ {
+		assignStatement, isAssignStatement := validated.Statements[i].(ast.AssignmentStatement)
+		if !isAssignStatement {
+			continue
+		}
+
+		for j := 0; j < len(assignStatement.Variables); j++ {
+			assignStatement.Variables[j].Identifier = v.symbolController.GetGlobalName(assignStatement.Variables[j].Identifier)
+		}
+
+		validated.Statements[i] = assignStatement
+	}
+
+	v.validatedModules[module] = true
+	return validated, nil
+}
+
+//Removes the import statements from the block and adds the names of the imported modules
+func (v *validator) addImports(block ast.BlockStatement) (ast.BlockStatement, error) {
+	v.imports = make(map[string]string)
+
+	globalNames := make(map[string]bool)
+	for i := 0; i < len(block.Statements); i++ {
+		if assignStatement, isAssignStatement := block.Statements[i].(ast.AssignmentStatement); isAssignStatement {
+			for j := 0; j < len(assignStatement.Variables); j++ {
+				globalNames[assignStatement.Variables[j].Identifier] = true
+			}
+		}
+	}
+
+	statements := make([]ast.Node, 0)
+	for i := 0; i < len(block.Statements); i++ {
+		importStatement, isImport := block.Statements[i].(ast.ImportStatement)
+		if !isImport {
+			statements = append(statements, block.Statements[i])
+			continue
+		}
+
+		if !v.validatedModules[importStatement.Path] {
+			return block, errors.NewGeneralError(importStatement.Line, "Module "+importStatement.Path+" is not loaded")
+		}
+
+		if importedPath, isImported := v.imports[importStatement.Name]; isImported {
+			return block, errors.NewGeneralError(importStatement.Line, fmt.Sprintf("Modules %s and %s are both imported with the name %s", importedPath, importStatement.Path, importStatement.Name))
+		}
+
+		if globalNames[importStatement.Name] {
+			return block, errors.NewGeneralError(importStatement.Line, fmt.Sprintf("Module %s is imported with the name %s, which is also the name of a global variable", importStatement.Path, importStatement.Name))
+		}
+
+		v.imports[importStatement.Name] = importStatement.Path
+	}
+
+	block.Statements = statements
+	return block, nil
+}
+
+//Gives the global of an imported module used like vector.dot. Local variables and globals with the name of the import are used as records
+func (v *validator) getImportedGlobal(expression ast.FieldAccessExpression) (ast.Variable, bool, error) {
+	namespace, isVariable := expression.Record.(ast.Variable)
+	if !isVariable {
+		return ast.Variable{}, false, nil
+	}
+
+	path, isImported := v.imports[namespace.Identifier]
+	if !isImported {
+		return ast.Variable{}, false, nil
+	}
+
+	if _, isDefined, _ := v.symbolController.Resolve(namespace.Identifier); isDefined {
+		return ast.Variable{}, false, nil
+	}
+
+	name := symbolTable.GetModuleGlobalName(path, expression.Field)
+	if _, isDefined := v.symbolController.ResolveGlobal(name); !isDefined {
+		return ast.Variable{}, false, fmt.Errorf("Module %s has no global %s", path, expression.Field)
+	}
+
+	return ast.Variable{Identifier: name, Type: types.StandardType{Name: types.NONE}}, true, nil
+}
+
+//Gives the name a type defined in the module is stored with
+func (v *validator) getModuleTypeName(name string) string {
+	if v.module == preludeModule {
+		return name
+	}
+
+	return v.symbolController.GetGlobalName(name)
+}
+
+//Gives the name a type written in the module is stored with. Types of imported modules are written like vector.Vec,
+//and types of the prelude can be used in every module
+func (v *validator) getDefinedTypeName(name string) string {
+	if dotIndex := strings.Index(name, "."); dotIndex != -1 {
+		if path, isImported := v.imports[name[:dotIndex]]; isImported {
+			return symbolTable.GetModuleGlobalName(path, name[dotIndex+1:])
+		}
+
+		return name
+	}
+
+	moduleName := v.getModuleTypeName(name)
+	_, isNamedType := v.namedTypes[moduleName]
+	_, isAlias := v.typeAliases[moduleName]
+	if isNamedType || isAlias {
+		return moduleName
+	}
+
+	return name
+}
@@ -0,0 +1,139 @@
+package validator
+
+import (
+	"compiler/ast"
+	"compiler/symbolTable"
+	"compiler/types"
+	"fmt"
+)
+
+// Values of newtypes are created by a function with the name of the newtype, and the values they hold are given by
+// unwrap. Newtypes are only used for type checking, so they are replaced with the types they hold when the syntax tree is resolved
+
+func getNewTypeConstructor(name string, newType types.NewType) ast.Node {
+	argument := ast.Variable{Identifier: "value", Type: newType.Type}
+	returnTypes := []types.Type{newType}
+
+	return ast.AssignmentStatement{
+		Variables: []ast.Variable{{Identifier: name, Type: types.StandardType{Name: types.NONE}}},
+		Value: ast.DefineFunctionExpression{
+			Arguments:   []ast.Variable{argument},
+			ReturnTypes: returnTypes,
+			FunctionBody: ast.BlockStatement{Statements: []ast.Node{
+				ast.ReturnStatement{Expressions: []ast.Node{
+					ast.NewTypeExpression{Type: newType, Value: argument},
+				}},
+			}},
+			FunctionType: types.FunctionType{ArgumentTypes: []types.Type{newType.Type}, ReturnTypes: returnTypes},
+		},
+	}
+}
+
+func (v *validator) validateNewTypeExpression(expression ast.NewTypeExpression) (ast.NewTypeExpression, []types.Type, error) {
+	newType, isNewType := expression.Type.(types.NewType)
+	if !isNewType {
+		return ast.NewTypeExpression{}, []types.Type{}, fmt.Errorf("Internal validator error: type in ast.NewTypeExpression not newtype")
+	}
+
+	validated, valueTypes, err := v.validateExpression(expression.Value)
+	if err != nil {
+		return ast.NewTypeExpression{}, []types.Type{}, err
+	}
+
+	if len(valueTypes) != 1 || v.unify(valueTypes[0], newType.Type) != nil {
+		return ast.NewTypeExpression{}, []types.Type{}, fmt.Errorf("Newtype %s holds a value of type %s, got %s", newType.Name, newType.Type.String(), typeListString(v.typesForError(valueTypes)[0]))
+	}
+
+	expression.Value = validated
+	return expression, []types.Type{newType}, nil
+}
+
+func isUnwrapFunctionVariable(function ast.Node, symbolController *symbolTable.SymbolController) bool {
+	functionVariable, isVariable := function.(ast.Variable)
+	if !isVariable || functionVariable.Identifier != "unwrap" {
+		return false
+	}
+
+	_, isDefined, _ := symbolController.Resolve(functionVariable.Identifier)
+	return !isDefined
+}
+
+// unwrap gives the value held by a newtype. The type of the newtype must be known when unwrap is used
+func (v *validator) validateUnwrapExpression(expression ast.ExecuteFunctionExpression) (ast.NewTypeExpression, []types.Type, error) {
+	if len(expression.Arguments) != 1 {
+		return ast.NewTypeExpression{}, []types.Type{}, fmt.Errorf("Function unwrap expects one argument, got %v", len(expression.Arguments))
+	}
+
+	validated, valueTypes, err := v.validateExpression(expression.Arguments[0])
+	if err != nil {
+		return ast.NewTypeExpression{}, []types.Type{}, err
+	}
+
+	if len(valueTypes) != 1 {
+		return ast.NewTypeExpression{}, []types.Type{}, fmt.Errorf("Function unwrap expects one value, got %v values", len(valueTypes))
+	}
+
+	valueType := v.resolveType(valueTypes[0])
+	newType, isNewType := valueType.(types.NewType)
+	if !isNewType {
+		if isTypeVariable(valueType) {
+			return ast.NewTypeExpression{}, []types.Type{}, fmt.Errorf("Could not infer the type of the value given to unwrap. Add the type of the value")
+		}
+
+		return ast.NewTypeExpression{}, []types.Type{}, fmt.Errorf("Function unwrap expects a value of a newtype, got %s", valueType.String())
+	}
+
+	return ast.NewTypeExpression{Type: newType.Type, Value: validated}, []types.Type{newType.Type}, nil
+}
+
+// Replaces the newtypes in the type with the types they hold
+func eraseNewTypes(t types.Type) types.Type {
+	switch erased := t.(type) {
+	case types.NewType:
+		return eraseNewTypes(erased.Type)
+
+	case types.ArrayType:
+		return types.ArrayType{ElementType: eraseNewTypes(erased.ElementType)}
+
+	case types.FunctionType:
+		return types.FunctionType{
+			TypeIndex:     erased.TypeIndex,
+			ArgumentTypes: eraseNewTypesInList(erased.ArgumentTypes),
+			ReturnTypes:   eraseNewTypesInList(erased.ReturnTypes),
+		}
+
+	case types.RecordType:
+		fields := make([]types.RecordField, 0)
+		for i := 0; i < len(erased.Fields); i++ {
+			fields = append(fields, types.RecordField{Name: erased.Fields[i].Name, Type: eraseNewTypes(erased.Fields[i].Type)})
+		}
+
+		return types.RecordType{Name: erased.Name, Fields: fields}
+
+	case types.TupleType:
+		return types.TupleType{ElementTypes: eraseNewTypesInList(erased.ElementTypes)}
+
+	case types.UnionType:
+		return eraseNewTypesInUnion(erased)
+	}
+
+	return t
+}
+
+func eraseNewTypesInList(typeList []types.Type) []types.Type {
+	erased := make([]types.Type, 0)
+	for i := 0; i < len(typeList); i++ {
+		erased = append(erased, eraseNewTypes(typeList[i]))
+	}
+
+	return erased
+}
+
+func eraseNewTypesInUnion(unionType types.UnionType) types.UnionType {
+	erased := types.UnionType{Name: unionType.Name, TypeParameters: unionType.TypeParameters, TypeArguments: eraseNewTypesInList(unionType.TypeArguments)}
+	for i := 0; i < len(unionType.Variants); i++ {
+		erased.Variants = append(erased.Variants, types.UnionVariant{Name: unionType.Variants[i].Name, Types: eraseNewTypesInList(unionType.Variants[i].Types)})
+	}
+
+	return erased
+}
@@ -0,0 +1,377 @@
+package validator
+
+import (
+	"compiler/ast"
+	"compiler/token"
+	"compiler/types"
+	"fmt"
+)
+
+// Checks that every pattern matches the type of its value, and gives the types of the variables bound by the patterns
+func (v *validator) validateDestructureExpression(expression ast.DestructureExpression) (ast.DestructureExpression, []types.Type, error) {
+	value, valueTypes, err := v.validateExpression(expression.Value)
+	if err != nil {
+		return ast.DestructureExpression{}, []types.Type{}, err
+	}
+
+	value, valueTypes, err = v.matchTupleAssignment(len(expression.Patterns), value, valueTypes)
+	if err != nil {
+		return ast.DestructureExpression{}, []types.Type{}, err
+	}
+
+	if len(valueTypes) != len(expression.Patterns) {
+		return ast.DestructureExpression{}, []types.Type{}, fmt.Errorf("Expected %v values for the patterns in assignment statement, got %v values", len(expression.Patterns), len(valueTypes))
+	}
+
+	boundTypes := make([]types.Type, 0)
+	boundNames := make([]string, 0)
+	for i := 0; i < len(expression.Patterns); i++ {
+		pattern, patternTypes, err := v.validatePattern(expression.Patterns[i], valueTypes[i])
+		if err != nil {
+			return ast.DestructureExpression{}, []types.Type{}, err
+		}
+
+		expression.Patterns[i] = pattern
+		boundTypes = append(boundTypes, patternTypes...)
+		boundNames = append(boundNames, getPatternNames(pattern)...)
+	}
+
+	for i := 0; i < len(boundNames); i++ {
+		if isInList(boundNames[i], boundNames[:i]) {
+			return ast.DestructureExpression{}, []types.Type{}, fmt.Errorf("Name %s is used more than once in pattern", boundNames[i])
+		}
+	}
+
+	expression.Value = value
+	expression.ReturnTypes = boundTypes
+	return expression, boundTypes, nil
+}
+
+// Gives the pattern with the types of its parts set and the types of the variables bound by the pattern
+func (v *validator) validatePattern(pattern ast.Node, valueType types.Type) (ast.Node, []types.Type, error) {
+	switch p := pattern.(type) {
+	case ast.Variable:
+		if p.Identifier != token.WILDCARD {
+			p.Type = valueType
+			return p, []types.Type{valueType}, nil
+		}
+
+		//The type given to _ is checked here since _ is not added to the assignment statement
+		err := v.matchPatternType(p.Type, valueType)
+		if err != nil {
+			return p, []types.Type{}, err
+		}
+
+		p.Type = valueType
+		return p, []types.Type{}, nil
+
+	case ast.TuplePattern:
+		return v.validateTuplePattern(p, valueType)
+
+	case ast.ArrayPattern:
+		return v.validateArrayPattern(p, valueType)
+
+	case ast.RecordPattern:
+		return v.validateRecordPattern(p, valueType)
+	}
+
+	return pattern, []types.Type{}, fmt.Errorf("Internal validator error: pattern not supported")
+}
+
+func (v *validator) validatePatterns(patterns []ast.Node, valueTypes []types.Type) ([]ast.Node, []types.Type, error) {
+	boundTypes := make([]types.Type, 0)
+	for i := 0; i < len(patterns); i++ {
+		pattern, patternTypes, err := v.validatePattern(patterns[i], valueTypes[i])
+		if err != nil {
+			return patterns, boundTypes, err
+		}
+
+		patterns[i] = pattern
+		boundTypes = append(boundTypes, patternTypes...)
+	}
+
+	return patterns, boundTypes, nil
+}
+
+// Checks that the type written with a pattern matches the type of the value
+func (v *validator) matchPatternType(patternType, valueType types.Type) error {
+	if patternType.String() == types.NONE {
+		return nil
+	}
+
+	patternType, err := v.resolveNamedType(patternType)
+	if err != nil {
+		return err
+	}
+
+	if v.unify(patternType, valueType) != nil {
+		return fmt.Errorf("Type %s given to pattern does not match the type of the value, %s", patternType.String(), v.typeForError(valueType).String())
+	}
+
+	return nil
+}
+
+func (v *validator) validateTuplePattern(pattern ast.TuplePattern, valueType types.Type) (ast.Node, []types.Type, error) {
+	err := v.matchPatternType(pattern.Type, valueType)
+	if err != nil {
+		return pattern, []types.Type{}, err
+	}
+
+	valueType = v.resolveType(valueType)
+	if isTypeVariable(valueType) {
+		elementTypes := make([]types.Type, 0)
+		for i := 0; i < len(pattern.Elements); i++ {
+			elementTypes = append(elementTypes, v.newTypeVariable())
+		}
+
+		err = v.unify(valueType, types.TupleType{ElementTypes: elementTypes})
+		if err != nil {
+			return pattern, []types.Type{}, err
+		}
+
+		valueType = types.TupleType{ElementTypes: elementTypes}
+	}
+
+	tupleType, isTupleType := valueType.(types.TupleType)
+	if !isTupleType {
+		return pattern, []types.Type{}, fmt.Errorf("Tuple pattern used on value of type %s, which is not a tuple", valueType.String())
+	}
+
+	if len(tupleType.ElementTypes) != len(pattern.Elements) {
+		return pattern, []types.Type{}, fmt.Errorf("Tuple pattern has %v elements, but tuples of type %s hold %v values", len(pattern.Elements), tupleType.String(), len(tupleType.ElementTypes))
+	}
+
+	elements, boundTypes, err := v.validatePatterns(pattern.Elements, tupleType.ElementTypes)
+	if err != nil {
+		return pattern, []types.Type{}, err
+	}
+
+	pattern.Elements = elements
+	pattern.Type = tupleType
+	return pattern, boundTypes, nil
+}
+
+func (v *validator) validateArrayPattern(pattern ast.ArrayPattern, valueType types.Type) (ast.Node, []types.Type, error) {
+	err := v.matchPatternType(pattern.Type, valueType)
+	if err != nil {
+		return pattern, []types.Type{}, err
+	}
+
+	valueType = v.resolveType(valueType)
+	if isTypeVariable(valueType) {
+		arrayType := types.ArrayType{ElementType: v.newTypeVariable()}
+		err = v.unify(valueType, arrayType)
+		if err != nil {
+			return pattern, []types.Type{}, err
+		}
+
+		valueType = arrayType
+	}
+
+	arrayType, isArrayType := valueType.(types.ArrayType)
+	if !isArrayType {
+		return pattern, []types.Type{}, fmt.Errorf("Array pattern used on value of type %s, which is not an array", valueType.String())
+	}
+
+	elementTypes := make([]types.Type, 0)
+	for i := 0; i < len(pattern.Elements); i++ {
+		elementTypes = append(elementTypes, arrayType.ElementType)
+	}
+
+	elements, boundTypes, err := v.validatePatterns(pattern.Elements, elementTypes)
+	if err != nil {
+		return pattern, []types.Type{}, err
+	}
+
+	if pattern.Rest != nil {
+		rest, restTypes, err := v.validatePattern(pattern.Rest, arrayType)
+		if err != nil {
+			return pattern, []types.Type{}, err
+		}
+
+		pattern.Rest = rest
+		boundTypes = append(boundTypes, restTypes...)
+	}
+
+	pattern.Elements = elements
+	pattern.Type = arrayType
+	return pattern, boundTypes, nil
+}
+
+// The record type is given by the name before the fields, the type of the value or the names of the fields
+func (v *validator) validateRecordPattern(pattern ast.RecordPattern, valueType types.Type) (ast.Node, []types.Type, error) {
+	fieldNames := make([]string, 0)
+	for i := 0; i < len(pattern.Fields); i++ {
+		if isInList(pattern.Fields[i].Identifier, fieldNames) {
+			return pattern, []types.Type{}, fmt.Errorf("Field %s is given more than once", pattern.Fields[i].Identifier)
+		}
+
+		fieldNames = append(fieldNames, pattern.Fields[i].Identifier)
+	}
+
+	if pattern.Type.String() != types.NONE {
+		namedType, err := v.resolveNamedType(pattern.Type)
+		if err != nil {
+			return pattern, []types.Type{}, err
+		}
+
+		if _, isRecordType := namedType.(types.RecordType); !isRecordType {
+			return pattern, []types.Type{}, fmt.Errorf("Type %s is not a record type", namedType.String())
+		}
+
+		if v.unify(namedType, valueType) != nil {
+			return pattern, []types.Type{}, fmt.Errorf("Record pattern of type %s used on value of type %s", namedType.String(), v.typeForError(valueType).String())
+		}
+	}
+
+	valueType = v.resolveType(valueType)
+	if isTypeVariable(valueType) {
+		recordTypesWithFields := make([]types.Type, 0)
+		for _, namedType := range v.namedTypes {
+			if recordType, isRecordType := namedType.(types.RecordType); isRecordType && hasFields(recordType, fieldNames) {
+				recordTypesWithFields = append(recordTypesWithFields, recordType)
+			}
+		}
+
+		if len(recordTypesWithFields) != 1 {
+			return pattern, []types.Type{}, fmt.Errorf("Could not infer the record type used with fields %v. Add the type before the record pattern", fieldNames)
+		}
+
+		err := v.unify(valueType, recordTypesWithFields[0])
+		if err != nil {
+			return pattern, []types.Type{}, err
+		}
+
+		valueType = recordTypesWithFields[0]
+	}
+
+	recordType, isRecordType := valueType.(types.RecordType)
+	if !isRecordType {
+		return pattern, []types.Type{}, fmt.Errorf("Record pattern used on value of type %s, which is not a record", valueType.String())
+	}
+
+	boundTypes := make([]types.Type, 0)
+	for i := 0; i < len(pattern.Fields); i++ {
+		fieldIndex, hasField := recordType.GetField(pattern.Fields[i].Identifier)
+		if !hasField {
+			return pattern, []types.Type{}, fmt.Errorf("Record type %s has no field %s", recordType.Name, pattern.Fields[i].Identifier)
+		}
+
+		pattern.Fields[i].Type = recordType.Fields[fieldIndex].Type
+		boundTypes = append(boundTypes, pattern.Fields[i].Type)
+	}
+
+	pattern.Type = recordType
+	return pattern, boundTypes, nil
+}
+
+func hasFields(recordType types.RecordType, fieldNames []string) bool {
+	for i := 0; i < len(fieldNames); i++ {
+		if _, hasField := recordType.GetField(fieldNames[i]); !hasField {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Gives the names of the variables bound by the pattern
+func getPatternNames(pattern ast.Node) []string {
+	switch p := pattern.(type) {
+	case ast.Variable:
+		if p.Identifier == token.WILDCARD {
+			return []string{}
+		}
+
+		return []string{p.Identifier}
+
+	case ast.TuplePattern:
+		names := make([]string, 0)
+		for i := 0; i < len(p.Elements); i++ {
+			names = append(names, getPatternNames(p.Elements[i])...)
+		}
+
+		return names
+
+	case ast.ArrayPattern:
+		names := make([]string, 0)
+		for i := 0; i < len(p.Elements); i++ {
+			names = append(names, getPatternNames(p.Elements[i])...)
+		}
+
+		if p.Rest != nil {
+			names = append(names, getPatternNames(p.Rest)...)
+		}
+
+		return names
+
+	case ast.RecordPattern:
+		names := make([]string, 0)
+		for i := 0; i < len(p.Fields); i++ {
+			names = append(names, p.Fields[i].Identifier)
+		}
+
+		return names
+	}
+
+	return []string{}
+}
+
+// Replaces the type variables in the types of the pattern with the inferred types. The types of record patterns are already known
+func (v *validator) resolvePattern(pattern ast.Node) (ast.Node, error) {
+	var err error
+
+	switch p := pattern.(type) {
+	case ast.Variable:
+		p.Type, err = v.resolveInferredType(p.Type)
+		return p, err
+
+	case ast.TuplePattern:
+		p.Type, err = v.resolveInferredType(p.Type)
+		if err != nil {
+			return p, err
+		}
+
+		p.Elements, err = v.resolvePatterns(p.Elements)
+		return p, err
+
+	case ast.ArrayPattern:
+		p.Type, err = v.resolveInferredType(p.Type)
+		if err != nil {
+			return p, err
+		}
+
+		if p.Rest != nil {
+			p.Rest, err = v.resolvePattern(p.Rest)
+			if err != nil {
+				return p, err
+			}
+		}
+
+		p.Elements, err = v.resolvePatterns(p.Elements)
+		return p, err
+
+	case ast.RecordPattern:
+		p.Type = eraseNewTypes(p.Type)
+		for i := 0; i < len(p.Fields); i++ {
+			p.Fields[i].Type = eraseNewTypes(p.Fields[i].Type)
+		}
+
+		return p, nil
+	}
+
+	return pattern, nil
+}
+
+func (v *validator) resolvePatterns(patterns []ast.Node) ([]ast.Node, error) {
+	for i := 0; i < len(patterns); i++ {
+		resolved, err := v.resolvePattern(patterns[i])
+		if err != nil {
+			return patterns, err
+		}
+
+		patterns[i] = resolved
+	}
+
+	return patterns, nil
+}
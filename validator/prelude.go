@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"compiler/ast"
+	"compiler/parser"
+	"compiler/stdlib"
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// The prelude is the part of the standard library available in every program and module. Its globals are used by
+// their names, but programs can define globals with the same names. Its types keep their names in every module
+const preludeModule = "prelude"
+
+// Parses the Waffle files of the prelude, in the order of their names
+func parsePrelude() (ast.BlockStatement, error) {
+	files, err := fs.ReadDir(stdlib.Prelude, stdlib.PreludeDirectory)
+	if err != nil {
+		return ast.BlockStatement{}, fmt.Errorf("Internal validator error: could not read prelude: %s", err.Error())
+	}
+
+	block := ast.BlockStatement{Statements: make([]ast.Node, 0)}
+	for i := 0; i < len(files); i++ {
+		filePath := path.Join(stdlib.PreludeDirectory, files[i].Name())
+		fileData, err := fs.ReadFile(stdlib.Prelude, filePath)
+		if err != nil {
+			return ast.BlockStatement{}, fmt.Errorf("Internal validator error: could not read prelude file %s: %s", filePath, err.Error())
+		}
+
+		fileTree, err := parser.Parse(string(fileData))
+		if err != nil {
+			return ast.BlockStatement{}, fmt.Errorf("Internal validator error: could not parse prelude file %s: %s", filePath, err.Error())
+		}
+
+		block.Statements = append(block.Statements, fileTree.Body.Statements...)
+	}
+
+	return block, nil
+}
+
+// Globals of the prelude and the modules are only compiled if the program uses them, directly or through other globals
+func removeUnusedGlobals(libraryStatements []ast.Node, programStatements []ast.Node) []ast.Node {
+	definingStatements := make(map[string]int)
+	for i := 0; i < len(libraryStatements); i++ {
+		if assignStatement, isAssignStatement := libraryStatements[i].(ast.AssignmentStatement); isAssignStatement {
+			for j := 0; j < len(assignStatement.Variables); j++ {
+				definingStatements[assignStatement.Variables[j].Identifier] = i
+			}
+		}
+	}
+
+	usedIdentifiers := make([]string, 0)
+	for i := 0; i < len(programStatements); i++ {
+		usedIdentifiers = append(usedIdentifiers, getUsedIdentifiers(programStatements[i])...)
+	}
+
+	isUsed := make([]bool, len(libraryStatements))
+	for len(usedIdentifiers) != 0 {
+		identifier := usedIdentifiers[len(usedIdentifiers)-1]
+		usedIdentifiers = usedIdentifiers[:len(usedIdentifiers)-1]
+
+		statementIndex, isLibraryGlobal := definingStatements[identifier]
+		if !isLibraryGlobal || isUsed[statementIndex] {
+			continue
+		}
+
+		isUsed[statementIndex] = true
+		usedIdentifiers = append(usedIdentifiers, getUsedIdentifiers(libraryStatements[statementIndex])...)
+	}
+
+	usedStatements := make([]ast.Node, 0)
+	for i := 0; i < len(libraryStatements); i++ {
+		if _, isAssignStatement := libraryStatements[i].(ast.AssignmentStatement); isUsed[i] || !isAssignStatement {
+			usedStatements = append(usedStatements, libraryStatements[i])
+		}
+	}
+
+	return usedStatements
+}
@@ -0,0 +1,143 @@
+package validator
+
+import (
+	"compiler/ast"
+	"compiler/types"
+	"fmt"
+)
+
+func (v *validator) validateRecordExpression(expression ast.RecordExpression) (ast.RecordExpression, []types.Type, error) {
+	namedType, isDefined := v.namedTypes[v.getDefinedTypeName(expression.Type.String())]
+	if !isDefined {
+		return ast.RecordExpression{}, []types.Type{}, fmt.Errorf("Type %s is not defined", expression.Type.String())
+	}
+
+	recordType, isRecordType := namedType.(types.RecordType)
+	if !isRecordType {
+		return ast.RecordExpression{}, []types.Type{}, fmt.Errorf("Type %s is not a record type", expression.Type.String())
+	}
+
+	fieldValues, err := v.validateFieldAssignments(recordType, expression.FieldNames, expression.FieldValues)
+	if err != nil {
+		return ast.RecordExpression{}, []types.Type{}, err
+	}
+
+	for i := 0; i < len(recordType.Fields); i++ {
+		if !isInList(recordType.Fields[i].Name, expression.FieldNames) {
+			return ast.RecordExpression{}, []types.Type{}, fmt.Errorf("Field %s is missing in record of type %s", recordType.Fields[i].Name, recordType.Name)
+		}
+	}
+
+	expression.FieldValues = fieldValues
+	expression.Type = recordType
+	return expression, []types.Type{recordType}, nil
+}
+
+func (v *validator) validateRecordUpdateExpression(expression ast.RecordUpdateExpression) (ast.RecordUpdateExpression, []types.Type, error) {
+	if len(expression.FieldNames) == 0 {
+		return ast.RecordUpdateExpression{}, []types.Type{}, fmt.Errorf("No fields to update given after with")
+	}
+
+	record, recordType, err := v.validateRecord(expression.Record, expression.FieldNames[0])
+	if err != nil {
+		return ast.RecordUpdateExpression{}, []types.Type{}, err
+	}
+
+	fieldValues, err := v.validateFieldAssignments(recordType, expression.FieldNames, expression.FieldValues)
+	if err != nil {
+		return ast.RecordUpdateExpression{}, []types.Type{}, err
+	}
+
+	expression.Record = record
+	expression.FieldValues = fieldValues
+	expression.Type = recordType
+	return expression, []types.Type{recordType}, nil
+}
+
+func (v *validator) validateFieldAccessExpression(expression ast.FieldAccessExpression) (ast.FieldAccessExpression, []types.Type, error) {
+	record, recordType, err := v.validateRecord(expression.Record, expression.Field)
+	if err != nil {
+		return ast.FieldAccessExpression{}, []types.Type{}, err
+	}
+
+	fieldIndex, hasField := recordType.GetField(expression.Field)
+	if !hasField {
+		return ast.FieldAccessExpression{}, []types.Type{}, fmt.Errorf("Record type %s has no field %s", recordType.Name, expression.Field)
+	}
+
+	expression.Record = record
+	expression.Type = recordType.Fields[fieldIndex].Type
+	return expression, []types.Type{expression.Type}, nil
+}
+
+// Validates the expression returning the record used with a field. If the type of the record is not known yet, it is inferred from the field name
+func (v *validator) validateRecord(expression ast.Node, fieldName string) (ast.Node, types.RecordType, error) {
+	validated, expressionTypes, err := v.validateExpression(expression)
+	if err != nil {
+		return expression, types.RecordType{}, err
+	}
+
+	if len(expressionTypes) != 1 {
+		return expression, types.RecordType{}, fmt.Errorf("Expected one record before field %s, got %v values", fieldName, len(expressionTypes))
+	}
+
+	expressionType := v.resolveType(expressionTypes[0])
+	if isTypeVariable(expressionType) {
+		recordTypesWithField := make([]types.Type, 0)
+		for _, namedType := range v.namedTypes {
+			if recordType, isRecordType := namedType.(types.RecordType); isRecordType {
+				if _, hasField := recordType.GetField(fieldName); hasField {
+					recordTypesWithField = append(recordTypesWithField, recordType)
+				}
+			}
+		}
+
+		if len(recordTypesWithField) != 1 {
+			return expression, types.RecordType{}, fmt.Errorf("Could not infer the record type used with field %s. Add the type after the argument name", fieldName)
+		}
+
+		err := v.unify(expressionType, recordTypesWithField[0])
+		if err != nil {
+			return expression, types.RecordType{}, err
+		}
+
+		expressionType = recordTypesWithField[0]
+	}
+
+	recordType, isRecordType := expressionType.(types.RecordType)
+	if !isRecordType {
+		return expression, types.RecordType{}, fmt.Errorf("Field %s used on value of type %s, which is not a record", fieldName, expressionType.String())
+	}
+
+	return validated, recordType, nil
+}
+
+// Checks that the fields exist in the record type, are only given once and are given values of the field types
+func (v *validator) validateFieldAssignments(recordType types.RecordType, fieldNames []string, fieldValues []ast.Node) ([]ast.Node, error) {
+	validatedValues := make([]ast.Node, 0)
+
+	for i := 0; i < len(fieldNames); i++ {
+		fieldIndex, hasField := recordType.GetField(fieldNames[i])
+		if !hasField {
+			return fieldValues, fmt.Errorf("Record type %s has no field %s", recordType.Name, fieldNames[i])
+		}
+
+		if isInList(fieldNames[i], fieldNames[:i]) {
+			return fieldValues, fmt.Errorf("Field %s is given more than once", fieldNames[i])
+		}
+
+		validated, valueTypes, err := v.validateExpression(fieldValues[i])
+		if err != nil {
+			return fieldValues, err
+		}
+
+		fieldType := recordType.Fields[fieldIndex].Type
+		if len(valueTypes) != 1 || v.unify(valueTypes[0], fieldType) != nil {
+			return fieldValues, fmt.Errorf("Field %s of record type %s has type %s, got %s", fieldNames[i], recordType.Name, fieldType.String(), typeListString(v.typesForError(valueTypes)[0]))
+		}
+
+		validatedValues = append(validatedValues, validated)
+	}
+
+	return validatedValues, nil
+}
@@ -55,6 +55,233 @@ var standardFunctions = map[string]types.FunctionType{
 		},
 	},
 
+	"toInt": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.StandardType{Name: token.INT},
+		},
+	},
+
+	"toLong": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.StandardType{Name: token.LONG},
+		},
+	},
+
+	"toFloat": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.StandardType{Name: token.FLOAT},
+		},
+	},
+
+	"toDouble": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.StandardType{Name: token.DOUBLE},
+		},
+	},
+
+	"roundToInt": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.StandardType{Name: token.INT},
+		},
+	},
+
+	"floorToInt": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.StandardType{Name: token.INT},
+		},
+	},
+
+	"ceilToInt": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.StandardType{Name: token.INT},
+		},
+	},
+
+	"toBool": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.StandardType{Name: token.BOOL},
+		},
+	},
+
+	"fromBool": {
+		ArgumentTypes: []types.Type{
+			types.StandardType{Name: token.BOOL},
+		},
+		ReturnTypes: []types.Type{
+			types.StandardType{Name: token.INT},
+		},
+	},
+
+	"min": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"max": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"abs": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"sqrt": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"floor": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"ceil": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"trunc": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"nearest": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"pow": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"exp": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"log": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"sin": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"cos": {
+		ArgumentTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+		ReturnTypes: []types.Type{
+			types.AnyType{Name: "a"},
+		},
+	},
+
+	"seed": {
+		ArgumentTypes: []types.Type{
+			types.StandardType{Name: token.INT},
+		},
+		ReturnTypes: []types.Type{
+			types.StandardType{Name: token.INT},
+		},
+	},
+
+	"randomInt": {
+		ArgumentTypes: []types.Type{
+			types.StandardType{Name: token.INT},
+			types.StandardType{Name: token.INT},
+		},
+		ReturnTypes: []types.Type{
+			types.StandardType{Name: token.INT},
+		},
+	},
+
+	"randomFloat": {
+		ArgumentTypes: []types.Type{},
+		ReturnTypes: []types.Type{
+			types.StandardType{Name: token.FLOAT},
+		},
+	},
+
 	/* "map": { Not implemented yet
 		ArgumentTypes: []types.Type{
 			types.FunctionType{
@@ -69,3 +296,77 @@ var standardFunctions = map[string]types.FunctionType{
 		},
 	}, */
 }
+
+type typeConstraint struct {
+	description   string
+	isSatisfiedBy func(types.Type) bool
+}
+
+var numberConstraint = typeConstraint{
+	description: "int, long, float or double",
+	isSatisfiedBy: func(t types.Type) bool {
+		standardType, isStandardType := t.(types.StandardType)
+		return isStandardType && isNumberType(standardType.Name)
+	},
+}
+
+var integerConstraint = typeConstraint{
+	description: "int or long",
+	isSatisfiedBy: func(t types.Type) bool {
+		standardType, isStandardType := t.(types.StandardType)
+		return isStandardType && isIntegerType(standardType.Name)
+	},
+}
+
+var boolConstraint = typeConstraint{
+	description: "bool",
+	isSatisfiedBy: func(t types.Type) bool {
+		return t.String() == token.BOOL
+	},
+}
+
+var integerOrBoolConstraint = typeConstraint{
+	description: "int, long or bool",
+	isSatisfiedBy: func(t types.Type) bool {
+		return integerConstraint.isSatisfiedBy(t) || boolConstraint.isSatisfiedBy(t)
+	},
+}
+
+var numberOrBoolConstraint = typeConstraint{
+	description: "int, long, float, double or bool",
+	isSatisfiedBy: func(t types.Type) bool {
+		return numberConstraint.isSatisfiedBy(t) || boolConstraint.isSatisfiedBy(t)
+	},
+}
+
+var floatingPointConstraint = typeConstraint{
+	description: "float or double",
+	isSatisfiedBy: func(t types.Type) bool {
+		return t.String() == token.FLOAT || t.String() == token.DOUBLE
+	},
+}
+
+// Constraints on the any types of standard functions. The any types must be satisfied by the types given when the function is executed.
+var standardFunctionsAnyTypeConstraints = map[string]map[string]typeConstraint{
+	"toInt":      {"a": numberConstraint},
+	"toLong":     {"a": numberConstraint},
+	"toFloat":    {"a": numberConstraint},
+	"toDouble":   {"a": numberConstraint},
+	"toBool":     {"a": numberConstraint},
+	"roundToInt": {"a": floatingPointConstraint},
+	"floorToInt": {"a": floatingPointConstraint},
+	"ceilToInt":  {"a": floatingPointConstraint},
+	"min":        {"a": numberConstraint},
+	"max":        {"a": numberConstraint},
+	"abs":        {"a": numberConstraint},
+	"sqrt":       {"a": floatingPointConstraint},
+	"floor":      {"a": floatingPointConstraint},
+	"ceil":       {"a": floatingPointConstraint},
+	"trunc":      {"a": floatingPointConstraint},
+	"nearest":    {"a": floatingPointConstraint},
+	"pow":        {"a": floatingPointConstraint},
+	"exp":        {"a": floatingPointConstraint},
+	"log":        {"a": floatingPointConstraint},
+	"sin":        {"a": floatingPointConstraint},
+	"cos":        {"a": floatingPointConstraint},
+}
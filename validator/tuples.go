@@ -0,0 +1,66 @@
+package validator
+
+import (
+	"compiler/ast"
+	"compiler/types"
+	"fmt"
+)
+
+func (v *validator) validateTupleExpression(expression ast.TupleExpression) (ast.TupleExpression, []types.Type, error) {
+	elementTypes := make([]types.Type, 0)
+	for i := 0; i < len(expression.Elements); i++ {
+		validated, valueTypes, err := v.validateExpression(expression.Elements[i])
+		if err != nil {
+			return ast.TupleExpression{}, []types.Type{}, err
+		}
+
+		if len(valueTypes) != 1 {
+			return ast.TupleExpression{}, []types.Type{}, fmt.Errorf("Expected one value for element %v in tuple, got %v values", i, len(valueTypes))
+		}
+
+		expression.Elements[i] = validated
+		elementTypes = append(elementTypes, valueTypes[0])
+	}
+
+	expression.Type = types.TupleType{ElementTypes: elementTypes}
+	return expression, []types.Type{expression.Type}, nil
+}
+
+// More than one value assigned to a single variable is stored as a tuple, and a tuple assigned to more than one
+// variable is unpacked to its elements. Returns the value to assign and the types of the values it gives
+func (v *validator) matchTupleAssignment(numVariables int, value ast.Node, valueTypes []types.Type) (ast.Node, []types.Type, error) {
+	if numVariables == 1 && len(valueTypes) > 1 {
+		tupleType := types.TupleType{ElementTypes: valueTypes}
+		return ast.TupleExpression{Type: tupleType, Elements: []ast.Node{value}}, []types.Type{tupleType}, nil
+	}
+
+	if numVariables <= 1 || len(valueTypes) != 1 {
+		return value, valueTypes, nil
+	}
+
+	valueType := v.resolveType(valueTypes[0])
+	if isTypeVariable(valueType) {
+		elementTypes := make([]types.Type, 0)
+		for i := 0; i < numVariables; i++ {
+			elementTypes = append(elementTypes, v.newTypeVariable())
+		}
+
+		err := v.unify(valueType, types.TupleType{ElementTypes: elementTypes})
+		if err != nil {
+			return value, valueTypes, err
+		}
+
+		valueType = types.TupleType{ElementTypes: elementTypes}
+	}
+
+	tupleType, isTupleType := valueType.(types.TupleType)
+	if !isTupleType {
+		return value, valueTypes, nil
+	}
+
+	if len(tupleType.ElementTypes) != numVariables {
+		return value, valueTypes, fmt.Errorf("Tuple of type %s holds %v values, got %v variables in assignment statement", tupleType.String(), len(tupleType.ElementTypes), numVariables)
+	}
+
+	return ast.TupleUnpackExpression{Type: tupleType, Tuple: value}, tupleType.ElementTypes, nil
+}
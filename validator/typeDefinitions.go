@@ -0,0 +1,405 @@
+package validator
+
+import (
+	"compiler/ast"
+	"compiler/types"
+	"fmt"
+)
+
+type typeDefinitionState int
+
+const (
+	typeNotResolved typeDefinitionState = iota
+	typeBeingResolved
+	typeResolved
+)
+
+// Types defined in the global scope can be used before they are defined, so all type definitions are added before any other statement is validated.
+// The definitions are stored by the names including the module, which are also the names of the record and union types
+func (v *validator) addTypeDefinitions(block ast.BlockStatement) error {
+	definitions := make(map[string]ast.TypeDefinitionStatement)
+	for i := 0; i < len(block.Statements); i++ {
+		definition, isTypeDefinition := block.Statements[i].(ast.TypeDefinitionStatement)
+		if !isTypeDefinition {
+			continue
+		}
+
+		typeName := v.getModuleTypeName(definition.Name)
+		_, isDefined := definitions[typeName]
+		_, isNamedType := v.namedTypes[typeName]
+		_, isAlias := v.typeAliases[typeName]
+		if isDefined || isNamedType || isAlias {
+			return fmt.Errorf("Double definition of type %s", definition.Name)
+		}
+
+		definitions[typeName] = getModuleTypeDefinition(definition, typeName)
+
+		//Union types are used by name, so they can contain themselves and can be used before their type definition is resolved
+		if isUnionTypeDefinition(definition) {
+			v.namedTypes[typeName] = types.UnionType{Name: typeName, TypeParameters: definition.Type.(types.UnionType).TypeParameters}
+		}
+	}
+
+	states := make(map[string]typeDefinitionState)
+	for i := 0; i < len(block.Statements); i++ {
+		definition, isTypeDefinition := block.Statements[i].(ast.TypeDefinitionStatement)
+		if !isTypeDefinition {
+			continue
+		}
+
+		typeName := v.getModuleTypeName(definition.Name)
+		err := v.resolveTypeDefinition(typeName, definitions, states)
+		if err != nil {
+			return err
+		}
+
+		definition.Type = v.namedTypes[typeName]
+		if definition.IsAlias {
+			definition.Type = v.typeAliases[typeName].Type
+		}
+
+		block.Statements[i] = definition
+	}
+
+	return nil
+}
+
+// Resolves the named types used in the type definition, resolving the type definitions they refer to first
+func (v *validator) resolveTypeDefinition(typeName string, definitions map[string]ast.TypeDefinitionStatement, states map[string]typeDefinitionState) error {
+	if states[typeName] == typeResolved {
+		return nil
+	}
+
+	if states[typeName] == typeBeingResolved {
+		return fmt.Errorf("Type %s can not contain itself", typeName)
+	}
+
+	states[typeName] = typeBeingResolved
+
+	for _, usedTypeName := range getUsedTypeNames(definitions[typeName].Type) {
+		usedTypeName = v.getModuleTypeName(usedTypeName)
+		usedDefinition, isDefinedType := definitions[usedTypeName]
+		if !isDefinedType {
+			continue
+		}
+
+		if isUnionTypeDefinition(usedDefinition) {
+			continue
+		}
+
+		err := v.resolveTypeDefinition(usedTypeName, definitions, states)
+		if err != nil {
+			return err
+		}
+	}
+
+	if definitions[typeName].IsAlias {
+		err := v.resolveTypeAlias(definitions[typeName])
+		if err != nil {
+			return err
+		}
+
+		states[typeName] = typeResolved
+		return nil
+	}
+
+	if definitions[typeName].IsNewType {
+		heldType, err := v.resolveNamedType(definitions[typeName].Type)
+		if err != nil {
+			return err
+		}
+
+		v.namedTypes[typeName] = types.NewType{Name: typeName, Type: heldType}
+		states[typeName] = typeResolved
+		return nil
+	}
+
+	if unionType, isUnionType := definitions[typeName].Type.(types.UnionType); isUnionType {
+		resolved, err := v.resolveUnionType(unionType)
+		if err != nil {
+			return err
+		}
+
+		v.namedTypes[typeName] = resolved
+		states[typeName] = typeResolved
+		return nil
+	}
+
+	recordType, isRecordType := definitions[typeName].Type.(types.RecordType)
+	if !isRecordType {
+		return fmt.Errorf("Internal validator error: type definition of %s not supported", typeName)
+	}
+
+	resolved := types.RecordType{Name: recordType.Name, Fields: make([]types.RecordField, 0)}
+	for i := 0; i < len(recordType.Fields); i++ {
+		if _, isDuplicate := resolved.GetField(recordType.Fields[i].Name); isDuplicate {
+			return fmt.Errorf("Field %s is defined more than once in record type %s", recordType.Fields[i].Name, typeName)
+		}
+
+		fieldType, err := v.resolveNamedType(recordType.Fields[i].Type)
+		if err != nil {
+			return err
+		}
+
+		resolved.Fields = append(resolved.Fields, types.RecordField{Name: recordType.Fields[i].Name, Type: fieldType})
+	}
+
+	v.namedTypes[typeName] = resolved
+	states[typeName] = typeResolved
+
+	return nil
+}
+
+// Gives the definition with the name including the module
+func getModuleTypeDefinition(definition ast.TypeDefinitionStatement, typeName string) ast.TypeDefinitionStatement {
+	definition.Name = typeName
+	if definition.IsAlias || definition.IsNewType {
+		return definition
+	}
+
+	switch t := definition.Type.(type) {
+	case types.UnionType:
+		t.Name = typeName
+		definition.Type = t
+	case types.RecordType:
+		t.Name = typeName
+		definition.Type = t
+	}
+
+	return definition
+}
+
+func isUnionTypeDefinition(definition ast.TypeDefinitionStatement) bool {
+	_, isUnionType := definition.Type.(types.UnionType)
+	return isUnionType && !definition.IsAlias && !definition.IsNewType
+}
+
+// Type aliases can have type parameters, which are replaced with the type arguments where the alias is used
+func (v *validator) resolveTypeAlias(alias ast.TypeDefinitionStatement) error {
+	for i := 0; i < len(alias.TypeParameters); i++ {
+		if isInList(alias.TypeParameters[i], alias.TypeParameters[:i]) {
+			return fmt.Errorf("Type parameter %s is defined more than once in type %s", alias.TypeParameters[i], alias.Name)
+		}
+	}
+
+	v.typeParameters = alias.TypeParameters
+	defer func() { v.typeParameters = nil }()
+
+	aliasedType, err := v.resolveNamedType(alias.Type)
+	if err != nil {
+		return err
+	}
+
+	alias.Type = aliasedType
+	v.typeAliases[alias.Name] = alias
+	return nil
+}
+
+func (v *validator) getAliasedType(alias ast.TypeDefinitionStatement, typeArguments []types.Type) (types.Type, error) {
+	if len(alias.TypeParameters) == 0 && len(typeArguments) != 0 {
+		return alias.Type, fmt.Errorf("Type %s can not be given type arguments", alias.Name)
+	}
+
+	if len(typeArguments) != len(alias.TypeParameters) {
+		return alias.Type, fmt.Errorf("Type %s expects %v type arguments, got %v", alias.Name, len(alias.TypeParameters), len(typeArguments))
+	}
+
+	typeArguments, err := v.resolveNamedTypes(typeArguments)
+	if err != nil {
+		return alias.Type, err
+	}
+
+	typeParameterToType := make(map[string]types.Type)
+	for i := 0; i < len(typeArguments); i++ {
+		typeParameterToType[alias.TypeParameters[i]] = typeArguments[i]
+	}
+
+	return v.instantiateType(alias.Type, typeParameterToType), nil
+}
+
+// Gives the names written in the type that can refer to other named types
+func getUsedTypeNames(t types.Type) []string {
+	switch used := t.(type) {
+	case types.AnyType:
+		return []string{used.Name}
+
+	case types.ArrayType:
+		return getUsedTypeNames(used.ElementType)
+
+	case types.FunctionType:
+		names := make([]string, 0)
+		for i := 0; i < len(used.ArgumentTypes); i++ {
+			names = append(names, getUsedTypeNames(used.ArgumentTypes[i])...)
+		}
+
+		for i := 0; i < len(used.ReturnTypes); i++ {
+			names = append(names, getUsedTypeNames(used.ReturnTypes[i])...)
+		}
+
+		return names
+
+	case types.RecordType:
+		names := make([]string, 0)
+		for i := 0; i < len(used.Fields); i++ {
+			names = append(names, getUsedTypeNames(used.Fields[i].Type)...)
+		}
+
+		return names
+
+	case types.TupleType:
+		names := make([]string, 0)
+		for i := 0; i < len(used.ElementTypes); i++ {
+			names = append(names, getUsedTypeNames(used.ElementTypes[i])...)
+		}
+
+		return names
+
+	case types.UnionType:
+		names := make([]string, 0)
+		for i := 0; i < len(used.TypeArguments); i++ {
+			names = append(names, getUsedTypeNames(used.TypeArguments[i])...)
+		}
+
+		for i := 0; i < len(used.Variants); i++ {
+			for j := 0; j < len(used.Variants[i].Types); j++ {
+				names = append(names, getUsedTypeNames(used.Variants[i].Types[j])...)
+			}
+		}
+
+		return names
+	}
+
+	return []string{}
+}
+
+// Types written as names are either type parameters of the global function being validated or types defined with a type definition
+func (v *validator) resolveNamedType(t types.Type) (types.Type, error) {
+	switch named := t.(type) {
+	case types.AnyType:
+		if isTypeVariable(named) || isInList(named.Name, v.typeParameters) {
+			return named, nil
+		}
+
+		typeName := v.getDefinedTypeName(named.Name)
+		if alias, isAlias := v.typeAliases[typeName]; isAlias {
+			if len(alias.TypeParameters) != 0 {
+				return t, fmt.Errorf("Type %s expects %v type arguments, like %s<int>", named.Name, len(alias.TypeParameters), named.Name)
+			}
+
+			return alias.Type, nil
+		}
+
+		if namedType, isDefined := v.namedTypes[typeName]; isDefined {
+			if unionType, isUnionType := namedType.(types.UnionType); isUnionType {
+				if len(unionType.TypeParameters) != 0 {
+					return t, fmt.Errorf("Type %s expects %v type arguments, like %s<int>", named.Name, len(unionType.TypeParameters), named.Name)
+				}
+
+				return types.UnionType{Name: typeName}, nil
+			}
+
+			return namedType, nil
+		}
+
+		return t, fmt.Errorf("Type %s is not defined", named.Name)
+
+	case types.UnionType:
+		typeName := v.getDefinedTypeName(named.Name)
+		if alias, isAlias := v.typeAliases[typeName]; isAlias {
+			return v.getAliasedType(alias, named.TypeArguments)
+		}
+
+		unionType, isUnionType := v.namedTypes[typeName].(types.UnionType)
+		if !isUnionType {
+			if _, isDefined := v.namedTypes[typeName]; isDefined || isInList(named.Name, v.typeParameters) {
+				return t, fmt.Errorf("Type %s can not be given type arguments", named.Name)
+			}
+
+			return t, fmt.Errorf("Type %s is not defined", named.Name)
+		}
+
+		if len(named.TypeArguments) != len(unionType.TypeParameters) {
+			return t, fmt.Errorf("Type %s expects %v type arguments, got %v", named.Name, len(unionType.TypeParameters), len(named.TypeArguments))
+		}
+
+		typeArguments, err := v.resolveNamedTypes(named.TypeArguments)
+		if err != nil {
+			return t, err
+		}
+
+		return types.UnionType{Name: typeName, TypeArguments: typeArguments}, nil
+
+	case types.ArrayType:
+		elementType, err := v.resolveNamedType(named.ElementType)
+		if err != nil {
+			return t, err
+		}
+
+		return types.ArrayType{ElementType: elementType}, nil
+
+	case types.FunctionType:
+		argumentTypes, err := v.resolveNamedTypes(named.ArgumentTypes)
+		if err != nil {
+			return t, err
+		}
+
+		returnTypes, err := v.resolveNamedTypes(named.ReturnTypes)
+		if err != nil {
+			return t, err
+		}
+
+		return types.FunctionType{TypeIndex: named.TypeIndex, ArgumentTypes: argumentTypes, ReturnTypes: returnTypes}, nil
+
+	case types.TupleType:
+		elementTypes, err := v.resolveNamedTypes(named.ElementTypes)
+		if err != nil {
+			return t, err
+		}
+
+		return types.TupleType{ElementTypes: elementTypes}, nil
+	}
+
+	return t, nil
+}
+
+func (v *validator) resolveNamedTypes(typeList []types.Type) ([]types.Type, error) {
+	resolved := make([]types.Type, 0)
+	for i := 0; i < len(typeList); i++ {
+		resolvedType, err := v.resolveNamedType(typeList[i])
+		if err != nil {
+			return typeList, err
+		}
+
+		resolved = append(resolved, resolvedType)
+	}
+
+	return resolved, nil
+}
+
+// Replaces the names in the argument types and return types of the function with the types they refer to
+func (v *validator) resolveFunctionDefinitionTypes(function ast.DefineFunctionExpression) (ast.DefineFunctionExpression, error) {
+	arguments := make([]ast.Variable, 0)
+	for i := 0; i < len(function.Arguments); i++ {
+		argument := function.Arguments[i]
+
+		argumentType, err := v.resolveNamedType(argument.Type)
+		if err != nil {
+			return function, err
+		}
+
+		argument.Type = argumentType
+		arguments = append(arguments, argument)
+	}
+
+	returnTypes, err := v.resolveNamedTypes(function.ReturnTypes)
+	if err != nil {
+		return function, err
+	}
+
+	function.Arguments = arguments
+	function.ReturnTypes = returnTypes
+	function.FunctionType.ArgumentTypes = VariablesToTypeList(arguments)
+	function.FunctionType.ReturnTypes = returnTypes
+
+	return function, nil
+}
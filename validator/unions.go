@@ -0,0 +1,372 @@
+package validator
+
+import (
+	"compiler/ast"
+	"compiler/symbolTable"
+	"compiler/token"
+	"compiler/types"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Resolves the types held by the variants and checks that the variant names are only used once in the union type
+func (v *validator) resolveUnionType(unionType types.UnionType) (types.UnionType, error) {
+	for i := 0; i < len(unionType.TypeParameters); i++ {
+		if isInList(unionType.TypeParameters[i], unionType.TypeParameters[:i]) {
+			return types.UnionType{}, fmt.Errorf("Type parameter %s is defined more than once in union type %s", unionType.TypeParameters[i], unionType.Name)
+		}
+	}
+
+	//The type parameters can be used in the types held by the variants
+	v.typeParameters = unionType.TypeParameters
+	defer func() { v.typeParameters = nil }()
+
+	resolved := types.UnionType{Name: unionType.Name, TypeParameters: unionType.TypeParameters, Variants: make([]types.UnionVariant, 0)}
+	for i := 0; i < len(unionType.Variants); i++ {
+		if _, isDuplicate := resolved.GetVariant(unionType.Variants[i].Name); isDuplicate {
+			return types.UnionType{}, fmt.Errorf("Variant %s is defined more than once in union type %s", unionType.Variants[i].Name, unionType.Name)
+		}
+
+		variantTypes, err := v.resolveNamedTypes(unionType.Variants[i].Types)
+		if err != nil {
+			return types.UnionType{}, err
+		}
+
+		resolved.Variants = append(resolved.Variants, types.UnionVariant{Name: unionType.Variants[i].Name, Types: variantTypes})
+	}
+
+	return resolved, nil
+}
+
+// Every variant is created by a global value or function with the name of the variant. Variants holding no values are
+// global values, the others are functions taking the values held by the variant. Values of newtypes are created by a
+// function with the name of the newtype. The statements defining them are added to the start of the global scope so
+// they can be used everywhere.
+func (v *validator) addConstructors(block ast.BlockStatement) (ast.BlockStatement, error) {
+	globalNames := make(map[string]bool)
+	for i := 0; i < len(block.Statements); i++ {
+		if assignStatement, isAssignStatement := block.Statements[i].(ast.AssignmentStatement); isAssignStatement {
+			for j := 0; j < len(assignStatement.Variables); j++ {
+				globalNames[assignStatement.Variables[j].Identifier] = true
+			}
+		}
+	}
+
+	constructors := make([]ast.Node, 0)
+	for i := 0; i < len(block.Statements); i++ {
+		definition, isTypeDefinition := block.Statements[i].(ast.TypeDefinitionStatement)
+		if !isTypeDefinition {
+			continue
+		}
+
+		if newType, isNewType := definition.Type.(types.NewType); isNewType {
+			if globalNames[definition.Name] {
+				return block, fmt.Errorf("Newtype %s has the same name as a variant or global variable", definition.Name)
+			}
+
+			globalNames[definition.Name] = true
+			constructors = append(constructors, getNewTypeConstructor(definition.Name, newType))
+			continue
+		}
+
+		unionType, isUnionType := definition.Type.(types.UnionType)
+		if !isUnionType || definition.IsAlias {
+			continue
+		}
+
+		for j := 0; j < len(unionType.Variants); j++ {
+			variant := unionType.Variants[j]
+			if globalNames[variant.Name] {
+				return block, fmt.Errorf("Variant %s of union type %s has the same name as another variant or global variable", variant.Name, unionType.Name)
+			}
+
+			globalNames[variant.Name] = true
+			constructors = append(constructors, getVariantConstructor(unionType, variant))
+		}
+	}
+
+	block.Statements = append(constructors, block.Statements...)
+	return block, nil
+}
+
+// Variants of union types with type parameters are created by generic functions. Variants holding no values are global
+// values with the type parameters in their type, which are given new type variables every time they are used
+func getVariantConstructor(unionType types.UnionType, variant types.UnionVariant) ast.Node {
+	variable := ast.Variable{Identifier: variant.Name, Type: types.StandardType{Name: types.NONE}}
+	if len(variant.Types) == 0 {
+		return ast.AssignmentStatement{
+			Variables: []ast.Variable{variable},
+			Value:     ast.VariantExpression{Type: unionType, Variant: variant.Name, Arguments: []ast.Node{}},
+		}
+	}
+
+	arguments := make([]ast.Variable, 0)
+	argumentNodes := make([]ast.Node, 0)
+	for i := 0; i < len(variant.Types); i++ {
+		argument := ast.Variable{Identifier: "value" + strconv.Itoa(i), Type: variant.Types[i]}
+		arguments = append(arguments, argument)
+		argumentNodes = append(argumentNodes, argument)
+	}
+
+	returnTypes := []types.Type{getGenericUnionType(unionType)}
+
+	return ast.AssignmentStatement{
+		Variables: []ast.Variable{variable},
+		Value: ast.DefineFunctionExpression{
+			TypeParameters: unionType.TypeParameters,
+			Arguments:      arguments,
+			ReturnTypes:    returnTypes,
+			FunctionBody: ast.BlockStatement{Statements: []ast.Node{
+				ast.ReturnStatement{Expressions: []ast.Node{
+					ast.VariantExpression{Type: unionType, Variant: variant.Name, Arguments: argumentNodes},
+				}},
+			}},
+			FunctionType: types.FunctionType{ArgumentTypes: variant.Types, ReturnTypes: returnTypes},
+		},
+	}
+}
+
+func (v *validator) validateVariantExpression(expression ast.VariantExpression) (ast.VariantExpression, []types.Type, error) {
+	variantIndex, isVariant := expression.Type.GetVariant(expression.Variant)
+	if !isVariant {
+		return ast.VariantExpression{}, []types.Type{}, fmt.Errorf("Internal validator error: union type %s has no variant %s", expression.Type.Name, expression.Variant)
+	}
+
+	variantTypes := expression.Type.Variants[variantIndex].Types
+	if len(expression.Arguments) != len(variantTypes) {
+		return ast.VariantExpression{}, []types.Type{}, fmt.Errorf("Internal validator error: wrong number of values given to variant %s", expression.Variant)
+	}
+
+	for i := 0; i < len(expression.Arguments); i++ {
+		validated, argumentTypes, err := v.validateExpression(expression.Arguments[i])
+		if err != nil {
+			return ast.VariantExpression{}, []types.Type{}, err
+		}
+
+		if len(argumentTypes) != 1 || v.unify(argumentTypes[0], variantTypes[i]) != nil {
+			return ast.VariantExpression{}, []types.Type{}, fmt.Errorf("Variant %s expects values of types %s", expression.Variant, typeListString(variantTypes))
+		}
+
+		expression.Arguments[i] = validated
+	}
+
+	return expression, []types.Type{getGenericUnionType(expression.Type)}, nil
+}
+
+// Gives the union type used as a value type, with the type parameters of the union type as type arguments
+func getGenericUnionType(unionType types.UnionType) types.UnionType {
+	typeArguments := make([]types.Type, 0)
+	for i := 0; i < len(unionType.TypeParameters); i++ {
+		typeArguments = append(typeArguments, types.AnyType{Name: unionType.TypeParameters[i]})
+	}
+
+	return types.UnionType{Name: unionType.Name, TypeArguments: typeArguments}
+}
+
+// Checks that the arms match variants of the union type, that all variants are matched and that all arms return the same types
+func (v *validator) validateMatchExpression(expression ast.MatchExpression) (ast.MatchExpression, []types.Type, error) {
+	value, valueTypes, err := v.validateExpression(expression.Value)
+	if err != nil {
+		return ast.MatchExpression{}, []types.Type{}, err
+	}
+
+	if len(valueTypes) != 1 {
+		return ast.MatchExpression{}, []types.Type{}, fmt.Errorf("Expected one value after match, got %v values", len(valueTypes))
+	}
+
+	unionType, err := v.getMatchedUnionType(valueTypes[0], expression.Arms)
+	if err != nil {
+		return ast.MatchExpression{}, []types.Type{}, err
+	}
+
+	returnTypes := make([]types.Type, 0)
+	matchedVariants := make(map[string]bool)
+	hasWildcard := false
+
+	for i := 0; i < len(expression.Arms); i++ {
+		arm := expression.Arms[i]
+		if hasWildcard {
+			return ast.MatchExpression{}, []types.Type{}, fmt.Errorf("Arms after the arm with _ in match expression are never used")
+		}
+
+		bindingTypes := []types.Type{}
+		if arm.Variant == "" {
+			hasWildcard = true
+		} else {
+			variantIndex, isVariant := unionType.GetVariant(arm.Variant)
+			if !isVariant {
+				return ast.MatchExpression{}, []types.Type{}, fmt.Errorf("Union type %s has no variant %s", unionType.Name, arm.Variant)
+			}
+
+			if matchedVariants[arm.Variant] {
+				return ast.MatchExpression{}, []types.Type{}, fmt.Errorf("Variant %s is matched more than once in match expression", arm.Variant)
+			}
+			matchedVariants[arm.Variant] = true
+
+			bindingTypes = unionType.Variants[variantIndex].Types
+			if len(arm.Bindings) != len(bindingTypes) {
+				return ast.MatchExpression{}, []types.Type{}, fmt.Errorf("Variant %s holds %v values, got %v names in match arm", arm.Variant, len(bindingTypes), len(arm.Bindings))
+			}
+		}
+
+		validatedArm, armTypes, err := v.validateMatchArm(arm, bindingTypes)
+		if err != nil {
+			return ast.MatchExpression{}, []types.Type{}, err
+		}
+
+		if i == 0 {
+			returnTypes = armTypes
+		} else if v.unifyLists(returnTypes, armTypes) != nil {
+			shown := v.typesForError(returnTypes, armTypes)
+			return ast.MatchExpression{}, []types.Type{}, fmt.Errorf("All arms in match expression must return the same types, got %s and %s", typeListString(shown[0]), typeListString(shown[1]))
+		}
+
+		expression.Arms[i] = validatedArm
+	}
+
+	if !hasWildcard {
+		missingVariants := make([]string, 0)
+		for i := 0; i < len(unionType.Variants); i++ {
+			if !matchedVariants[unionType.Variants[i].Name] {
+				missingVariants = append(missingVariants, unionType.Variants[i].Name)
+			}
+		}
+
+		if len(missingVariants) != 0 {
+			return ast.MatchExpression{}, []types.Type{}, fmt.Errorf("Match expression on %s does not match the variants %s. Add arms for them or an arm with _", unionType.Name, strings.Join(missingVariants, ", "))
+		}
+	}
+
+	expression.Value = value
+	expression.Type = unionType
+	expression.ReturnTypes = v.resolveTypes(returnTypes)
+
+	return expression, expression.ReturnTypes, nil
+}
+
+// The names in the arm are defined in a new block, so they can only be used in the expression of the arm
+func (v *validator) validateMatchArm(arm ast.MatchArm, bindingTypes []types.Type) (ast.MatchArm, []types.Type, error) {
+	names := make([]string, 0)
+	for i := 0; i < len(arm.Bindings); i++ {
+		if arm.Bindings[i].Identifier == token.WILDCARD {
+			continue
+		}
+
+		if isInList(arm.Bindings[i].Identifier, names) {
+			return ast.MatchArm{}, []types.Type{}, fmt.Errorf("Name %s is used more than once in match arm", arm.Bindings[i].Identifier)
+		}
+
+		names = append(names, arm.Bindings[i].Identifier)
+	}
+
+	//Outside functions the names are added to a new function scope, so they are not added to the global scope
+	isInFunction := v.symbolController.IsInFunction()
+	if !isInFunction {
+		err := v.symbolController.PushFunction([]symbolTable.Variable{})
+		if err != nil {
+			return ast.MatchArm{}, []types.Type{}, err
+		}
+	}
+
+	v.symbolController.PushBlock()
+	validated, armTypes, err := v.validateArmExpression(arm, bindingTypes)
+
+	v.symbolController.PopBlock()
+	if !isInFunction {
+		v.symbolController.PopFunction()
+	}
+
+	if err != nil {
+		return ast.MatchArm{}, []types.Type{}, err
+	}
+
+	arm.Expression = validated
+	return arm, armTypes, nil
+}
+
+// Defines the names of the arm, which can not shadow other variables, and validates the expression of the arm
+func (v *validator) validateArmExpression(arm ast.MatchArm, bindingTypes []types.Type) (ast.Node, []types.Type, error) {
+	for i := 0; i < len(arm.Bindings); i++ {
+		arm.Bindings[i].Type = bindingTypes[i]
+		if arm.Bindings[i].Identifier == token.WILDCARD {
+			continue
+		}
+
+		_, _, err := v.symbolController.DefineUniqueVariable(arm.Bindings[i].Identifier, bindingTypes[i])
+		if err != nil {
+			return nil, []types.Type{}, err
+		}
+	}
+
+	return v.validateExpression(arm.Expression)
+}
+
+// Gives the union type with its variants. If the type of the matched value is not known yet, it is inferred from the variant names in the arms
+func (v *validator) getMatchedUnionType(valueType types.Type, arms []ast.MatchArm) (types.UnionType, error) {
+	valueType = v.resolveType(valueType)
+	if isTypeVariable(valueType) {
+		for i := 0; i < len(arms); i++ {
+			if arms[i].Variant == "" {
+				continue
+			}
+
+			unionType, isVariant := v.getUnionTypeOfVariant(arms[i].Variant)
+			if !isVariant {
+				return types.UnionType{}, fmt.Errorf("Variant %s used in match arm is not defined", arms[i].Variant)
+			}
+
+			typeArguments := make([]types.Type, 0)
+			for j := 0; j < len(unionType.TypeParameters); j++ {
+				typeArguments = append(typeArguments, v.newTypeVariable())
+			}
+
+			err := v.unify(valueType, types.UnionType{Name: unionType.Name, TypeArguments: typeArguments})
+			if err != nil {
+				return types.UnionType{}, err
+			}
+
+			return v.getUnionTypeWithArguments(unionType, typeArguments), nil
+		}
+
+		return types.UnionType{}, fmt.Errorf("Could not infer the type of the value after match")
+	}
+
+	valueUnionType, isValueUnionType := valueType.(types.UnionType)
+	unionType, isUnionType := v.namedTypes[valueUnionType.Name].(types.UnionType)
+	if !isValueUnionType || !isUnionType {
+		return types.UnionType{}, fmt.Errorf("Match used on value of type %s, which is not a union type", valueType.String())
+	}
+
+	return v.getUnionTypeWithArguments(unionType, valueUnionType.TypeArguments), nil
+}
+
+// Replaces the type parameters in the types held by the variants with the type arguments
+func (v *validator) getUnionTypeWithArguments(unionType types.UnionType, typeArguments []types.Type) types.UnionType {
+	typeParameterToType := make(map[string]types.Type)
+	for i := 0; i < len(unionType.TypeParameters) && i < len(typeArguments); i++ {
+		typeParameterToType[unionType.TypeParameters[i]] = typeArguments[i]
+	}
+
+	withArguments := types.UnionType{Name: unionType.Name, TypeArguments: typeArguments, Variants: make([]types.UnionVariant, 0)}
+	for i := 0; i < len(unionType.Variants); i++ {
+		withArguments.Variants = append(withArguments.Variants, types.UnionVariant{
+			Name:  unionType.Variants[i].Name,
+			Types: v.instantiateTypes(unionType.Variants[i].Types, typeParameterToType),
+		})
+	}
+
+	return withArguments
+}
+
+func (v *validator) getUnionTypeOfVariant(variantName string) (types.UnionType, bool) {
+	for _, namedType := range v.namedTypes {
+		if unionType, isUnionType := namedType.(types.UnionType); isUnionType {
+			if _, isVariant := unionType.GetVariant(variantName); isVariant {
+				return unionType, true
+			}
+		}
+	}
+
+	return types.UnionType{}, false
+}
@@ -3,30 +3,85 @@ package validator
 import (
 	"compiler/ast"
 	"compiler/symbolTable"
+	"compiler/token"
 	"compiler/types"
 	"fmt"
 )
 
 func Validate(syntaxTree ast.Program) (ast.Program, error) {
-	v := validator{symbolController: symbolTable.NewSymbolController()}
+	v := validator{
+		symbolController:         symbolTable.NewSymbolController(),
+		globalFunctionSignatures: make(map[string]int),
+		namedTypes:               make(map[string]types.Type),
+		typeAliases:              make(map[string]ast.TypeDefinitionStatement),
+		typeVariables:            make(map[string]types.Type),
+		deferredConstraints:      make([]deferredConstraint, 0),
+		validatedModules:         make(map[string]bool),
+	}
+	v.symbolController.ForbidShadowing()
+
 	return v.validate(syntaxTree)
 }
 
 type validator struct {
 	symbolController *symbolTable.SymbolController
+
+	// Global functions added to the symbol controller before validation and the index of the statement defining them
+	globalFunctionSignatures map[string]int
+
+	// Types defined with type definitions
+	namedTypes map[string]types.Type
+
+	// Type aliases with the types they refer to
+	typeAliases map[string]ast.TypeDefinitionStatement
+
+	// The module being validated and the paths of the modules it imports, by the names used for them in the code
+	module           string
+	imports          map[string]string
+	validatedModules map[string]bool
+
+	// Type parameters of the generic global function being validated
+	typeParameters []string
+
+	// The inferred type of each type variable
+	typeVariables       map[string]types.Type
+	numTypeVariables    int
+	deferredConstraints []deferredConstraint
 }
 
+// The prelude, the modules and the program are validated in order, and their statements are merged into one program
 func (v *validator) validate(syntaxTre ast.Program) (ast.Program, error) {
-	validated, returnStatementsReturnTypes, err := v.validateBlockStatement(syntaxTre.Body, false)
+	prelude, err := parsePrelude()
 	if err != nil {
 		return ast.Program{}, err
 	}
 
-	if len(returnStatementsReturnTypes) != 0 {
-		return ast.Program{}, fmt.Errorf("Return statement in global scope")
+	validated, err := v.validateModule(preludeModule, prelude)
+	if err != nil {
+		return ast.Program{}, err
 	}
 
-	syntaxTre.Body = validated
+	v.symbolController.ShareModuleGlobals()
+
+	statements := validated.Statements
+
+	for i := 0; i < len(syntaxTre.Modules); i++ {
+		validated, err := v.validateModule(syntaxTre.Modules[i].Path, syntaxTre.Modules[i].Body)
+		if err != nil {
+			return ast.Program{}, fmt.Errorf("Error in module %s: %s", syntaxTre.Modules[i].Path, err.Error())
+		}
+
+		statements = append(statements, validated.Statements...)
+	}
+
+	validated, err = v.validateModule("", syntaxTre.Body)
+	if err != nil {
+		return ast.Program{}, err
+	}
+
+	statements = removeUnusedGlobals(statements, validated.Statements)
+	syntaxTre.Body = ast.BlockStatement{Statements: append(statements, validated.Statements...)}
+	syntaxTre.Modules = nil
 	return syntaxTre, nil
 }
 
@@ -36,49 +91,18 @@ func (v *validator) validateBlockStatement(block ast.BlockStatement, isFunction
 	for i := 0; i < len(block.Statements); i++ {
 		switch s := block.Statements[i].(type) {
 		case ast.AssignmentStatement:
-			functionIsRecursive := false
-			if funcDefinitionExpression, isFunctionDefinitionExpression := s.Value.(ast.DefineFunctionExpression); isFunctionDefinitionExpression {
-				if isUsingRecursion(funcDefinitionExpression, s.Variables[0].Identifier) {
-					functionIsRecursive = true
-					if funcDefinitionExpression.NoReturnTypesSpecified {
-						return ast.BlockStatement{}, returnStatementsReturnTypes, fmt.Errorf("Function definition using recursion must have specified return types")
-					}
-
-					if len(s.Variables) != 1 {
-						return ast.BlockStatement{}, returnStatementsReturnTypes, fmt.Errorf("Number of expression return types does not match number of variables in assignment statement")
-					}
-
-					err := v.addVariableToSymbolController(s.Variables[0].Identifier, s.Variables[0].Type, funcDefinitionExpression.FunctionType)
-					if err != nil {
-						return ast.BlockStatement{}, returnStatementsReturnTypes, err
-					}
-					s.Variables[0].Type = funcDefinitionExpression.FunctionType
-				}
-			}
-
-			validated, expressionReturnTypes, err := v.validateExpression(s.Value)
+			validated, err := v.validateAssignmentStatement(s)
 			if err != nil {
 				return ast.BlockStatement{}, returnStatementsReturnTypes, err
 			}
 
-			s.Value = validated
-			block.Statements[i] = s
+			block.Statements[i] = validated
 
-			if functionIsRecursive {
-				continue
-			}
+		case ast.TypeDefinitionStatement:
+			return ast.BlockStatement{}, returnStatementsReturnTypes, fmt.Errorf("Type %s must be defined in the global scope", s.Name)
 
-			if len(s.Variables) != len(expressionReturnTypes) {
-				return ast.BlockStatement{}, returnStatementsReturnTypes, fmt.Errorf("Number of expression return types does not match number of variables in assignment statement")
-			}
-
-			for i := 0; i < len(s.Variables); i++ {
-				err := v.addVariableToSymbolController(s.Variables[i].Identifier, s.Variables[i].Type, expressionReturnTypes[i])
-				if err != nil {
-					return ast.BlockStatement{}, returnStatementsReturnTypes, err
-				}
-				s.Variables[0].Type = expressionReturnTypes[i]
-			}
+		case ast.ImportStatement:
+			return ast.BlockStatement{}, returnStatementsReturnTypes, fmt.Errorf("Module %s must be imported in the global scope", s.Path)
 
 		case ast.ReturnStatement:
 			if !isFunction {
@@ -105,24 +129,77 @@ func (v *validator) validateBlockStatement(block ast.BlockStatement, isFunction
 	return block, returnStatementsReturnTypes, nil
 }
 
-func areListsMatching(list1, list2 []types.Type) bool {
-	for i := 0; i < len(list1); i++ {
-		if i >= len(list2) {
-			return false
+func (v *validator) validateAssignmentStatement(s ast.AssignmentStatement) (ast.AssignmentStatement, error) {
+	functionIsRecursive := false
+	if funcDefinitionExpression, isFunctionDefinitionExpression := s.Value.(ast.DefineFunctionExpression); isFunctionDefinitionExpression {
+		if isUsingRecursion(funcDefinitionExpression, s.Variables[0].Identifier) {
+			functionIsRecursive = true
+			if funcDefinitionExpression.NoReturnTypesSpecified {
+				return ast.AssignmentStatement{}, fmt.Errorf("Function definition using recursion must have specified return types")
+			}
+
+			if len(s.Variables) != 1 {
+				return ast.AssignmentStatement{}, fmt.Errorf("Number of expression return types does not match number of variables in assignment statement")
+			}
+
+			funcDefinitionExpression = v.addArgumentTypeVariables(funcDefinitionExpression)
+			s.Value = funcDefinitionExpression
+
+			err := v.addVariableToSymbolController(s.Variables[0].Identifier, s.Variables[0].Type, funcDefinitionExpression.FunctionType)
+			if err != nil {
+				return ast.AssignmentStatement{}, err
+			}
+			s.Variables[0].Type = funcDefinitionExpression.FunctionType
+		}
+	}
+
+	validated, expressionReturnTypes, err := v.validateExpression(s.Value)
+	if err != nil {
+		return ast.AssignmentStatement{}, err
+	}
+
+	s.Value = validated
+
+	if functionIsRecursive {
+		return s, nil
+	}
+
+	s.Value, expressionReturnTypes, err = v.matchTupleAssignment(len(s.Variables), s.Value, expressionReturnTypes)
+	if err != nil {
+		return ast.AssignmentStatement{}, err
+	}
+
+	if len(s.Variables) != len(expressionReturnTypes) {
+		return ast.AssignmentStatement{}, fmt.Errorf("Number of expression return types does not match number of variables in assignment statement")
+	}
+
+	for i := 0; i < len(s.Variables); i++ {
+		err := v.addVariableToSymbolController(s.Variables[i].Identifier, s.Variables[i].Type, expressionReturnTypes[i])
+		if err != nil {
+			return ast.AssignmentStatement{}, err
 		}
+		s.Variables[i].Type = expressionReturnTypes[i]
+	}
 
-		if list1[i].String() != list2[i].String() {
-			return false
+	return s, nil
+}
+
+func typeListString(typeList []types.Type) string {
+	output := "("
+	for i := 0; i < len(typeList); i++ {
+		output += typeList[i].String()
+		if i+1 != len(typeList) {
+			output += ", "
 		}
 	}
 
-	return true
+	return output + ")"
 }
 
 func generateOperatorError(operator string, leftTypes, rightTypes []types.Type) error {
 	errorMessage := "use of operator " + operator + " on "
 	if len(leftTypes) == 0 {
-		errorMessage += "no type "
+		errorMessage += "no type"
 	}
 
 	for i := 0; i < len(leftTypes); i++ {
@@ -134,7 +211,7 @@ func generateOperatorError(operator string, leftTypes, rightTypes []types.Type)
 
 	errorMessage += " and "
 	if len(rightTypes) == 0 {
-		errorMessage += "no type "
+		errorMessage += "no type"
 	}
 	for i := 0; i < len(rightTypes); i++ {
 		errorMessage += rightTypes[i].String()
@@ -143,11 +220,37 @@ func generateOperatorError(operator string, leftTypes, rightTypes []types.Type)
 		}
 	}
 
-	errorMessage += "not supported"
+	errorMessage += " not supported"
 
 	return fmt.Errorf(errorMessage)
 }
 
+func generateUnaryOperatorError(operator string, expressionTypes []types.Type) error {
+	errorMessage := "use of operator " + operator + " on "
+	if len(expressionTypes) == 0 {
+		errorMessage += "no type"
+	}
+
+	for i := 0; i < len(expressionTypes); i++ {
+		errorMessage += expressionTypes[i].String()
+		if i+1 != len(expressionTypes) {
+			errorMessage += ", "
+		}
+	}
+
+	errorMessage += " not supported"
+
+	return fmt.Errorf(errorMessage)
+}
+
+func isIntegerType(typeName string) bool {
+	return typeName == token.INT || typeName == token.LONG
+}
+
+func isNumberType(typeName string) bool {
+	return isIntegerType(typeName) || typeName == token.FLOAT || typeName == token.DOUBLE
+}
+
 func isInList(s string, sList []string) bool {
 	for i := 0; i < len(sList); i++ {
 		if s == sList[i] {
@@ -160,12 +263,18 @@ func isInList(s string, sList []string) bool {
 
 func (v *validator) addVariableToSymbolController(variableName string, variableType, expressionReturnType types.Type) error {
 	variableSymbol, alreadyDefined, isGlobal := v.symbolController.Resolve(variableName)
+
+	//Globals shared by the prelude can be shadowed
+	if alreadyDefined && isGlobal && variableSymbol.Name != v.symbolController.GetGlobalName(variableName) {
+		alreadyDefined = false
+	}
+
 	if alreadyDefined {
 		if isGlobal {
 			return fmt.Errorf("Attempt at mutating global variable")
 		}
 
-		if variableSymbol.Type.String() != expressionReturnType.String() {
+		if v.unify(variableSymbol.Type, expressionReturnType) != nil {
 			return fmt.Errorf("Attempt at changing variable type")
 		}
 
@@ -173,11 +282,14 @@ func (v *validator) addVariableToSymbolController(variableName string, variableT
 	}
 
 	if variableType.String() != types.NONE {
-		if variableType.String() != expressionReturnType.String() {
+		variableType, err := v.resolveNamedType(variableType)
+		if err != nil {
+			return err
+		}
+
+		if v.unify(variableType, expressionReturnType) != nil {
 			return fmt.Errorf("Given variable type does not match return type from expression")
 		}
-	} else {
-		variableType = expressionReturnType
 	}
 
 	v.symbolController.DefineVariable(variableName, expressionReturnType)
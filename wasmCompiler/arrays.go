@@ -2,18 +2,18 @@ package wasmCompiler
 
 import (
 	"compiler/ast"
-	"compiler/leb128"
 	"compiler/token"
 	"compiler/types"
 	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
 )
 
-func (c *compiler) createArrayCode(arrayElementType types.Type, arrayElementsExpression []ast.Node, functionLocals *functionLocals) ([]byte, error) {
-	outputCode := make([]byte, 0)
+func (c *compiler) createArrayCode(arrayElementType types.Type, arrayElementsExpression []ast.Node, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	outputCode := make([]wasmDecoder.Instruction, 0)
 
 	elementSizeInBytes, err := getArrayTypeElementSize(types.ArrayType{ElementType: arrayElementType})
 	if err != nil {
-		return []byte{}, err
+		return []wasmDecoder.Instruction{}, err
 	}
 
 	arrayFunctionIndex, arrayFunctionTypeIndex, _, err := c.getStandardFunctionIndexTypeIndexAndExtraArguments("array", []types.Type{})
@@ -24,42 +24,38 @@ func (c *compiler) createArrayCode(arrayElementType types.Type, arrayElementsExp
 	outputCode = append(outputCode, callIndirect(arrayFunctionTypeIndex)...)
 
 	arrayVariableIndex := functionLocals.defineLocalVariable(types.StandardType{Name: token.INT}, "", c.symbolController)
-	outputCode = append(outputCode, code.LOCAL_SET)
-	outputCode = append(outputCode, leb128.Int32ToULEB128((int32(arrayVariableIndex)))...)
+	outputCode = append(outputCode, localSet(arrayVariableIndex))
 
 	for i := 0; i < len(arrayElementsExpression); i++ {
 		expressionCode, err := c.compileExpression(arrayElementsExpression[i], functionLocals)
 		if err != nil {
-			return []byte{}, err
+			return []wasmDecoder.Instruction{}, err
 		}
 
 		indexCode := addConst(i)
 
-		variableCode := make([]byte, 0)
-		variableCode = append(variableCode, code.LOCAL_GET)
-		variableCode = append(variableCode, leb128.Int32ToULEB128(int32(arrayVariableIndex))...)
+		variableCode := []wasmDecoder.Instruction{localGet(arrayVariableIndex)}
 
 		curSetterCode, err := c.createSetArrayCode(arrayElementType, variableCode, expressionCode, indexCode)
 		if err != nil {
-			return []byte{}, err
+			return []wasmDecoder.Instruction{}, err
 		}
 
-		curSetterCode = append(curSetterCode, code.DROP)
+		curSetterCode = append(curSetterCode, instruction(code.DROP))
 		outputCode = append(outputCode, curSetterCode...)
 	}
 
-	outputCode = append(outputCode, code.LOCAL_GET)
-	outputCode = append(outputCode, leb128.Int32ToULEB128((int32(arrayVariableIndex)))...)
+	outputCode = append(outputCode, localGet(arrayVariableIndex))
 
 	return outputCode, nil
 }
 
-func (c *compiler) createSetArrayCode(arrayVariableType types.Type, arrayVariableCode, elementExpressionCode, indexExpressionCode []byte) ([]byte, error) {
-	outputCode := make([]byte, 0)
+func (c *compiler) createSetArrayCode(arrayVariableType types.Type, arrayVariableCode, elementExpressionCode, indexExpressionCode []wasmDecoder.Instruction) ([]wasmDecoder.Instruction, error) {
+	outputCode := make([]wasmDecoder.Instruction, 0)
 
 	setterFunctionIndex, setterTypeIndex, _, err := c.getStandardFunctionIndexTypeIndexAndExtraArguments("set", []types.Type{types.ArrayType{ElementType: arrayVariableType}})
 	if err != nil {
-		return []byte{}, err
+		return []wasmDecoder.Instruction{}, err
 	}
 
 	outputCode = append(outputCode, arrayVariableCode...)
@@ -71,8 +67,6 @@ func (c *compiler) createSetArrayCode(arrayVariableType types.Type, arrayVariabl
 	return outputCode, nil
 }
 
-func addConst(constValue int) []byte {
-	outputCode := []byte{code.I32_CONST}
-	outputCode = append(outputCode, leb128.Int32ToULEB128(int32(constValue))...)
-	return outputCode
+func addConst(constValue int) []wasmDecoder.Instruction {
+	return []wasmDecoder.Instruction{i32Const(int32(constValue))}
 }
@@ -170,7 +170,7 @@ const (
 	F64_MIN             uint8 = 164
 	F64_MAX             uint8 = 165
 	F64_COPYSIGN        uint8 = 166
-	I32_WRAP_F64        uint8 = 167
+	I32_WRAP_I64        uint8 = 167
 	I32_TRUNC_S_F32     uint8 = 168
 	I32_TRUNC_U_F32     uint8 = 169
 	I32_TRUNC_S_F64     uint8 = 170
@@ -195,6 +195,7 @@ const (
 	I64_REINTERPRET_F64 uint8 = 189
 	F32_REINTERPRET_I32 uint8 = 190
 	F64_REINTERPRET_I64 uint8 = 191
+	MISC_PREFIX         uint8 = 252
 	DESC_FUNCTION       uint8 = 0
 	DESC_TABLE          uint8 = 1
 	DESC_MEMORY         uint8 = 2
@@ -204,3 +205,15 @@ const (
 	IMMUTABLE           uint8 = 0
 	MUTABLE             uint8 = 1
 )
+
+// Instructions encoded as MISC_PREFIX followed by the sub opcode
+const (
+	I32_TRUNC_SAT_F32_S uint8 = 0
+	I32_TRUNC_SAT_F32_U uint8 = 1
+	I32_TRUNC_SAT_F64_S uint8 = 2
+	I32_TRUNC_SAT_F64_U uint8 = 3
+	I64_TRUNC_SAT_F32_S uint8 = 4
+	I64_TRUNC_SAT_F32_U uint8 = 5
+	I64_TRUNC_SAT_F64_S uint8 = 6
+	I64_TRUNC_SAT_F64_U uint8 = 7
+)
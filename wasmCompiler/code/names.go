@@ -0,0 +1,189 @@
+package code
+
+// The names of the instructions in the WebAssembly text format
+var InstructionNames = map[uint8]string{
+	UNREACHABLE:         "unreachable",
+	NOP:                 "nop",
+	BLOCK:               "block",
+	LOOP:                "loop",
+	IF:                  "if",
+	ELSE:                "else",
+	END:                 "end",
+	BR:                  "br",
+	BR_IF:               "br_if",
+	BR_TABLE:            "br_table",
+	RETURN:              "return",
+	CALL:                "call",
+	CALL_INDIRECT:       "call_indirect",
+	DROP:                "drop",
+	SELECT:              "select",
+	LOCAL_GET:           "local.get",
+	LOCAL_SET:           "local.set",
+	LOCAL_TEE:           "local.tee",
+	GLOBAL_GET:          "global.get",
+	GLOBAL_SET:          "global.set",
+	I32_LOAD:            "i32.load",
+	I64_LOAD:            "i64.load",
+	F32_LOAD:            "f32.load",
+	F64_LOAD:            "f64.load",
+	I32_LOAD8_S:         "i32.load8_s",
+	I32_LOAD8_U:         "i32.load8_u",
+	I32_LOAD16_S:        "i32.load16_s",
+	I32_LOAD16_U:        "i32.load16_u",
+	I64_LOAD8_S:         "i64.load8_s",
+	I64_LOAD8_U:         "i64.load8_u",
+	I64_LOAD16_S:        "i64.load16_s",
+	I64_LOAD16_U:        "i64.load16_u",
+	I64_LOAD32_S:        "i64.load32_s",
+	I64_LOAD32_U:        "i64.load32_u",
+	I32_STORE:           "i32.store",
+	I64_STORE:           "i64.store",
+	F32_STORE:           "f32.store",
+	F64_STORE:           "f64.store",
+	I32_STORE8:          "i32.store8",
+	I32_STORE16:         "i32.store16",
+	I64_STORE8:          "i64.store8",
+	I64_STORE16:         "i64.store16",
+	I64_STORE32:         "i64.store32",
+	MEMORY_SIZE:         "memory.size",
+	MEMORY_GROW:         "memory.grow",
+	I32_CONST:           "i32.const",
+	I64_CONST:           "i64.const",
+	F32_CONST:           "f32.const",
+	F64_CONST:           "f64.const",
+	I32_EQZ:             "i32.eqz",
+	I32_EQ:              "i32.eq",
+	I32_NE:              "i32.ne",
+	I32_LT_S:            "i32.lt_s",
+	I32_LT_U:            "i32.lt_u",
+	I32_GT_S:            "i32.gt_s",
+	I32_GT_U:            "i32.gt_u",
+	I32_LE_S:            "i32.le_s",
+	I32_LE_U:            "i32.le_u",
+	I32_GE_S:            "i32.ge_s",
+	I32_GE_U:            "i32.ge_u",
+	I64_EQZ:             "i64.eqz",
+	I64_EQ:              "i64.eq",
+	I64_NE:              "i64.ne",
+	I64_LT_S:            "i64.lt_s",
+	I64_LT_U:            "i64.lt_u",
+	I64_GT_S:            "i64.gt_s",
+	I64_GT_U:            "i64.gt_u",
+	I64_LE_S:            "i64.le_s",
+	I64_LE_U:            "i64.le_u",
+	I64_GE_S:            "i64.ge_s",
+	I64_GE_U:            "i64.ge_u",
+	F32_EQ:              "f32.eq",
+	F32_NE:              "f32.ne",
+	F32_LT:              "f32.lt",
+	F32_GT:              "f32.gt",
+	F32_LE:              "f32.le",
+	F32_GE:              "f32.ge",
+	F64_EQ:              "f64.eq",
+	F64_NE:              "f64.ne",
+	F64_LT:              "f64.lt",
+	F64_GT:              "f64.gt",
+	F64_LE:              "f64.le",
+	F64_GE:              "f64.ge",
+	I32_CLZ:             "i32.clz",
+	I32_CTZ:             "i32.ctz",
+	I32_POPCNT:          "i32.popcnt",
+	I32_ADD:             "i32.add",
+	I32_SUB:             "i32.sub",
+	I32_MUL:             "i32.mul",
+	I32_DIV_S:           "i32.div_s",
+	I32_DIV_U:           "i32.div_u",
+	I32_REM_S:           "i32.rem_s",
+	I32_REM_U:           "i32.rem_u",
+	I32_AND:             "i32.and",
+	I32_OR:              "i32.or",
+	I32_XOR:             "i32.xor",
+	I32_SHL:             "i32.shl",
+	I32_SHR_S:           "i32.shr_s",
+	I32_SHR_U:           "i32.shr_u",
+	I32_ROTL:            "i32.rotl",
+	I32_ROTR:            "i32.rotr",
+	I64_CLZ:             "i64.clz",
+	I64_CTZ:             "i64.ctz",
+	I64_POPCNT:          "i64.popcnt",
+	I64_ADD:             "i64.add",
+	I64_SUB:             "i64.sub",
+	I64_MUL:             "i64.mul",
+	I64_DIV_S:           "i64.div_s",
+	I64_DIV_U:           "i64.div_u",
+	I64_REM_S:           "i64.rem_s",
+	I64_REM_U:           "i64.rem_u",
+	I64_AND:             "i64.and",
+	I64_OR:              "i64.or",
+	I64_XOR:             "i64.xor",
+	I64_SHL:             "i64.shl",
+	I64_SHR_S:           "i64.shr_s",
+	I64_SHR_U:           "i64.shr_u",
+	I64_ROTL:            "i64.rotl",
+	I64_ROTR:            "i64.rotr",
+	F32_ABS:             "f32.abs",
+	F32_NEG:             "f32.neg",
+	F32_CEIL:            "f32.ceil",
+	F32_FLOOR:           "f32.floor",
+	F32_TRUNC:           "f32.trunc",
+	F32_NEAREST:         "f32.nearest",
+	F32_SQRT:            "f32.sqrt",
+	F32_ADD:             "f32.add",
+	F32_SUB:             "f32.sub",
+	F32_MUL:             "f32.mul",
+	F32_DIV:             "f32.div",
+	F32_MIN:             "f32.min",
+	F32_MAX:             "f32.max",
+	F32_COPYSIGN:        "f32.copysign",
+	F64_ABS:             "f64.abs",
+	F64_NEG:             "f64.neg",
+	F64_CEIL:            "f64.ceil",
+	F64_FLOOR:           "f64.floor",
+	F64_TRUNC:           "f64.trunc",
+	F64_NEAREST:         "f64.nearest",
+	F64_SQRT:            "f64.sqrt",
+	F64_ADD:             "f64.add",
+	F64_SUB:             "f64.sub",
+	F64_MUL:             "f64.mul",
+	F64_DIV:             "f64.div",
+	F64_MIN:             "f64.min",
+	F64_MAX:             "f64.max",
+	F64_COPYSIGN:        "f64.copysign",
+	I32_WRAP_I64:        "i32.wrap_i64",
+	I32_TRUNC_S_F32:     "i32.trunc_f32_s",
+	I32_TRUNC_U_F32:     "i32.trunc_f32_u",
+	I32_TRUNC_S_F64:     "i32.trunc_f64_s",
+	I32_TRUNC_U_F64:     "i32.trunc_f64_u",
+	I64_EXTEND_S_I32:    "i64.extend_i32_s",
+	I64_EXTEND_U_I32:    "i64.extend_i32_u",
+	I64_TRUNC_S_F32:     "i64.trunc_f32_s",
+	I64_TRUNC_U_F32:     "i64.trunc_f32_u",
+	I64_TRUNC_S_F64:     "i64.trunc_f64_s",
+	I64_TRUNC_U_F64:     "i64.trunc_f64_u",
+	F32_CONVERT_S_I32:   "f32.convert_i32_s",
+	F32_CONVERT_U_I32:   "f32.convert_i32_u",
+	F32_CONVERT_S_I64:   "f32.convert_i64_s",
+	F32_CONVERT_U_I64:   "f32.convert_i64_u",
+	F32_DEMOTE_F64:      "f32.demote_f64",
+	F64_CONVERT_S_I32:   "f64.convert_i32_s",
+	F64_CONVERT_U_I32:   "f64.convert_i32_u",
+	F64_CONVERT_S_I64:   "f64.convert_i64_s",
+	F64_CONVERT_U_I64:   "f64.convert_i64_u",
+	F64_PROMOTE_F32:     "f64.promote_f32",
+	I32_REINTERPRET_F32: "i32.reinterpret_f32",
+	I64_REINTERPRET_F64: "i64.reinterpret_f64",
+	F32_REINTERPRET_I32: "f32.reinterpret_i32",
+	F64_REINTERPRET_I64: "f64.reinterpret_i64",
+}
+
+// The names of the instructions encoded as MISC_PREFIX followed by the sub opcode
+var MiscInstructionNames = map[uint8]string{
+	I32_TRUNC_SAT_F32_S: "i32.trunc_sat_f32_s",
+	I32_TRUNC_SAT_F32_U: "i32.trunc_sat_f32_u",
+	I32_TRUNC_SAT_F64_S: "i32.trunc_sat_f64_s",
+	I32_TRUNC_SAT_F64_U: "i32.trunc_sat_f64_u",
+	I64_TRUNC_SAT_F32_S: "i64.trunc_sat_f32_s",
+	I64_TRUNC_SAT_F32_U: "i64.trunc_sat_f32_u",
+	I64_TRUNC_SAT_F64_S: "i64.trunc_sat_f64_s",
+	I64_TRUNC_SAT_F64_U: "i64.trunc_sat_f64_u",
+}
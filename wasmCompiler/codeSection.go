@@ -2,35 +2,33 @@ package wasmCompiler
 
 import (
 	"compiler/ast"
-	"compiler/leb128"
 	"compiler/symbolTable"
 	"compiler/types"
 	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
 )
 
 type codeSection struct {
-	numFunctions int
-	functionCode [][]uint8
+	functionBodies []wasmDecoder.FunctionBody
 }
 
 func newCodeSection() *codeSection {
 	return &codeSection{
-		numFunctions: 0,
-		functionCode: make([][]uint8, 0),
+		functionBodies: make([]wasmDecoder.FunctionBody, 0),
 	}
 }
 
-func (s *codeSection) addFunction(newFunctionCode []uint8, index int) {
-	for len(s.functionCode) <= index {
-		s.functionCode = append(s.functionCode, nil)
+//Functions are compiled in another order than they are defined in, so the body is put at the index of the function
+func (s *codeSection) addFunction(body wasmDecoder.FunctionBody, index int) {
+	for len(s.functionBodies) <= index {
+		s.functionBodies = append(s.functionBodies, wasmDecoder.FunctionBody{})
 	}
 
-	s.functionCode[index] = encodeVector(newFunctionCode)
-	s.numFunctions++
+	s.functionBodies[index] = body
 }
 
-func (s *codeSection) toByteCode() []uint8 {
-	return createSection(code.SECTION_CODE, append(leb128.Int32ToULEB128(int32(s.numFunctions)), flatten(s.functionCode)...))
+func (s *codeSection) addToModule(m *wasmDecoder.Module) {
+	m.Code = s.functionBodies
 }
 
 type functionLocals struct {
@@ -38,6 +36,7 @@ type functionLocals struct {
 		partType uint8
 		num      int32
 	}
+	names map[int]string //The names of the parameters and variables by local index. Locals used by the compiler have no name
 }
 
 func newFunctionLocals() *functionLocals {
@@ -46,11 +45,15 @@ func newFunctionLocals() *functionLocals {
 			partType uint8
 			num      int32
 		}, 0),
+		names: make(map[int]string),
 	}
 }
 
 func (l *functionLocals) defineLocalVariable(variableType types.Type, variableName string, symbolController *symbolTable.SymbolController) int {
 	_, variableIndex := symbolController.DefineVariable(variableName, variableType)
+	if variableName != "" {
+		l.names[variableIndex] = variableName
+	}
 
 	if len(l.parts) == 0 || l.parts[len(l.parts)-1].partType != variableType.ByteCode() {
 		l.parts = append(l.parts, struct {
@@ -64,22 +67,23 @@ func (l *functionLocals) defineLocalVariable(variableType types.Type, variableNa
 	return variableIndex
 }
 
-func (l *functionLocals) toByteCode() []uint8 {
-	output := make([]uint8, 0)
-	output = append(output, leb128.Int32ToULEB128(int32(len(l.parts)))...)
-
+//Gives the function body with the locals and the instructions
+func (l *functionLocals) functionBody(instructions []wasmDecoder.Instruction) wasmDecoder.FunctionBody {
+	body := wasmDecoder.FunctionBody{Locals: make([]wasmDecoder.Locals, 0), Instructions: instructions}
 	for i := 0; i < len(l.parts); i++ {
-		output = append(output, leb128.Int32ToULEB128(l.parts[i].num)...)
-		output = append(output, l.parts[i].partType)
+		body.Locals = append(body.Locals, wasmDecoder.Locals{Count: uint32(l.parts[i].num), ValueType: l.parts[i].partType})
 	}
 
-	return output
+	return body
 }
 
-func (c *compiler) compileFunction(functionBody ast.BlockStatement, functionIndex int) error {
-	bodyByteCode := make([]uint8, 0)
+func (c *compiler) compileFunction(functionBody ast.BlockStatement, arguments []ast.Variable, functionIndex int) error {
+	bodyByteCode := make([]wasmDecoder.Instruction, 0)
 
 	localVariables := newFunctionLocals()
+	for i := 0; i < len(arguments); i++ {
+		localVariables.names[i] = arguments[i].Identifier
+	}
 
 	for i := 0; i < len(functionBody.Statements); i++ {
 		switch s := functionBody.Statements[i].(type) {
@@ -91,6 +95,7 @@ func (c *compiler) compileFunction(functionBody ast.BlockStatement, functionInde
 
 			bodyByteCode = append(bodyByteCode, expressionCode...)
 
+			variableIndexes := make([]int, 0)
 			for i := 0; i < len(s.Variables); i++ {
 				variableSymbol, isDefined, _ := c.symbolController.Resolve(s.Variables[i].Identifier)
 				variableIndex := int(variableSymbol.Index)
@@ -100,11 +105,15 @@ func (c *compiler) compileFunction(functionBody ast.BlockStatement, functionInde
 
 				}
 
-				bodyByteCode = append(bodyByteCode, code.LOCAL_SET)
-				bodyByteCode = append(bodyByteCode, leb128.Int32ToULEB128(int32(variableIndex))...)
+				variableIndexes = append(variableIndexes, variableIndex)
+			}
+
+			//The last value is on the top of the stack so the variables are set in reverse order
+			for i := len(variableIndexes) - 1; i >= 0; i-- {
+				bodyByteCode = append(bodyByteCode, localSet(variableIndexes[i]))
 			}
 		case ast.ReturnStatement:
-			returnExpressionsCode := make([]uint8, 0)
+			returnExpressionsCode := make([]wasmDecoder.Instruction, 0)
 
 			for i := 0; i < len(s.Expressions); i++ {
 				expressionCode, err := c.compileExpression(s.Expressions[i], localVariables)
@@ -116,22 +125,20 @@ func (c *compiler) compileFunction(functionBody ast.BlockStatement, functionInde
 			}
 
 			bodyByteCode = append(bodyByteCode, returnExpressionsCode...)
-			bodyByteCode = append(bodyByteCode, code.RETURN)
+			bodyByteCode = append(bodyByteCode, instruction(code.RETURN))
 		}
 	}
 
-	bodyByteCode = append(bodyByteCode, code.END)
-
-	functionCode := localVariables.toByteCode()
-	functionCode = append(functionCode, bodyByteCode...)
+	bodyByteCode = append(bodyByteCode, instruction(code.END))
 
-	c.codeSection.addFunction(functionCode, functionIndex)
+	c.codeSection.addFunction(localVariables.functionBody(bodyByteCode), functionIndex)
+	c.localNames[functionIndex] = localVariables.names
 
 	return nil
 }
 
-func flatten(a [][]uint8) []uint8 {
-	output := make([]uint8, 0)
+func flatten(a [][]wasmDecoder.Instruction) []wasmDecoder.Instruction {
+	output := make([]wasmDecoder.Instruction, 0)
 	for i := 0; i < len(a); i++ {
 		output = append(output, a[i]...)
 	}
@@ -1,8 +1,8 @@
 package wasmCompiler
 
 import (
-	"compiler/leb128"
 	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
 )
 
 type elementSection struct {
@@ -17,38 +17,21 @@ func (s *elementSection) addFunction(functionIndex int) {
 	s.functions = append(s.functions, functionIndex)
 }
 
-func (s *elementSection) toByteCode() []uint8 {
+//One element segment puts every function in the table, starting at table index 0
+func (s *elementSection) addToModule(m *wasmDecoder.Module) {
 	if len(s.functions) == 0 {
-		return []uint8{}
+		return
 	}
 
-	byteCode := leb128.Int32ToULEB128(int32(1))                     //Number of elements
-	byteCode = append(byteCode, leb128.Int32ToULEB128(int32(0))...) //Element index
-
-	byteCode = append(byteCode, code.I32_CONST) //Expression
-	byteCode = append(byteCode, leb128.Int32ToULEB128(int32(0))...)
-	byteCode = append(byteCode, code.END)
-
-	byteCode = append(byteCode, leb128.Int32ToULEB128(int32(len(s.functions)))...)
+	element := wasmDecoder.Element{
+		TableIndex: 0,
+		Offset:     []wasmDecoder.Instruction{i32Const(0), instruction(code.END)},
+		Functions:  make([]uint32, 0),
+	}
 
 	for i := 0; i < len(s.functions); i++ {
-		byteCode = append(byteCode, leb128.Int32ToULEB128(int32(s.functions[i]))...)
+		element.Functions = append(element.Functions, uint32(s.functions[i]))
 	}
 
-	return createSection(code.SECTION_ELEMENT, byteCode)
+	m.Elements = []wasmDecoder.Element{element}
 }
-
-/*
-Example of element section:
-
-9 	Section element
-8
-1 	num elements
-0 	element index
-65	i32 const
-0	const value
-11	end
-2	num functions
-0	function index
-1	function index
-*/
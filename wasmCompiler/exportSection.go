@@ -1,8 +1,8 @@
 package wasmCompiler
 
 import (
-	"compiler/leb128"
 	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
 )
 
 type export struct {
@@ -25,22 +25,12 @@ func (s *exportSection) addExport(functionName string, functionIndex int) {
 	})
 }
 
-func (s *exportSection) toByteCode() []uint8 {
-	byteCode := leb128.Int32ToULEB128(int32(len(s.exports)))
-
+func (s *exportSection) addToModule(m *wasmDecoder.Module) {
 	for i := 0; i < len(s.exports); i++ {
-		byteCode = append(byteCode, stringToByteCode(s.exports[i].functionName)...)
-		byteCode = append(byteCode, leb128.Int32ToULEB128(s.exports[i].functionIndex)...)
+		m.Exports = append(m.Exports, wasmDecoder.Export{
+			Name:  s.exports[i].functionName,
+			Kind:  code.DESC_FUNCTION,
+			Index: uint32(s.exports[i].functionIndex),
+		})
 	}
-
-	return createSection(code.SECTION_EXPORT, byteCode)
-}
-
-func stringToByteCode(s string) []uint8 {
-	byteCode := leb128.Int32ToULEB128(int32(len(s)))
-
-	byteCode = append(byteCode, []byte(s)...)
-	byteCode = append(byteCode, 0)
-
-	return byteCode
 }
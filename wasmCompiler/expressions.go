@@ -2,27 +2,27 @@ package wasmCompiler
 
 import (
 	"compiler/ast"
-	"compiler/leb128"
 	"compiler/token"
 	"compiler/types"
 	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
 	"fmt"
 	"reflect"
 )
 
-func (c *compiler) compileExpression(expression ast.Node, functionLocals *functionLocals) ([]uint8, error) {
-	byteCode := make([]uint8, 0)
+func (c *compiler) compileExpression(expression ast.Node, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	byteCode := make([]wasmDecoder.Instruction, 0)
 
 	switch s := expression.(type) {
 	case ast.OperatorExpression:
 		left, err := c.compileExpression(s.LeftSide, functionLocals)
 		if err != nil {
-			return []uint8{}, err
+			return []wasmDecoder.Instruction{}, err
 		}
 
 		right, err := c.compileExpression(s.RightSide, functionLocals)
 		if err != nil {
-			return []uint8{}, err
+			return []wasmDecoder.Instruction{}, err
 		}
 
 		byteCode = append(byteCode, left...)
@@ -30,19 +30,37 @@ func (c *compiler) compileExpression(expression ast.Node, functionLocals *functi
 
 		operatorCodeIndex, err := getOperatorCode(s.Operator, s.Type.String())
 		if err != nil {
-			return []byte{}, err
+			return []wasmDecoder.Instruction{}, err
 		}
 
-		byteCode = append(byteCode, operatorCodeIndex)
+		byteCode = append(byteCode, instruction(operatorCodeIndex))
+
+	case ast.UnaryOperatorExpression:
+		expressionCode, err := c.compileExpression(s.Expression, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		unaryOperatorCode, err := getUnaryOperatorCode(s.Operator, s.Type.String(), expressionCode)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		byteCode = append(byteCode, unaryOperatorCode...)
 
 	case ast.ExecuteFunctionExpression:
+		promoteFloatArguments := c.isFloatCallToDoubleStandardFunction(s)
+
 		for i := 0; i < len(s.Arguments); i++ {
 			argumentBytecode, err := c.compileExpression(s.Arguments[i], functionLocals)
 			if err != nil {
-				return []uint8{}, err
+				return []wasmDecoder.Instruction{}, err
 			}
 
 			byteCode = append(byteCode, argumentBytecode...)
+			if promoteFloatArguments {
+				byteCode = append(byteCode, instruction(code.F64_PROMOTE_F32))
+			}
 		}
 
 		tableIndex := -1
@@ -54,23 +72,46 @@ func (c *compiler) compileExpression(expression ast.Node, functionLocals *functi
 		case ast.DefineFunctionExpression:
 			tableIndex, functionTypeIndex, err = c.addLocalFunction(f)
 			if err != nil {
-				return []uint8{}, err
+				return []wasmDecoder.Instruction{}, err
 			}
 			isGlobal = false
 
 		case ast.Variable:
+			if c.isGenericFunction(f.Identifier) {
+				tableIndex, functionTypeIndex, err = c.getGenericFunctionInstance(f.Identifier, f.Type)
+				if err != nil {
+					return []wasmDecoder.Instruction{}, err
+				}
+
+				break
+			}
+
 			variableSymbol, isDefined, symbolIsGlobal := c.symbolController.Resolve(f.Identifier)
 			if !isDefined {
+				if isInlineStandardFunction(f.Identifier) {
+					argumentTypes := make([]types.Type, 0)
+					for i := 0; i < len(s.Arguments); i++ {
+						argumentTypes = append(argumentTypes, s.Arguments[i].GetExpressionReturnType()...)
+					}
+
+					inlineCode, err := getInlineStandardFunctionCode(f.Identifier, argumentTypes)
+					if err != nil {
+						return []wasmDecoder.Instruction{}, err
+					}
+
+					return append(byteCode, inlineCode...), nil
+				}
+
 				if isOpenStandardFunction[f.Identifier] {
 					argumentTypes := make([]types.Type, 0)
 					for i := 0; i < len(s.Arguments); i++ {
 						argumentTypes = append(argumentTypes, s.Arguments[i].GetExpressionReturnType()...)
 					}
 
-					var extraArguments []byte
+					var extraArguments []wasmDecoder.Instruction
 					tableIndex, functionTypeIndex, extraArguments, err = c.getStandardFunctionIndexTypeIndexAndExtraArguments(f.Identifier, argumentTypes)
 					if err != nil {
-						return []byte{}, err
+						return []wasmDecoder.Instruction{}, err
 					}
 
 					byteCode = append(byteCode, extraArguments...)
@@ -78,71 +119,172 @@ func (c *compiler) compileExpression(expression ast.Node, functionLocals *functi
 					break
 				}
 
-				return []uint8{}, fmt.Errorf("Internal compiler error: undefined identifier")
+				return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: undefined identifier")
 			}
 
 			isGlobal = symbolIsGlobal
 
 			symbolType, isFunction := variableSymbol.Type.(types.FunctionType)
 			if !isFunction {
-				return []uint8{}, fmt.Errorf("Internal compiler error: type of variable in function given to compile expression not of type function")
+				return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: type of variable in function given to compile expression not of type function")
 			}
 
-			functionTypeIndex = symbolType.TypeIndex
+			//Local variables given a function value do not have the type index in their type, so the index is looked up
+			functionTypeIndex = c.typeSection.addType(symbolType)
 			tableIndex = int(variableSymbol.Index)
 
 		default:
-			return []uint8{}, fmt.Errorf("Internal compiler error: expression of type execute function does not operate of function")
+			return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: expression of type execute function does not operate of function")
 		}
 
 		if isGlobal {
-			byteCode = append(byteCode, code.I32_CONST)
+			byteCode = append(byteCode, i32Const(int32(tableIndex)))
 		} else {
-			byteCode = append(byteCode, code.LOCAL_GET)
+			byteCode = append(byteCode, localGet(tableIndex))
 		}
 
-		byteCode = append(byteCode, leb128.Int32ToULEB128(int32(tableIndex))...)
 		byteCode = append(byteCode, callIndirect(functionTypeIndex)...)
 
+		if promoteFloatArguments {
+			byteCode = append(byteCode, instruction(code.F32_DEMOTE_F64))
+		}
+
 	case ast.DefineFunctionExpression:
 		functionIndex, _, err := c.addLocalFunction(s)
 		if err != nil {
-			return []uint8{}, err
+			return []wasmDecoder.Instruction{}, err
 		}
 
-		byteCode = append(byteCode, code.I32_CONST)
-		byteCode = append(byteCode, leb128.Int32ToULEB128(int32(functionIndex))...)
+		byteCode = append(byteCode, i32Const(int32(functionIndex)))
 
 	case ast.Variable:
+		if c.isGenericFunction(s.Identifier) {
+			functionIndex, _, err := c.getGenericFunctionInstance(s.Identifier, s.Type)
+			if err != nil {
+				return []wasmDecoder.Instruction{}, err
+			}
+
+			byteCode = append(byteCode, i32Const(int32(functionIndex)))
+			break
+		}
+
 		variableSymbol, isDefined, isGlobal := c.symbolController.Resolve(s.Identifier)
 		if !isDefined {
-			return []uint8{}, fmt.Errorf("undefined identifier")
+			return []wasmDecoder.Instruction{}, fmt.Errorf("undefined identifier")
 		}
 
-		if _, isFunction := variableSymbol.Type.(types.FunctionType); isFunction {
-			byteCode = append(byteCode, code.I32_CONST)
-			byteCode = append(byteCode, leb128.Int32ToULEB128(int32(variableSymbol.Index))...)
+		//Global functions are used by their table index, while local variables of function type hold table indexes
+		if _, isFunction := variableSymbol.Type.(types.FunctionType); isFunction && isGlobal {
+			byteCode = append(byteCode, i32Const(variableSymbol.Index))
 			break
 		}
 
 		if isGlobal {
-			byteCode = append(byteCode, code.GLOBAL_GET)
+			byteCode = append(byteCode, indexInstruction(code.GLOBAL_GET, int(variableSymbol.Index)))
 		} else {
-			byteCode = append(byteCode, code.LOCAL_GET)
+			byteCode = append(byteCode, localGet(int(variableSymbol.Index)))
 		}
 
-		byteCode = append(byteCode, leb128.Int32ToULEB128(int32(variableSymbol.Index))...)
-
 	case ast.ArrayExpression:
 		arrayType_ := s.Type
 		arrayType, ok := arrayType_.(types.ArrayType)
 		if !ok {
-			return []byte{}, fmt.Errorf("Type in ast.ArrayExpression not array")
+			return []wasmDecoder.Instruction{}, fmt.Errorf("Type in ast.ArrayExpression not array")
 		}
 
 		expressionCode, err := c.createArrayCode(arrayType.ElementType, s.ElementsExpressions, functionLocals)
 		if err != nil {
-			return []byte{}, err
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		byteCode = append(byteCode, expressionCode...)
+
+	case ast.RecordExpression:
+		expressionCode, err := c.createRecordCode(s, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		byteCode = append(byteCode, expressionCode...)
+
+	case ast.RecordUpdateExpression:
+		expressionCode, err := c.createRecordUpdateCode(s, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		byteCode = append(byteCode, expressionCode...)
+
+	case ast.FieldAccessExpression:
+		recordType, isRecordType := s.Record.GetExpressionReturnType()[0].(types.RecordType)
+		if !isRecordType {
+			return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: record in ast.FieldAccessExpression not of record type")
+		}
+
+		recordCode, err := c.compileExpression(s.Record, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		expressionCode, err := createFieldAccessCode(recordType, s.Field, recordCode)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		byteCode = append(byteCode, expressionCode...)
+
+	case ast.VariantExpression:
+		expressionCode, err := c.createVariantCode(s, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		byteCode = append(byteCode, expressionCode...)
+
+	case ast.MatchExpression:
+		expressionCode, err := c.createMatchCode(s, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		byteCode = append(byteCode, expressionCode...)
+
+	case ast.LetExpression:
+		expressionCode, err := c.createLetCode(s, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		byteCode = append(byteCode, expressionCode...)
+
+	case ast.TupleExpression:
+		expressionCode, err := c.createTupleCode(s, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		byteCode = append(byteCode, expressionCode...)
+
+	case ast.NewTypeExpression:
+		expressionCode, err := c.compileExpression(s.Value, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		byteCode = append(byteCode, expressionCode...)
+
+	case ast.DestructureExpression:
+		expressionCode, err := c.createDestructureCode(s, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		byteCode = append(byteCode, expressionCode...)
+
+	case ast.TupleUnpackExpression:
+		expressionCode, err := c.createTupleUnpackCode(s, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
 		}
 
 		byteCode = append(byteCode, expressionCode...)
@@ -150,17 +292,17 @@ func (c *compiler) compileExpression(expression ast.Node, functionLocals *functi
 	case ast.IfExpression:
 		conditionalCode, err := c.compileExpression(s.Condition, functionLocals)
 		if err != nil {
-			return []byte{}, err
+			return []wasmDecoder.Instruction{}, err
 		}
 
 		trueExpressionCode, err := c.compileExpression(s.TrueExpression, functionLocals)
 		if err != nil {
-			return []byte{}, err
+			return []wasmDecoder.Instruction{}, err
 		}
 
 		falseExpressionCode, err := c.compileExpression(s.FalseExpression, functionLocals)
 		if err != nil {
-			return []byte{}, err
+			return []wasmDecoder.Instruction{}, err
 		}
 
 		ifTypeIndex := c.typeSection.addType(types.FunctionType{
@@ -168,29 +310,29 @@ func (c *compiler) compileExpression(expression ast.Node, functionLocals *functi
 			ReturnTypes:   s.ReturnType,
 		})
 
-		expressionCode := make([]uint8, 0)
+		expressionCode := make([]wasmDecoder.Instruction, 0)
 		expressionCode = append(expressionCode, conditionalCode...)
-		expressionCode = append(expressionCode, code.IF)
-		expressionCode = append(expressionCode, leb128.Int32ToULEB128(int32(ifTypeIndex))...)
+		expressionCode = append(expressionCode, blockInstruction(code.IF, ifTypeIndex))
 		expressionCode = append(expressionCode, trueExpressionCode...)
-		expressionCode = append(expressionCode, code.ELSE)
+		expressionCode = append(expressionCode, instruction(code.ELSE))
 		expressionCode = append(expressionCode, falseExpressionCode...)
-		expressionCode = append(expressionCode, code.END)
+		expressionCode = append(expressionCode, instruction(code.END))
 
 		return expressionCode, nil
 
 	case ast.IntExpression:
-		byteCode = append(byteCode, code.I32_CONST)
-		byteCode = append(byteCode, leb128.Int32ToLEB128(s.Value)...)
+		byteCode = append(byteCode, i32Const(s.Value))
 	case ast.FloatExpression:
-		byteCode = append(byteCode, code.F32_CONST)
-		byteCode = append(byteCode, float32ToLittleEndian(float32(s.Value))...)
+		byteCode = append(byteCode, f32Const(float32(s.Value)))
+	case ast.LongExpression:
+		byteCode = append(byteCode, i64Const(s.Value))
+	case ast.DoubleExpression:
+		byteCode = append(byteCode, f64Const(s.Value))
 	case ast.BoolExpression:
-		byteCode = append(byteCode, code.I32_CONST)
 		if s.Value {
-			byteCode = append(byteCode, leb128.Int32ToULEB128(1)...)
+			byteCode = append(byteCode, i32Const(1))
 		} else {
-			byteCode = append(byteCode, leb128.Int32ToULEB128(0)...)
+			byteCode = append(byteCode, i32Const(0))
 		}
 	case ast.StringExpression:
 		//TODO :)
@@ -201,89 +343,110 @@ func (c *compiler) compileExpression(expression ast.Node, functionLocals *functi
 	return byteCode, nil
 }
 
-func callIndirect(functionTypeIndex int) []byte {
-	byteCode := []byte{code.CALL_INDIRECT}
-	byteCode = append(byteCode, leb128.Int32ToULEB128(int32(functionTypeIndex))...)
-	byteCode = append(byteCode, leb128.Int32ToULEB128(int32(0))...)
+func (c *compiler) isFloatCallToDoubleStandardFunction(expression ast.ExecuteFunctionExpression) bool {
+	functionVariable, isVariable := expression.Function.(ast.Variable)
+	if !isVariable || !isDoubleStandardFunction[functionVariable.Identifier] {
+		return false
+	}
 
-	return byteCode
-}
+	if _, isDefined, _ := c.symbolController.Resolve(functionVariable.Identifier); isDefined {
+		return false
+	}
 
-func getOperatorCode(operatorType, argumentsType string) (byte, error) {
-	if !(argumentsType == token.INT || argumentsType == token.FLOAT || argumentsType == token.BOOL) {
-		return 0, fmt.Errorf("Type %s not supported in getOperatorCode ", operatorType)
+	if len(expression.Arguments) == 0 {
+		return false
 	}
 
-	switch operatorType {
-	case token.PLUS:
-		if argumentsType == token.INT {
-			return code.I32_ADD, nil
-		} else {
-			return code.F32_ADD, nil
-		}
+	argumentTypes := expression.Arguments[0].GetExpressionReturnType()
+	return len(argumentTypes) == 1 && argumentTypes[0].String() == token.FLOAT
+}
 
-	case token.MINUS:
-		if argumentsType == token.INT {
-			return code.I32_SUB, nil
-		} else {
-			return code.F32_SUB, nil
-		}
+// Calls the function with the table index on the top of the stack, in table 0
+func callIndirect(functionTypeIndex int) []wasmDecoder.Instruction {
+	return []wasmDecoder.Instruction{{Opcode: code.CALL_INDIRECT, Index: uint32(functionTypeIndex), Table: 0}}
+}
 
-	case token.MULT:
-		if argumentsType == token.INT {
-			return code.I32_MUL, nil
-		} else {
-			return code.F32_MUL, nil
-		}
+// Operator codes for each operator and argument type. Bools are stored as i32.
+var operatorCodes = map[string]map[string]byte{
+	token.PLUS:                  {token.INT: code.I32_ADD, token.LONG: code.I64_ADD, token.FLOAT: code.F32_ADD, token.DOUBLE: code.F64_ADD},
+	token.MINUS:                 {token.INT: code.I32_SUB, token.LONG: code.I64_SUB, token.FLOAT: code.F32_SUB, token.DOUBLE: code.F64_SUB},
+	token.MULT:                  {token.INT: code.I32_MUL, token.LONG: code.I64_MUL, token.FLOAT: code.F32_MUL, token.DOUBLE: code.F64_MUL},
+	token.DIV:                   {token.INT: code.I32_DIV_S, token.LONG: code.I64_DIV_S, token.FLOAT: code.F32_DIV, token.DOUBLE: code.F64_DIV},
+	token.MOD:                   {token.INT: code.I32_REM_S, token.LONG: code.I64_REM_S},
+	token.EQUAL:                 {token.INT: code.I32_EQ, token.BOOL: code.I32_EQ, token.LONG: code.I64_EQ, token.FLOAT: code.F32_EQ, token.DOUBLE: code.F64_EQ},
+	token.NOT_EQUAL:             {token.INT: code.I32_NE, token.BOOL: code.I32_NE, token.LONG: code.I64_NE, token.FLOAT: code.F32_NE, token.DOUBLE: code.F64_NE},
+	token.GREATER_THEN:          {token.INT: code.I32_GT_S, token.LONG: code.I64_GT_S, token.FLOAT: code.F32_GT, token.DOUBLE: code.F64_GT},
+	token.LESS_THEN:             {token.INT: code.I32_LT_S, token.LONG: code.I64_LT_S, token.FLOAT: code.F32_LT, token.DOUBLE: code.F64_LT},
+	token.EQUAL_OR_LESS_THEN:    {token.INT: code.I32_LE_S, token.LONG: code.I64_LE_S, token.FLOAT: code.F32_LE, token.DOUBLE: code.F64_LE},
+	token.EQUAL_OR_GREATER_THEN: {token.INT: code.I32_GE_S, token.LONG: code.I64_GE_S, token.FLOAT: code.F32_GE, token.DOUBLE: code.F64_GE},
+	token.AND:                   {token.BOOL: code.I32_AND},
+	token.OR:                    {token.BOOL: code.I32_OR},
+	token.BIT_AND:               {token.INT: code.I32_AND, token.BOOL: code.I32_AND, token.LONG: code.I64_AND},
+	token.BIT_OR:                {token.INT: code.I32_OR, token.BOOL: code.I32_OR, token.LONG: code.I64_OR},
+	token.BIT_XOR:               {token.INT: code.I32_XOR, token.BOOL: code.I32_XOR, token.LONG: code.I64_XOR},
+	token.SHIFT_LEFT:            {token.INT: code.I32_SHL, token.LONG: code.I64_SHL},
+	token.SHIFT_RIGHT:           {token.INT: code.I32_SHR_S, token.LONG: code.I64_SHR_S},
+	token.SHIFT_RIGHT_UNSIGNED:  {token.INT: code.I32_SHR_U, token.LONG: code.I64_SHR_U},
+}
 
-	case token.DIV:
-		if argumentsType == token.INT {
-			return code.I32_DIV_S, nil
-		} else {
-			return code.F32_DIV, nil
-		}
+func getOperatorCode(operatorType, argumentsType string) (byte, error) {
+	codesByType, isOperator := operatorCodes[operatorType]
+	if !isOperator {
+		return 0, fmt.Errorf("unknown operator %s", operatorType)
+	}
 
-	case token.EQUAL:
-		if argumentsType == token.INT {
-			return code.I32_EQ, nil
-		} else {
-			return code.F32_EQ, nil
-		}
+	operatorCode, isSupported := codesByType[argumentsType]
+	if !isSupported {
+		return 0, fmt.Errorf("Type %s not supported by operator %s", argumentsType, operatorType)
+	}
 
-	case token.GREATER_THEN:
-		if argumentsType == token.INT {
-			return code.I32_GT_S, nil
-		} else {
-			return code.F32_GT, nil
-		}
+	return operatorCode, nil
+}
 
-	case token.LESS_THEN:
-		if argumentsType == token.INT {
-			return code.I32_LT_S, nil
-		} else {
-			return code.F32_LT, nil
-		}
+// Returns the code for the operand with the unary operator applied
+func getUnaryOperatorCode(operatorType, argumentType string, expressionCode []wasmDecoder.Instruction) ([]wasmDecoder.Instruction, error) {
+	byteCode := make([]wasmDecoder.Instruction, 0)
 
-	case token.EQUAL_OR_LESS_THEN:
-		if argumentsType == token.INT {
-			return code.I32_LE_S, nil
-		} else {
-			return code.F32_LE, nil
+	switch operatorType {
+	case token.MINUS:
+		switch argumentType {
+		case token.INT:
+			byteCode = append(byteCode, addConst(0)...)
+			byteCode = append(byteCode, expressionCode...)
+			return append(byteCode, instruction(code.I32_SUB)), nil
+		case token.LONG:
+			byteCode = append(byteCode, i64Const(0))
+			byteCode = append(byteCode, expressionCode...)
+			return append(byteCode, instruction(code.I64_SUB)), nil
+		case token.FLOAT:
+			byteCode = append(byteCode, expressionCode...)
+			return append(byteCode, instruction(code.F32_NEG)), nil
+		case token.DOUBLE:
+			byteCode = append(byteCode, expressionCode...)
+			return append(byteCode, instruction(code.F64_NEG)), nil
 		}
 
-	case token.EQUAL_OR_GREATER_THEN:
-		if argumentsType == token.INT {
-			return code.I32_GE_S, nil
-		} else {
-			return code.F32_GE, nil
+	case token.NOT:
+		if argumentType == token.BOOL {
+			byteCode = append(byteCode, expressionCode...)
+			return append(byteCode, instruction(code.I32_EQZ)), nil
 		}
 
-	case token.AND:
-		return code.I32_AND, nil
+	case token.COMPLEMENT:
+		switch argumentType {
+		case token.INT:
+			byteCode = append(byteCode, expressionCode...)
+			byteCode = append(byteCode, i32Const(-1))
+			return append(byteCode, instruction(code.I32_XOR)), nil
+		case token.LONG:
+			byteCode = append(byteCode, expressionCode...)
+			byteCode = append(byteCode, i64Const(-1))
+			return append(byteCode, instruction(code.I64_XOR)), nil
+		}
 
-	case token.OR:
-		return code.I32_OR, nil
+	default:
+		return []wasmDecoder.Instruction{}, fmt.Errorf("unknown unary operator %s", operatorType)
 	}
 
-	return 0, fmt.Errorf("unknown operator %s", operatorType)
+	return []wasmDecoder.Instruction{}, fmt.Errorf("Type %s not supported by unary operator %s", argumentType, operatorType)
 }
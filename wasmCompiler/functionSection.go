@@ -1,27 +1,23 @@
 package wasmCompiler
 
 import (
-	"compiler/leb128"
-	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
 )
 
 type functionSection struct {
-	numFunction            int
-	functionTypeSignatures []uint8
+	functionTypeIndexes []uint32
 }
 
 func newFunctionSection() *functionSection {
 	return &functionSection{
-		numFunction:            0,
-		functionTypeSignatures: make([]uint8, 0),
+		functionTypeIndexes: make([]uint32, 0),
 	}
 }
 
 func (s *functionSection) addFunction(typeIndex int) {
-	s.functionTypeSignatures = append(s.functionTypeSignatures, leb128.Int32ToULEB128(int32(typeIndex))...)
-	s.numFunction++
+	s.functionTypeIndexes = append(s.functionTypeIndexes, uint32(typeIndex))
 }
 
-func (s *functionSection) toByteCode() []uint8 {
-	return createSection(code.SECTION_FUNCTION, append(leb128.Int32ToULEB128(int32(s.numFunction)), s.functionTypeSignatures...))
+func (s *functionSection) addToModule(m *wasmDecoder.Module) {
+	m.Functions = s.functionTypeIndexes
 }
@@ -2,9 +2,9 @@ package wasmCompiler
 
 import (
 	"compiler/ast"
-	"compiler/leb128"
 	"compiler/symbolTable"
 	"compiler/types"
+	"compiler/wasmDecoder"
 	"fmt"
 )
 
@@ -12,9 +12,9 @@ type functions struct {
 	functions []function
 }
 
-func (f *functions) callFunction(functionIndex int, arguments []types.Type) ([]byte, error) {
+func (f *functions) callFunction(functionIndex int, arguments []types.Type) ([]wasmDecoder.Instruction, error) {
 	if functionIndex >= len(f.functions) {
-		return []byte{}, fmt.Errorf("functionIndex given to callFunction not defined")
+		return []wasmDecoder.Instruction{}, fmt.Errorf("functionIndex given to callFunction not defined")
 	}
 
 	return f.functions[functionIndex].ByteCode(arguments)
@@ -27,7 +27,7 @@ func (f *functions) addFunction(newFunction function) int {
 }
 
 type function interface {
-	ByteCode([]types.Type) ([]byte, error)
+	ByteCode([]types.Type) ([]wasmDecoder.Instruction, error)
 }
 
 type normalFunction struct {
@@ -35,13 +35,12 @@ type normalFunction struct {
 	typeIndex     int
 }
 
-func (f normalFunction) ByteCode(types []types.Type) ([]byte, error) {
-	return append(leb128.Int32ToULEB128(int32(f.functionIndex)), callIndirect(f.typeIndex)...), nil
+func (f normalFunction) ByteCode(types []types.Type) ([]wasmDecoder.Instruction, error) {
+	return append(addConst(f.functionIndex), callIndirect(f.typeIndex)...), nil
 }
 
-// The function code will be added to the code section and the function name and index will be added to the symbol controller.
-func (c *compiler) addGlobalFunction(functionName string, function ast.DefineFunctionExpression) error {
-
+// The function name and index will be added to the symbol controller so the function can be used before its code is compiled.
+func (c *compiler) defineGlobalFunction(functionName string, function ast.DefineFunctionExpression) error {
 	if _, isDefined, _ := c.symbolController.Resolve(functionName); isDefined {
 		return fmt.Errorf("Double declaration in global scope")
 	}
@@ -50,14 +49,29 @@ func (c *compiler) addGlobalFunction(functionName string, function ast.DefineFun
 	functionType.TypeIndex = c.typeSection.addType(functionType)
 
 	_, functionIndex := c.symbolController.DefineVariable(functionName, functionType)
+	c.functionNames[functionIndex] = functionName
 
 	c.funcSection.addFunction(functionType.TypeIndex)
 	c.tableSection.addFunction()
 	c.elementSection.addFunction(functionIndex)
 	c.exportSection.addExport(functionName, functionIndex)
 
-	c.symbolController.PushFunction(c.getFunctionArguments(function.Arguments))
-	err := c.compileFunction(function.FunctionBody, functionIndex)
+	return nil
+}
+
+// The function code will be added to the code section. The function must be defined with defineGlobalFunction first.
+func (c *compiler) addGlobalFunction(functionName string, function ast.DefineFunctionExpression) error {
+	functionSymbol, isDefined, isGlobal := c.symbolController.Resolve(functionName)
+	if !isDefined || !isGlobal {
+		return fmt.Errorf("Internal compiler error: global function %s not defined before compilation", functionName)
+	}
+
+	err := c.symbolController.PushFunction(c.getFunctionArguments(function.Arguments))
+	if err != nil {
+		return err
+	}
+
+	err = c.compileFunction(function.FunctionBody, function.Arguments, int(functionSymbol.Index))
 	if err != nil {
 		return err
 	}
@@ -71,6 +85,7 @@ func (c *compiler) addGlobalFunction(functionName string, function ast.DefineFun
 func (c *compiler) addLocalFunction(function ast.DefineFunctionExpression) (tableIndex int, typeIndex int, e error) {
 	functionType := function.FunctionType
 	functionIndex := c.symbolController.DefineAnonymousFunction()
+	c.functionNames[functionIndex] = "anonymous function"
 
 	functionType.TypeIndex = c.typeSection.addType(functionType)
 
@@ -78,8 +93,12 @@ func (c *compiler) addLocalFunction(function ast.DefineFunctionExpression) (tabl
 	c.elementSection.addFunction(functionIndex)
 	c.funcSection.addFunction(functionType.TypeIndex)
 
-	c.symbolController.PushFunction(c.getFunctionArguments(function.Arguments))
-	err := c.compileFunction(function.FunctionBody, functionIndex)
+	err := c.symbolController.PushFunction(c.getFunctionArguments(function.Arguments))
+	if err != nil {
+		return -1, -1, err
+	}
+
+	err = c.compileFunction(function.FunctionBody, function.Arguments, functionIndex)
 	if err != nil {
 		return -1, -1, err
 	}
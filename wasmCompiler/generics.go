@@ -0,0 +1,343 @@
+package wasmCompiler
+
+import (
+	"compiler/ast"
+	"compiler/types"
+	"fmt"
+)
+
+// Generic functions are compiled once for every combination of types they are used with
+const maxGenericFunctionInstances = 100
+
+type genericFunctionInstance struct {
+	functionIndex int
+	typeIndex     int
+}
+
+type genericFunction struct {
+	definition ast.DefineFunctionExpression
+	instances  map[string]genericFunctionInstance
+}
+
+func (c *compiler) defineGenericFunction(functionName string, function ast.DefineFunctionExpression) error {
+	if _, isDefined := c.genericFunctions[functionName]; isDefined {
+		return fmt.Errorf("Double declaration in global scope")
+	}
+
+	if _, isDefined, _ := c.symbolController.Resolve(functionName); isDefined {
+		return fmt.Errorf("Double declaration in global scope")
+	}
+
+	c.genericFunctions[functionName] = &genericFunction{
+		definition: function,
+		instances:  make(map[string]genericFunctionInstance),
+	}
+
+	return nil
+}
+
+func (c *compiler) isGenericFunction(functionName string) bool {
+	if _, isDefined, _ := c.symbolController.Resolve(functionName); isDefined {
+		return false
+	}
+
+	_, isGeneric := c.genericFunctions[functionName]
+	return isGeneric
+}
+
+// Gives the function index and type index of the generic function compiled for the given function type. The function is compiled the first time it is used with the type
+func (c *compiler) getGenericFunctionInstance(functionName string, instanceType types.Type) (functionIndex int, typeIndex int, e error) {
+	generic := c.genericFunctions[functionName]
+
+	functionType, isFunctionType := instanceType.(types.FunctionType)
+	if !isFunctionType {
+		return -1, -1, fmt.Errorf("Internal compiler error: generic function %s used with type %s", functionName, instanceType.String())
+	}
+
+	instanceKey := functionType.String()
+	if instance, isCompiled := generic.instances[instanceKey]; isCompiled {
+		return instance.functionIndex, instance.typeIndex, nil
+	}
+
+	if len(generic.instances) >= maxGenericFunctionInstances {
+		return -1, -1, fmt.Errorf("Generic function %s is used with more than %v different types. It might be using itself with ever growing types", functionName, maxGenericFunctionInstances)
+	}
+
+	typeParameterToType := make(map[string]types.Type)
+	matchTypeParameters(generic.definition.FunctionType, functionType, typeParameterToType)
+
+	instanceDefinition := substituteTypeParameters(generic.definition, typeParameterToType).(ast.DefineFunctionExpression)
+	typeIndex = c.typeSection.addType(instanceDefinition.FunctionType)
+
+	functionIndex = c.symbolController.DefineAnonymousFunction()
+	c.functionNames[functionIndex] = functionName + " " + instanceKey
+	c.funcSection.addFunction(typeIndex)
+	c.tableSection.addFunction()
+	c.elementSection.addFunction(functionIndex)
+
+	//The instance is added before it is compiled so it can use itself
+	generic.instances[instanceKey] = genericFunctionInstance{functionIndex: functionIndex, typeIndex: typeIndex}
+
+	err := c.symbolController.PushFunction(c.getFunctionArguments(instanceDefinition.Arguments))
+	if err != nil {
+		return -1, -1, err
+	}
+
+	err = c.compileFunction(instanceDefinition.FunctionBody, instanceDefinition.Arguments, functionIndex)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	c.symbolController.PopFunction()
+
+	return functionIndex, typeIndex, nil
+}
+
+// Finds the type used for each type parameter by comparing the generic type with the type it is used as
+func matchTypeParameters(genericType, realType types.Type, typeParameterToType map[string]types.Type) {
+	switch generic := genericType.(type) {
+	case types.AnyType:
+		typeParameterToType[generic.Name] = realType
+
+	case types.ArrayType:
+		if realArrayType, isArrayType := realType.(types.ArrayType); isArrayType {
+			matchTypeParameters(generic.ElementType, realArrayType.ElementType, typeParameterToType)
+		}
+
+	case types.FunctionType:
+		realFunctionType, isFunctionType := realType.(types.FunctionType)
+		if !isFunctionType {
+			return
+		}
+
+		for i := 0; i < len(generic.ArgumentTypes) && i < len(realFunctionType.ArgumentTypes); i++ {
+			matchTypeParameters(generic.ArgumentTypes[i], realFunctionType.ArgumentTypes[i], typeParameterToType)
+		}
+
+		for i := 0; i < len(generic.ReturnTypes) && i < len(realFunctionType.ReturnTypes); i++ {
+			matchTypeParameters(generic.ReturnTypes[i], realFunctionType.ReturnTypes[i], typeParameterToType)
+		}
+
+	case types.UnionType:
+		realUnionType, isUnionType := realType.(types.UnionType)
+		if !isUnionType {
+			return
+		}
+
+		for i := 0; i < len(generic.TypeArguments) && i < len(realUnionType.TypeArguments); i++ {
+			matchTypeParameters(generic.TypeArguments[i], realUnionType.TypeArguments[i], typeParameterToType)
+		}
+
+	case types.TupleType:
+		realTupleType, isTupleType := realType.(types.TupleType)
+		if !isTupleType {
+			return
+		}
+
+		for i := 0; i < len(generic.ElementTypes) && i < len(realTupleType.ElementTypes); i++ {
+			matchTypeParameters(generic.ElementTypes[i], realTupleType.ElementTypes[i], typeParameterToType)
+		}
+	}
+}
+
+func substituteType(t types.Type, typeParameterToType map[string]types.Type) types.Type {
+	switch generic := t.(type) {
+	case types.AnyType:
+		if realType, isTypeParameter := typeParameterToType[generic.Name]; isTypeParameter {
+			return realType
+		}
+
+	case types.ArrayType:
+		return types.ArrayType{ElementType: substituteType(generic.ElementType, typeParameterToType)}
+
+	case types.FunctionType:
+		return types.FunctionType{
+			ArgumentTypes: substituteTypes(generic.ArgumentTypes, typeParameterToType),
+			ReturnTypes:   substituteTypes(generic.ReturnTypes, typeParameterToType),
+		}
+
+	case types.UnionType:
+		substituted := types.UnionType{Name: generic.Name, TypeArguments: substituteTypes(generic.TypeArguments, typeParameterToType)}
+		for i := 0; i < len(generic.Variants); i++ {
+			substituted.Variants = append(substituted.Variants, types.UnionVariant{
+				Name:  generic.Variants[i].Name,
+				Types: substituteTypes(generic.Variants[i].Types, typeParameterToType),
+			})
+		}
+
+		return substituted
+
+	case types.TupleType:
+		return types.TupleType{ElementTypes: substituteTypes(generic.ElementTypes, typeParameterToType)}
+	}
+
+	return t
+}
+
+func substituteTypes(typeList []types.Type, typeParameterToType map[string]types.Type) []types.Type {
+	substituted := make([]types.Type, 0)
+	for i := 0; i < len(typeList); i++ {
+		substituted = append(substituted, substituteType(typeList[i], typeParameterToType))
+	}
+
+	return substituted
+}
+
+// Gives a copy of the syntax tree where the type parameters are replaced with the types they are used with
+func substituteTypeParameters(node ast.Node, typeParameterToType map[string]types.Type) ast.Node {
+	switch n := node.(type) {
+	case ast.AssignmentStatement:
+		n.Variables = substituteVariables(n.Variables, typeParameterToType)
+		n.Value = substituteTypeParameters(n.Value, typeParameterToType)
+		return n
+
+	case ast.ReturnStatement:
+		n.Expressions = substituteNodes(n.Expressions, typeParameterToType)
+		return n
+
+	case ast.FunctionStatement:
+		n.Expression = substituteTypeParameters(n.Expression, typeParameterToType)
+		return n
+
+	case ast.BlockStatement:
+		n.Statements = substituteNodes(n.Statements, typeParameterToType)
+		return n
+
+	case ast.Variable:
+		n.Type = substituteType(n.Type, typeParameterToType)
+		return n
+
+	case ast.DefineFunctionExpression:
+		n.TypeParameters = []string{}
+		n.Arguments = substituteVariables(n.Arguments, typeParameterToType)
+		n.ReturnTypes = substituteTypes(n.ReturnTypes, typeParameterToType)
+		n.FunctionType = substituteType(n.FunctionType, typeParameterToType).(types.FunctionType)
+		n.FunctionBody = substituteTypeParameters(n.FunctionBody, typeParameterToType).(ast.BlockStatement)
+		return n
+
+	case ast.ExecuteFunctionExpression:
+		n.Function = substituteTypeParameters(n.Function, typeParameterToType)
+		n.Arguments = substituteNodes(n.Arguments, typeParameterToType)
+		n.ReturnTypes = substituteTypes(n.ReturnTypes, typeParameterToType)
+		return n
+
+	case ast.IfExpression:
+		n.Condition = substituteTypeParameters(n.Condition, typeParameterToType)
+		n.TrueExpression = substituteTypeParameters(n.TrueExpression, typeParameterToType)
+		n.FalseExpression = substituteTypeParameters(n.FalseExpression, typeParameterToType)
+		n.ReturnType = substituteTypes(n.ReturnType, typeParameterToType)
+		return n
+
+	case ast.OperatorExpression:
+		n.LeftSide = substituteTypeParameters(n.LeftSide, typeParameterToType)
+		n.RightSide = substituteTypeParameters(n.RightSide, typeParameterToType)
+		n.Type = substituteType(n.Type, typeParameterToType)
+		return n
+
+	case ast.UnaryOperatorExpression:
+		n.Expression = substituteTypeParameters(n.Expression, typeParameterToType)
+		n.Type = substituteType(n.Type, typeParameterToType)
+		return n
+
+	case ast.ArrayExpression:
+		n.ElementsExpressions = substituteNodes(n.ElementsExpressions, typeParameterToType)
+		n.Type = substituteType(n.Type, typeParameterToType)
+		return n
+
+	case ast.RecordExpression:
+		n.FieldValues = substituteNodes(n.FieldValues, typeParameterToType)
+		return n
+
+	case ast.RecordUpdateExpression:
+		n.Record = substituteTypeParameters(n.Record, typeParameterToType)
+		n.FieldValues = substituteNodes(n.FieldValues, typeParameterToType)
+		return n
+
+	case ast.FieldAccessExpression:
+		n.Record = substituteTypeParameters(n.Record, typeParameterToType)
+		return n
+
+	case ast.TupleExpression:
+		n.Type = substituteType(n.Type, typeParameterToType).(types.TupleType)
+		n.Elements = substituteNodes(n.Elements, typeParameterToType)
+		return n
+
+	case ast.TupleUnpackExpression:
+		n.Type = substituteType(n.Type, typeParameterToType).(types.TupleType)
+		n.Tuple = substituteTypeParameters(n.Tuple, typeParameterToType)
+		return n
+
+	case ast.NewTypeExpression:
+		n.Type = substituteType(n.Type, typeParameterToType)
+		n.Value = substituteTypeParameters(n.Value, typeParameterToType)
+		return n
+
+	case ast.DestructureExpression:
+		n.Value = substituteTypeParameters(n.Value, typeParameterToType)
+		n.Patterns = substituteNodes(n.Patterns, typeParameterToType)
+		n.ReturnTypes = substituteTypes(n.ReturnTypes, typeParameterToType)
+		return n
+
+	case ast.TuplePattern:
+		n.Type = substituteType(n.Type, typeParameterToType)
+		n.Elements = substituteNodes(n.Elements, typeParameterToType)
+		return n
+
+	case ast.ArrayPattern:
+		n.Type = substituteType(n.Type, typeParameterToType)
+		n.Elements = substituteNodes(n.Elements, typeParameterToType)
+		if n.Rest != nil {
+			n.Rest = substituteTypeParameters(n.Rest, typeParameterToType)
+		}
+		return n
+
+	case ast.VariantExpression:
+		n.Type = substituteType(n.Type, typeParameterToType).(types.UnionType)
+		n.Arguments = substituteNodes(n.Arguments, typeParameterToType)
+		return n
+
+	case ast.MatchExpression:
+		n.Type = substituteType(n.Type, typeParameterToType)
+		n.Value = substituteTypeParameters(n.Value, typeParameterToType)
+		arms := make([]ast.MatchArm, 0)
+		for i := 0; i < len(n.Arms); i++ {
+			arm := n.Arms[i]
+			arm.Bindings = substituteVariables(arm.Bindings, typeParameterToType)
+			arm.Expression = substituteTypeParameters(arm.Expression, typeParameterToType)
+			arms = append(arms, arm)
+		}
+		n.Arms = arms
+		n.ReturnTypes = substituteTypes(n.ReturnTypes, typeParameterToType)
+		return n
+
+	case ast.LetExpression:
+		bindings := make([]ast.AssignmentStatement, 0)
+		for i := 0; i < len(n.Bindings); i++ {
+			bindings = append(bindings, substituteTypeParameters(n.Bindings[i], typeParameterToType).(ast.AssignmentStatement))
+		}
+		n.Bindings = bindings
+		n.Expression = substituteTypeParameters(n.Expression, typeParameterToType)
+		n.ReturnTypes = substituteTypes(n.ReturnTypes, typeParameterToType)
+		return n
+	}
+
+	return node
+}
+
+func substituteNodes(nodes []ast.Node, typeParameterToType map[string]types.Type) []ast.Node {
+	substituted := make([]ast.Node, 0)
+	for i := 0; i < len(nodes); i++ {
+		substituted = append(substituted, substituteTypeParameters(nodes[i], typeParameterToType))
+	}
+
+	return substituted
+}
+
+func substituteVariables(variables []ast.Variable, typeParameterToType map[string]types.Type) []ast.Variable {
+	substituted := make([]ast.Variable, 0)
+	for i := 0; i < len(variables); i++ {
+		substituted = append(substituted, substituteTypeParameters(variables[i], typeParameterToType).(ast.Variable))
+	}
+
+	return substituted
+}
@@ -0,0 +1,28 @@
+package wasmCompiler
+
+import (
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
+)
+
+type globalSection struct {
+	globals []wasmDecoder.Global
+}
+
+func newGlobalSection() *globalSection {
+	return &globalSection{globals: make([]wasmDecoder.Global, 0)}
+}
+
+// Returns the global index. The init expression must be a constant expression without the end instruction
+func (s *globalSection) addGlobal(valueType, mutability byte, initExpression []wasmDecoder.Instruction) int {
+	s.globals = append(s.globals, wasmDecoder.Global{
+		Type: wasmDecoder.GlobalType{ValueType: valueType, Mutable: mutability == code.MUTABLE},
+		Init: append(append([]wasmDecoder.Instruction{}, initExpression...), instruction(code.END)),
+	})
+
+	return len(s.globals) - 1
+}
+
+func (s *globalSection) addToModule(m *wasmDecoder.Module) {
+	m.Globals = s.globals
+}
@@ -0,0 +1,130 @@
+package wasmCompiler
+
+import (
+	"compiler/ast"
+	"compiler/symbolTable"
+	"compiler/types"
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
+	"fmt"
+)
+
+// Adds the variables of a global assignment statement that is not a function declaration to the global section.
+// Values given by a constant are set in the global section, all other values are set by the start function.
+func (c *compiler) addGlobalValues(statement ast.AssignmentStatement) error {
+	for i := 0; i < len(statement.Variables); i++ {
+		if _, isDefined, _ := c.symbolController.Resolve(statement.Variables[i].Identifier); isDefined {
+			return fmt.Errorf("Double declaration in global scope")
+		}
+
+		if _, isFunction := statement.Variables[i].Type.(types.FunctionType); isFunction {
+			return fmt.Errorf("Global variable %s of function type must be defined with a function definition", statement.Variables[i].Identifier)
+		}
+	}
+
+	if len(statement.Variables) == 1 && isConstantExpression(statement.Value) {
+		initExpression, err := c.compileExpression(statement.Value, newFunctionLocals())
+		if err != nil {
+			return err
+		}
+
+		c.symbolController.DefineVariable(statement.Variables[0].Identifier, statement.Variables[0].Type)
+		globalIndex := c.globalSection.addGlobal(statement.Variables[0].Type.ByteCode(), code.IMMUTABLE, initExpression)
+		c.globalNames[globalIndex] = statement.Variables[0].Identifier
+
+		return nil
+	}
+
+	for i := 0; i < len(statement.Variables); i++ {
+		zeroValue, err := getZeroValueCode(statement.Variables[i].Type)
+		if err != nil {
+			return err
+		}
+
+		c.symbolController.DefineVariable(statement.Variables[i].Identifier, statement.Variables[i].Type)
+		globalIndex := c.globalSection.addGlobal(statement.Variables[i].Type.ByteCode(), code.MUTABLE, zeroValue)
+		c.globalNames[globalIndex] = statement.Variables[i].Identifier
+	}
+
+	c.globalInitializers = append(c.globalInitializers, statement)
+
+	return nil
+}
+
+// The start function runs when the module is instantiated and sets the global values that are not constants, like arrays that need allocation.
+func (c *compiler) addStartFunction() error {
+	if len(c.globalInitializers) == 0 {
+		return nil
+	}
+
+	functionIndex := c.symbolController.DefineAnonymousFunction()
+	c.functionNames[functionIndex] = "start function"
+	typeIndex := c.typeSection.addType(types.FunctionType{ArgumentTypes: []types.Type{}, ReturnTypes: []types.Type{}})
+
+	c.funcSection.addFunction(typeIndex)
+	c.tableSection.addFunction()
+	c.elementSection.addFunction(functionIndex)
+	c.startSection.setFunction(functionIndex)
+
+	err := c.symbolController.PushFunction([]symbolTable.Variable{})
+	if err != nil {
+		return err
+	}
+
+	bodyByteCode := make([]wasmDecoder.Instruction, 0)
+	localVariables := newFunctionLocals()
+
+	for i := 0; i < len(c.globalInitializers); i++ {
+		initializer := c.globalInitializers[i]
+
+		expressionCode, err := c.compileExpression(initializer.Value, localVariables)
+		if err != nil {
+			return err
+		}
+
+		bodyByteCode = append(bodyByteCode, expressionCode...)
+
+		//The last value is on the top of the stack so the variables are set in reverse order
+		for j := len(initializer.Variables) - 1; j >= 0; j-- {
+			variableSymbol, isDefined, isGlobal := c.symbolController.Resolve(initializer.Variables[j].Identifier)
+			if !isDefined || !isGlobal {
+				return fmt.Errorf("Internal compiler error: global variable %s not defined", initializer.Variables[j].Identifier)
+			}
+
+			bodyByteCode = append(bodyByteCode, indexInstruction(code.GLOBAL_SET, int(variableSymbol.Index)))
+		}
+	}
+
+	c.symbolController.PopFunction()
+
+	bodyByteCode = append(bodyByteCode, instruction(code.END))
+
+	c.codeSection.addFunction(localVariables.functionBody(bodyByteCode), functionIndex)
+	c.localNames[functionIndex] = localVariables.names
+
+	return nil
+}
+
+func isConstantExpression(expression ast.Node) bool {
+	switch expression.(type) {
+	case ast.IntExpression, ast.FloatExpression, ast.LongExpression, ast.DoubleExpression, ast.BoolExpression:
+		return true
+	}
+
+	return false
+}
+
+func getZeroValueCode(variableType types.Type) ([]wasmDecoder.Instruction, error) {
+	switch variableType.ByteCode() {
+	case code.I32:
+		return []wasmDecoder.Instruction{i32Const(0)}, nil
+	case code.I64:
+		return []wasmDecoder.Instruction{i64Const(0)}, nil
+	case code.F32:
+		return []wasmDecoder.Instruction{f32Const(0)}, nil
+	case code.F64:
+		return []wasmDecoder.Instruction{f64Const(0)}, nil
+	}
+
+	return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: type %s of global variable not supported", variableType)
+}
@@ -0,0 +1,97 @@
+package wasmCompiler
+
+import (
+	"compiler/token"
+	"compiler/types"
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
+	"fmt"
+)
+
+// Standard functions that are compiled to instructions placed directly after the arguments instead of a function call.
+// Each entry gives the instructions to use for the type of the first argument.
+var inlineStandardFunctions = map[string]map[string][]wasmDecoder.Instruction{
+	"toInt": {
+		token.INT:    {},
+		token.LONG:   {instruction(code.I32_WRAP_I64)},
+		token.FLOAT:  {miscInstruction(code.I32_TRUNC_SAT_F32_S)},
+		token.DOUBLE: {miscInstruction(code.I32_TRUNC_SAT_F64_S)},
+	},
+	"toLong": {
+		token.INT:    {instruction(code.I64_EXTEND_S_I32)},
+		token.LONG:   {},
+		token.FLOAT:  {miscInstruction(code.I64_TRUNC_SAT_F32_S)},
+		token.DOUBLE: {miscInstruction(code.I64_TRUNC_SAT_F64_S)},
+	},
+	"toFloat": {
+		token.INT:    {instruction(code.F32_CONVERT_S_I32)},
+		token.LONG:   {instruction(code.F32_CONVERT_S_I64)},
+		token.FLOAT:  {},
+		token.DOUBLE: {instruction(code.F32_DEMOTE_F64)},
+	},
+	"toDouble": {
+		token.INT:    {instruction(code.F64_CONVERT_S_I32)},
+		token.LONG:   {instruction(code.F64_CONVERT_S_I64)},
+		token.FLOAT:  {instruction(code.F64_PROMOTE_F32)},
+		token.DOUBLE: {},
+	},
+	"roundToInt": {
+		token.FLOAT:  {instruction(code.F32_NEAREST), miscInstruction(code.I32_TRUNC_SAT_F32_S)},
+		token.DOUBLE: {instruction(code.F64_NEAREST), miscInstruction(code.I32_TRUNC_SAT_F64_S)},
+	},
+	"floorToInt": {
+		token.FLOAT:  {instruction(code.F32_FLOOR), miscInstruction(code.I32_TRUNC_SAT_F32_S)},
+		token.DOUBLE: {instruction(code.F64_FLOOR), miscInstruction(code.I32_TRUNC_SAT_F64_S)},
+	},
+	"ceilToInt": {
+		token.FLOAT:  {instruction(code.F32_CEIL), miscInstruction(code.I32_TRUNC_SAT_F32_S)},
+		token.DOUBLE: {instruction(code.F64_CEIL), miscInstruction(code.I32_TRUNC_SAT_F64_S)},
+	},
+	"toBool": {
+		token.INT:    {instruction(code.I32_EQZ), instruction(code.I32_EQZ)},
+		token.LONG:   {instruction(code.I64_EQZ), instruction(code.I32_EQZ)},
+		token.FLOAT:  {f32Const(0), instruction(code.F32_NE)},
+		token.DOUBLE: {f64Const(0), instruction(code.F64_NE)},
+	},
+	"fromBool": {
+		token.BOOL: {},
+	},
+	"sqrt": {
+		token.FLOAT:  {instruction(code.F32_SQRT)},
+		token.DOUBLE: {instruction(code.F64_SQRT)},
+	},
+	"floor": {
+		token.FLOAT:  {instruction(code.F32_FLOOR)},
+		token.DOUBLE: {instruction(code.F64_FLOOR)},
+	},
+	"ceil": {
+		token.FLOAT:  {instruction(code.F32_CEIL)},
+		token.DOUBLE: {instruction(code.F64_CEIL)},
+	},
+	"trunc": {
+		token.FLOAT:  {instruction(code.F32_TRUNC)},
+		token.DOUBLE: {instruction(code.F64_TRUNC)},
+	},
+	"nearest": {
+		token.FLOAT:  {instruction(code.F32_NEAREST)},
+		token.DOUBLE: {instruction(code.F64_NEAREST)},
+	},
+}
+
+func isInlineStandardFunction(functionName string) bool {
+	_, isInline := inlineStandardFunctions[functionName]
+	return isInline
+}
+
+func getInlineStandardFunctionCode(functionName string, arguments []types.Type) ([]wasmDecoder.Instruction, error) {
+	if len(arguments) == 0 {
+		return []wasmDecoder.Instruction{}, fmt.Errorf("Error in validation process: wrong amount of arguments in %s call", functionName)
+	}
+
+	functionCode, isSupported := inlineStandardFunctions[functionName][arguments[0].String()]
+	if !isSupported {
+		return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: type %s not supported by %s", arguments[0].String(), functionName)
+	}
+
+	return functionCode, nil
+}
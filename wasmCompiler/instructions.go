@@ -0,0 +1,71 @@
+package wasmCompiler
+
+import (
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
+)
+
+//The code of functions is made of the instruction values of the decoder package, so the compiled functions are encoded
+//by its encoder and printed in the text format from the same instructions
+
+func instruction(opcode uint8) wasmDecoder.Instruction {
+	return wasmDecoder.Instruction{Opcode: opcode}
+}
+
+// Instructions with the MISC_PREFIX opcode, like the saturating truncations
+func miscInstruction(miscOpcode uint8) wasmDecoder.Instruction {
+	return wasmDecoder.Instruction{Opcode: code.MISC_PREFIX, MiscOpcode: uint32(miscOpcode)}
+}
+
+// Instructions with an index, like local.get, global.set, call and br
+func indexInstruction(opcode uint8, index int) wasmDecoder.Instruction {
+	return wasmDecoder.Instruction{Opcode: opcode, Index: uint32(index)}
+}
+
+func localGet(localIndex int) wasmDecoder.Instruction {
+	return indexInstruction(code.LOCAL_GET, localIndex)
+}
+
+func localSet(localIndex int) wasmDecoder.Instruction {
+	return indexInstruction(code.LOCAL_SET, localIndex)
+}
+
+// A block, loop or if with the results of the function type with the index
+func blockInstruction(opcode uint8, typeIndex int) wasmDecoder.Instruction {
+	return wasmDecoder.Instruction{Opcode: opcode, BlockType: wasmDecoder.BlockType{TypeIndex: int64(typeIndex)}}
+}
+
+func emptyBlockInstruction(opcode uint8) wasmDecoder.Instruction {
+	return wasmDecoder.Instruction{Opcode: opcode, BlockType: wasmDecoder.BlockType{Results: []uint8{}, TypeIndex: -1}}
+}
+
+// The last label is the default label
+func branchTable(labels []int) wasmDecoder.Instruction {
+	instruction := wasmDecoder.Instruction{Opcode: code.BR_TABLE}
+	for i := 0; i < len(labels); i++ {
+		instruction.Labels = append(instruction.Labels, uint32(labels[i]))
+	}
+
+	return instruction
+}
+
+//The alignment is 0 since the values in memory are not aligned
+func memoryInstruction(opcode uint8, offset int) wasmDecoder.Instruction {
+	return wasmDecoder.Instruction{Opcode: opcode, Align: 0, MemoryOffset: uint32(offset)}
+}
+
+func i32Const(value int32) wasmDecoder.Instruction {
+	return wasmDecoder.Instruction{Opcode: code.I32_CONST, I32: value}
+}
+
+func i64Const(value int64) wasmDecoder.Instruction {
+	return wasmDecoder.Instruction{Opcode: code.I64_CONST, I64: value}
+}
+
+func f32Const(value float32) wasmDecoder.Instruction {
+	return wasmDecoder.Instruction{Opcode: code.F32_CONST, F32: value}
+}
+
+func f64Const(value float64) wasmDecoder.Instruction {
+	return wasmDecoder.Instruction{Opcode: code.F64_CONST, F64: value}
+}
@@ -0,0 +1,43 @@
+package wasmCompiler
+
+import (
+	"compiler/ast"
+	"compiler/wasmDecoder"
+)
+
+//The values bound by the let are stored in new local variables defined in a block, which shadow the variables with
+//the same names until the end of the let expression
+func (c *compiler) createLetCode(expression ast.LetExpression, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	outputCode := make([]wasmDecoder.Instruction, 0)
+
+	c.symbolController.PushBlock()
+	defer c.symbolController.PopBlock()
+
+	for i := 0; i < len(expression.Bindings); i++ {
+		binding := expression.Bindings[i]
+
+		valueCode, err := c.compileExpression(binding.Value, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		outputCode = append(outputCode, valueCode...)
+
+		variableIndexes := make([]int, 0)
+		for j := 0; j < len(binding.Variables); j++ {
+			variableIndexes = append(variableIndexes, functionLocals.defineLocalVariable(binding.Variables[j].Type, binding.Variables[j].Identifier, c.symbolController))
+		}
+
+		//The last value is on the top of the stack so the variables are set in reverse order
+		for j := len(variableIndexes) - 1; j >= 0; j-- {
+			outputCode = append(outputCode, localSet(variableIndexes[j]))
+		}
+	}
+
+	expressionCode, err := c.compileExpression(expression.Expression, functionLocals)
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	return append(outputCode, expressionCode...), nil
+}
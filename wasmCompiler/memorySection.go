@@ -1,8 +1,7 @@
 package wasmCompiler
 
 import (
-	"compiler/leb128"
-	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
 )
 
 type memorySection struct {
@@ -15,16 +14,6 @@ func newMemorySection(size int) *memorySection {
 	}
 }
 
-func (s *memorySection) toByteCode() []byte {
-	byteCode := leb128.Int32ToULEB128(1) //1 storing number of memories
-	byteCode = append(byteCode, newLimit(s.size, s.size)...)
-	return createSection(code.SECTION_MEMORY, byteCode)
-}
-
-func newLimit(min, max int) []byte {
-	byteCode := []byte{code.LIMIT_MIN_MAX}
-	byteCode = append(byteCode, leb128.Int32ToULEB128(int32(min))...)
-	byteCode = append(byteCode, leb128.Int32ToULEB128(int32(max))...)
-
-	return byteCode
+func (s *memorySection) addToModule(m *wasmDecoder.Module) {
+	m.Memories = []wasmDecoder.Limits{{Min: uint32(s.size), Max: uint32(s.size), HasMax: true}}
 }
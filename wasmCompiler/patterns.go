@@ -0,0 +1,178 @@
+package wasmCompiler
+
+import (
+	"compiler/ast"
+	"compiler/token"
+	"compiler/types"
+	"compiler/wasmDecoder"
+	"fmt"
+)
+
+//The values given to the patterns are stored in locals, and the values bound to the variables are loaded from them.
+//Elements of arrays are read with get, and the rest of an array is created with tail
+
+func (c *compiler) createDestructureCode(expression ast.DestructureExpression, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	outputCode, err := c.compileExpression(expression.Value, functionLocals)
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	valuesCode := make([][]wasmDecoder.Instruction, len(expression.Patterns))
+	for i := len(expression.Patterns) - 1; i >= 0; i-- {
+		valueVariableIndex := functionLocals.defineLocalVariable(getPatternType(expression.Patterns[i]), "", c.symbolController)
+		outputCode = append(outputCode, localSet(valueVariableIndex))
+		valuesCode[i] = []wasmDecoder.Instruction{localGet(valueVariableIndex)}
+	}
+
+	for i := 0; i < len(expression.Patterns); i++ {
+		patternCode, err := c.createPatternCode(expression.Patterns[i], valuesCode[i], functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		outputCode = append(outputCode, patternCode...)
+	}
+
+	return outputCode, nil
+}
+
+//Leaves the values bound by the pattern on the stack. valueCode gives the value matched by the pattern
+func (c *compiler) createPatternCode(pattern ast.Node, valueCode []wasmDecoder.Instruction, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	switch p := pattern.(type) {
+	case ast.Variable:
+		if p.Identifier == token.WILDCARD {
+			return []wasmDecoder.Instruction{}, nil
+		}
+
+		return valueCode, nil
+
+	case ast.TuplePattern:
+		tupleType, isTupleType := p.Type.(types.TupleType)
+		if !isTupleType {
+			return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: type in ast.TuplePattern not tuple")
+		}
+
+		outputCode, valueCode := c.storeInLocal(tupleType, valueCode, functionLocals)
+		for i := 0; i < len(p.Elements); i++ {
+			elementCode, err := createLoadValueCode(tupleType.ElementTypes, i, valueCode)
+			if err != nil {
+				return []wasmDecoder.Instruction{}, err
+			}
+
+			patternCode, err := c.createPatternCode(p.Elements[i], elementCode, functionLocals)
+			if err != nil {
+				return []wasmDecoder.Instruction{}, err
+			}
+
+			outputCode = append(outputCode, patternCode...)
+		}
+
+		return outputCode, nil
+
+	case ast.ArrayPattern:
+		return c.createArrayPatternCode(p, valueCode, functionLocals)
+
+	case ast.RecordPattern:
+		recordType, isRecordType := p.Type.(types.RecordType)
+		if !isRecordType {
+			return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: type in ast.RecordPattern not record")
+		}
+
+		outputCode, valueCode := c.storeInLocal(recordType, valueCode, functionLocals)
+		for i := 0; i < len(p.Fields); i++ {
+			fieldCode, err := createFieldAccessCode(recordType, p.Fields[i].Identifier, valueCode)
+			if err != nil {
+				return []wasmDecoder.Instruction{}, err
+			}
+
+			outputCode = append(outputCode, fieldCode...)
+		}
+
+		return outputCode, nil
+	}
+
+	return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: pattern not supported")
+}
+
+func (c *compiler) createArrayPatternCode(pattern ast.ArrayPattern, valueCode []wasmDecoder.Instruction, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	arrayType, isArrayType := pattern.Type.(types.ArrayType)
+	if !isArrayType {
+		return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: type in ast.ArrayPattern not array")
+	}
+
+	outputCode, valueCode := c.storeInLocal(arrayType, valueCode, functionLocals)
+
+	getCode, err := c.createStandardFunctionCallCode("get", []types.Type{arrayType, types.StandardType{Name: token.INT}})
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	for i := 0; i < len(pattern.Elements); i++ {
+		elementCode := append([]wasmDecoder.Instruction{}, valueCode...)
+		elementCode = append(elementCode, addConst(i)...)
+		elementCode = append(elementCode, getCode...)
+
+		patternCode, err := c.createPatternCode(pattern.Elements[i], elementCode, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		outputCode = append(outputCode, patternCode...)
+	}
+
+	if pattern.Rest == nil {
+		return outputCode, nil
+	}
+
+	tailCode, err := c.createStandardFunctionCallCode("tail", []types.Type{arrayType})
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	restCode := append([]wasmDecoder.Instruction{}, valueCode...)
+	for i := 0; i < len(pattern.Elements); i++ {
+		restCode = append(restCode, tailCode...)
+	}
+
+	patternCode, err := c.createPatternCode(pattern.Rest, restCode, functionLocals)
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	return append(outputCode, patternCode...), nil
+}
+
+//Stores the value in a new local so it is only computed once. Returns the code storing the value and the code getting it from the local
+func (c *compiler) storeInLocal(valueType types.Type, valueCode []wasmDecoder.Instruction, functionLocals *functionLocals) ([]wasmDecoder.Instruction, []wasmDecoder.Instruction) {
+	variableIndex := functionLocals.defineLocalVariable(valueType, "", c.symbolController)
+	storeCode := append(append([]wasmDecoder.Instruction{}, valueCode...), localSet(variableIndex))
+
+	return storeCode, []wasmDecoder.Instruction{localGet(variableIndex)}
+}
+
+//Calls the standard function with the arguments already on the stack
+func (c *compiler) createStandardFunctionCallCode(functionName string, argumentTypes []types.Type) ([]wasmDecoder.Instruction, error) {
+	functionIndex, functionTypeIndex, extraArguments, err := c.getStandardFunctionIndexTypeIndexAndExtraArguments(functionName, argumentTypes)
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	outputCode := append([]wasmDecoder.Instruction{}, extraArguments...)
+	outputCode = append(outputCode, addConst(functionIndex)...)
+	return append(outputCode, callIndirect(functionTypeIndex)...), nil
+}
+
+func getPatternType(pattern ast.Node) types.Type {
+	switch p := pattern.(type) {
+	case ast.Variable:
+		return p.Type
+	case ast.TuplePattern:
+		return p.Type
+	case ast.ArrayPattern:
+		return p.Type
+	case ast.RecordPattern:
+		return p.Type
+	}
+
+	return types.StandardType{Name: types.NONE}
+}
@@ -0,0 +1,218 @@
+package wasmCompiler
+
+import (
+	"compiler/ast"
+	"compiler/token"
+	"compiler/types"
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
+	"fmt"
+)
+
+//Records are stored in memory allocated with allocate. The fields are stored after each other in the order they are defined in the record type
+
+func (c *compiler) createRecordCode(expression ast.RecordExpression, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	recordType, isRecordType := expression.Type.(types.RecordType)
+	if !isRecordType {
+		return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: type in ast.RecordExpression not record")
+	}
+
+	fieldValuesCode := make([][]wasmDecoder.Instruction, len(recordType.Fields))
+	for i := 0; i < len(expression.FieldNames); i++ {
+		fieldIndex, _ := recordType.GetField(expression.FieldNames[i])
+
+		valueCode, err := c.compileExpression(expression.FieldValues[i], functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		fieldValuesCode[fieldIndex] = valueCode
+	}
+
+	return c.createRecordFromFieldsCode(recordType, fieldValuesCode, functionLocals)
+}
+
+//The fields not updated are copied from the old record to a new record
+func (c *compiler) createRecordUpdateCode(expression ast.RecordUpdateExpression, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	recordType, isRecordType := expression.Type.(types.RecordType)
+	if !isRecordType {
+		return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: type in ast.RecordUpdateExpression not record")
+	}
+
+	outputCode, err := c.compileExpression(expression.Record, functionLocals)
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	oldRecordVariableIndex := functionLocals.defineLocalVariable(types.StandardType{Name: token.INT}, "", c.symbolController)
+	outputCode = append(outputCode, localSet(oldRecordVariableIndex))
+
+	fieldValuesCode := make([][]wasmDecoder.Instruction, len(recordType.Fields))
+	for i := 0; i < len(expression.FieldNames); i++ {
+		fieldIndex, _ := recordType.GetField(expression.FieldNames[i])
+
+		valueCode, err := c.compileExpression(expression.FieldValues[i], functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		fieldValuesCode[fieldIndex] = valueCode
+	}
+
+	for i := 0; i < len(recordType.Fields); i++ {
+		if fieldValuesCode[i] != nil {
+			continue
+		}
+
+		oldRecordCode := []wasmDecoder.Instruction{localGet(oldRecordVariableIndex)}
+		fieldCode, err := createFieldAccessCode(recordType, recordType.Fields[i].Name, oldRecordCode)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		fieldValuesCode[i] = fieldCode
+	}
+
+	recordCode, err := c.createRecordFromFieldsCode(recordType, fieldValuesCode, functionLocals)
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	return append(outputCode, recordCode...), nil
+}
+
+//Allocates the record and stores the values of the fields. The code for the field values must be in the order of the fields in the record type
+func (c *compiler) createRecordFromFieldsCode(recordType types.RecordType, fieldValuesCode [][]wasmDecoder.Instruction, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	return c.createStoredValuesCode(getRecordFieldTypes(recordType), fieldValuesCode, functionLocals)
+}
+
+func createFieldAccessCode(recordType types.RecordType, fieldName string, recordCode []wasmDecoder.Instruction) ([]wasmDecoder.Instruction, error) {
+	fieldIndex, hasField := recordType.GetField(fieldName)
+	if !hasField {
+		return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: record type %s has no field %s", recordType.Name, fieldName)
+	}
+
+	return createLoadValueCode(getRecordFieldTypes(recordType), fieldIndex, recordCode)
+}
+
+func getRecordFieldTypes(recordType types.RecordType) []types.Type {
+	fieldTypes := make([]types.Type, 0)
+	for i := 0; i < len(recordType.Fields); i++ {
+		fieldTypes = append(fieldTypes, recordType.Fields[i].Type)
+	}
+
+	return fieldTypes
+}
+
+//Allocates memory for the values and stores them after each other. The pointer to the memory is left on the stack
+func (c *compiler) createStoredValuesCode(valueTypes []types.Type, valuesCode [][]wasmDecoder.Instruction, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	outputCode := make([]wasmDecoder.Instruction, 0)
+
+	size, err := getValueOffset(valueTypes, len(valueTypes))
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	allocateFunctionIndex, allocateFunctionTypeIndex, _, err := c.getStandardFunctionIndexTypeIndexAndExtraArguments("allocate", []types.Type{})
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	outputCode = append(outputCode, addConst(size)...)
+	outputCode = append(outputCode, addConst(allocateFunctionIndex)...)
+	outputCode = append(outputCode, callIndirect(allocateFunctionTypeIndex)...)
+
+	pointerVariableIndex := functionLocals.defineLocalVariable(types.StandardType{Name: token.INT}, "", c.symbolController)
+	outputCode = append(outputCode, localSet(pointerVariableIndex))
+
+	for i := 0; i < len(valueTypes); i++ {
+		offset, err := getValueOffset(valueTypes, i)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		storeCode, err := getStoreCode(valueTypes[i])
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		outputCode = append(outputCode, localGet(pointerVariableIndex))
+		outputCode = append(outputCode, valuesCode[i]...)
+		outputCode = append(outputCode, memoryInstruction(storeCode, offset))
+	}
+
+	outputCode = append(outputCode, localGet(pointerVariableIndex))
+
+	return outputCode, nil
+}
+
+//Loads the value with the given index from memory storing the values after each other. The pointer to the memory is given by pointerCode
+func createLoadValueCode(valueTypes []types.Type, valueIndex int, pointerCode []wasmDecoder.Instruction) ([]wasmDecoder.Instruction, error) {
+	offset, err := getValueOffset(valueTypes, valueIndex)
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	loadCode, err := getLoadCode(valueTypes[valueIndex])
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	outputCode := append([]wasmDecoder.Instruction{}, pointerCode...)
+	outputCode = append(outputCode, memoryInstruction(loadCode, offset))
+
+	return outputCode, nil
+}
+
+//Returns the number of bytes from the start of the memory to the value with the given index
+func getValueOffset(valueTypes []types.Type, valueIndex int) (int, error) {
+	offset := 0
+	for i := 0; i < valueIndex; i++ {
+		valueSize, err := getTypeSize(valueTypes[i])
+		if err != nil {
+			return 0, err
+		}
+
+		offset += valueSize
+	}
+
+	return offset, nil
+}
+
+func getLoadCode(valueType types.Type) (byte, error) {
+	switch valueType.String() {
+	case token.BOOL:
+		return code.I32_LOAD8_U, nil
+	case token.LONG:
+		return code.I64_LOAD, nil
+	case token.FLOAT:
+		return code.F32_LOAD, nil
+	case token.DOUBLE:
+		return code.F64_LOAD, nil
+	}
+
+	if valueType.ByteCode() == code.I32 {
+		return code.I32_LOAD, nil
+	}
+
+	return 0, fmt.Errorf("Internal compiler error: loading value of type %s from memory not supported", valueType.String())
+}
+
+func getStoreCode(valueType types.Type) (byte, error) {
+	switch valueType.String() {
+	case token.BOOL:
+		return code.I32_STORE8, nil
+	case token.LONG:
+		return code.I64_STORE, nil
+	case token.FLOAT:
+		return code.F32_STORE, nil
+	case token.DOUBLE:
+		return code.F64_STORE, nil
+	}
+
+	if valueType.ByteCode() == code.I32 {
+		return code.I32_STORE, nil
+	}
+
+	return 0, fmt.Errorf("Internal compiler error: storing value of type %s in memory not supported", valueType.String())
+}
@@ -1,9 +1,10 @@
 package wasmCompiler
 
 import (
-	"compiler/readWasm"
 	"compiler/token"
 	"compiler/types"
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
 	"fmt"
 )
 
@@ -12,19 +13,37 @@ type typeAndFuncIndex struct {
 	funcIndex int
 }
 
+// A function or a global in one of the files with standard functions. The index is the index in the file
+type standardDefinition struct {
+	fileName string
+	index    int
+}
+
 type standardFunctions struct {
 	standardFunctionIndexes map[string]typeAndFuncIndex
+	modules                 map[string]*wasmDecoder.Module
+	importedFunctions       map[standardDefinition]typeAndFuncIndex
+	importedGlobals         map[standardDefinition]int
 }
 
-func (c *compiler) getStandardFunctionIndexTypeIndexAndExtraArguments(name string, arguments []types.Type) (int, int, []byte, error) {
+func newStandardFunctions() standardFunctions {
+	return standardFunctions{
+		standardFunctionIndexes: make(map[string]typeAndFuncIndex),
+		modules:                 make(map[string]*wasmDecoder.Module),
+		importedFunctions:       make(map[standardDefinition]typeAndFuncIndex),
+		importedGlobals:         make(map[standardDefinition]int),
+	}
+}
+
+func (c *compiler) getStandardFunctionIndexTypeIndexAndExtraArguments(name string, arguments []types.Type) (int, int, []wasmDecoder.Instruction, error) {
 	realFunctionName, err := getStandardFunctionRealName(name, arguments)
 	if err != nil {
-		return 0, 0, []byte{}, err
+		return 0, 0, []wasmDecoder.Instruction{}, err
 	}
 
 	extraArguments, err := getStandardFunctionExtraArguments(name, arguments)
 	if err != nil {
-		return 0, 0, []byte{}, err
+		return 0, 0, []wasmDecoder.Instruction{}, err
 	}
 
 	if indexes, isImported := c.standardFunctions.standardFunctionIndexes[realFunctionName]; isImported {
@@ -35,14 +54,6 @@ func (c *compiler) getStandardFunctionIndexTypeIndexAndExtraArguments(name strin
 	return funcIndex, typeIndex, extraArguments, err
 }
 
-//the memory handler must be added before all other function because multiple standard functions written in wasm reference these functions indexes with 0, 1 and 2
-func (c *compiler) importMemoryHandler() error {
-	_, _, err := c.importStandardFunction("allocate")
-	_, _, err = c.importStandardFunction("deAllocate")
-	_, _, err = c.importStandardFunction("array")
-	return err
-}
-
 //Returns func index and type index
 func (c *compiler) importStandardFunction(functionName string) (int, int, error) {
 	for i := 0; i < len(standardFunctionsData); i++ {
@@ -50,28 +61,202 @@ func (c *compiler) importStandardFunction(functionName string) (int, int, error)
 			continue
 		}
 
-		functionCode, err := readWasm.GetFuncFromFile(standardFunctionsData[i].fileName, standardFunctionsData[i].funcIndex)
+		indexes, err := c.importWasmFunction(standardDefinition{fileName: standardFunctionsData[i].fileName, index: standardFunctionsData[i].funcIndex})
 		if err != nil {
-			return 0, 0, fmt.Errorf("Internal compiler error: Error getting standard function %v from file %v: %v", standardFunctionsData[i].funcIndex, standardFunctionsData[i].fileName, err.Error())
+			return 0, 0, fmt.Errorf("Internal compiler error: Error importing standard function %s from file %s: %s", functionName, standardFunctionsData[i].fileName, err.Error())
 		}
 
-		funcIndex := c.symbolController.DefineAnonymousFunction()
-		typeIndex := c.typeSection.addType(standardFunctionsData[i].funcType)
+		c.standardFunctions.standardFunctionIndexes[functionName] = indexes
+		return indexes.funcIndex, indexes.typeIndex, nil
+	}
 
-		c.funcSection.addFunction(typeIndex)
-		c.tableSection.addFunction()
-		c.elementSection.addFunction(funcIndex)
-		c.codeSection.addFunction(functionCode, funcIndex)
-		c.standardFunctions.standardFunctionIndexes[functionName] = typeAndFuncIndex{funcIndex: funcIndex, typeIndex: typeIndex}
+	return 0, 0, fmt.Errorf("Internal compiler error: Standard function with name %s not found in standard function data", functionName)
+}
 
-		return funcIndex, typeIndex, nil
+// Adds a function from a file with standard functions to the module, together with the functions and globals it uses
+func (c *compiler) importWasmFunction(definition standardDefinition) (typeAndFuncIndex, error) {
+	if indexes, isImported := c.standardFunctions.importedFunctions[definition]; isImported {
+		return indexes, nil
 	}
 
-	return 0, 0, fmt.Errorf("Internal compiler error: Standard function with name %s not found in standard function data", functionName)
+	module, err := c.getStandardFunctionsModule(definition.fileName)
+	if err != nil {
+		return typeAndFuncIndex{}, err
+	}
+
+	bodyIndex := definition.index - module.NumImportedFunctions()
+	if bodyIndex < 0 || bodyIndex >= len(module.Functions) {
+		return typeAndFuncIndex{}, fmt.Errorf("function %v is not defined in the file", definition.index)
+	}
+
+	typeIndex, err := c.addWasmType(module, module.Functions[bodyIndex])
+	if err != nil {
+		return typeAndFuncIndex{}, err
+	}
+
+	funcIndex := c.symbolController.DefineAnonymousFunction()
+	c.functionNames[funcIndex] = getStandardFunctionName(definition)
+
+	c.funcSection.addFunction(typeIndex)
+	c.tableSection.addFunction()
+	c.elementSection.addFunction(funcIndex)
+
+	//The function is added before its code is imported so it can call itself
+	indexes := typeAndFuncIndex{funcIndex: funcIndex, typeIndex: typeIndex}
+	c.standardFunctions.importedFunctions[definition] = indexes
+
+	functionBody, err := c.relocateWasmFunction(definition.fileName, module, module.Code[bodyIndex])
+	if err != nil {
+		return typeAndFuncIndex{}, fmt.Errorf("function %v: %s", definition.index, err.Error())
+	}
+
+	c.codeSection.addFunction(functionBody, funcIndex)
+	return indexes, nil
+}
+
+// The functions, globals and types used by a function from a file have other indexes in the compiled module, so the
+// instructions using them are given the new indexes. All other instructions are copied
+func (c *compiler) relocateWasmFunction(fileName string, module *wasmDecoder.Module, body wasmDecoder.FunctionBody) (wasmDecoder.FunctionBody, error) {
+	relocated := wasmDecoder.FunctionBody{Locals: body.Locals, Instructions: make([]wasmDecoder.Instruction, 0)}
+
+	for i := 0; i < len(body.Instructions); i++ {
+		instruction := body.Instructions[i]
+
+		switch {
+		case instruction.Opcode == code.CALL:
+			indexes, err := c.importWasmFunction(standardDefinition{fileName: fileName, index: int(instruction.Index)})
+			if err != nil {
+				return wasmDecoder.FunctionBody{}, err
+			}
+
+			instruction.Index = uint32(indexes.funcIndex)
+
+		case instruction.Opcode == code.CALL_INDIRECT:
+			typeIndex, err := c.addWasmType(module, instruction.Index)
+			if err != nil {
+				return wasmDecoder.FunctionBody{}, err
+			}
+
+			instruction.Index = uint32(typeIndex)
+
+		case instruction.Opcode == code.GLOBAL_GET || instruction.Opcode == code.GLOBAL_SET:
+			globalIndex, err := c.importWasmGlobal(standardDefinition{fileName: fileName, index: int(instruction.Index)})
+			if err != nil {
+				return wasmDecoder.FunctionBody{}, err
+			}
+
+			instruction.Index = uint32(globalIndex)
+
+		case (instruction.Opcode == code.BLOCK || instruction.Opcode == code.LOOP || instruction.Opcode == code.IF) && instruction.BlockType.TypeIndex != -1:
+			typeIndex, err := c.addWasmType(module, uint32(instruction.BlockType.TypeIndex))
+			if err != nil {
+				return wasmDecoder.FunctionBody{}, err
+			}
+
+			instruction.BlockType.TypeIndex = int64(typeIndex)
+		}
+
+		relocated.Instructions = append(relocated.Instructions, instruction)
+	}
+
+	return relocated, nil
+}
+
+// Adds a global from a file with standard functions to the global section
+func (c *compiler) importWasmGlobal(definition standardDefinition) (int, error) {
+	if globalIndex, isImported := c.standardFunctions.importedGlobals[definition]; isImported {
+		return globalIndex, nil
+	}
+
+	module, err := c.getStandardFunctionsModule(definition.fileName)
+	if err != nil {
+		return -1, err
+	}
+
+	globalIndex := definition.index - module.NumImportedGlobals()
+	if globalIndex < 0 || globalIndex >= len(module.Globals) {
+		return -1, fmt.Errorf("global %v is not defined in the file", definition.index)
+	}
+
+	global := module.Globals[globalIndex]
+	if !isConstantInstruction(global.Init[0]) {
+		return -1, fmt.Errorf("global %v: only constant initial values are supported", definition.index)
+	}
+
+	mutability := code.IMMUTABLE
+	if global.Type.Mutable {
+		mutability = code.MUTABLE
+	}
+
+	c.symbolController.DefineAnonymousGlobal()
+	importedIndex := c.globalSection.addGlobal(global.Type.ValueType, mutability, global.Init[:1])
+	c.standardFunctions.importedGlobals[definition] = importedIndex
+
+	return importedIndex, nil
+}
+
+func isConstantInstruction(instruction wasmDecoder.Instruction) bool {
+	switch instruction.Opcode {
+	case code.I32_CONST, code.I64_CONST, code.F32_CONST, code.F64_CONST:
+		return true
+	}
+
+	return false
+}
+
+// Adds a function type from a file with standard functions to the type section and returns its index in the module
+func (c *compiler) addWasmType(module *wasmDecoder.Module, fileTypeIndex uint32) (int, error) {
+	if int(fileTypeIndex) >= len(module.Types) {
+		return -1, fmt.Errorf("type %v is not defined in the file", fileTypeIndex)
+	}
+
+	wasmType := module.Types[fileTypeIndex]
+	functionType := types.FunctionType{ArgumentTypes: []types.Type{}, ReturnTypes: []types.Type{}}
+	for i := 0; i < len(wasmType.Parameters); i++ {
+		functionType.ArgumentTypes = append(functionType.ArgumentTypes, wasmValueTypeToType[wasmType.Parameters[i]])
+	}
+
+	for i := 0; i < len(wasmType.Results); i++ {
+		functionType.ReturnTypes = append(functionType.ReturnTypes, wasmValueTypeToType[wasmType.Results[i]])
+	}
+
+	return c.typeSection.addType(functionType), nil
+}
+
+var wasmValueTypeToType = map[uint8]types.Type{
+	code.I32: types.StandardType{Name: token.INT},
+	code.I64: types.StandardType{Name: token.LONG},
+	code.F32: types.StandardType{Name: token.FLOAT},
+	code.F64: types.StandardType{Name: token.DOUBLE},
+}
+
+// The files are only decoded once
+func (c *compiler) getStandardFunctionsModule(fileName string) (*wasmDecoder.Module, error) {
+	if module, isDecoded := c.standardFunctions.modules[fileName]; isDecoded {
+		return module, nil
+	}
+
+	module, err := wasmDecoder.DecodeFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.standardFunctions.modules[fileName] = module
+	return module, nil
+}
+
+func getStandardFunctionName(definition standardDefinition) string {
+	for i := 0; i < len(standardFunctionsData); i++ {
+		if standardFunctionsData[i].fileName == definition.fileName && standardFunctionsData[i].funcIndex == definition.index {
+			return standardFunctionsData[i].name
+		}
+	}
+
+	return fmt.Sprintf("function %v in %s", definition.index, definition.fileName)
 }
 
 func getStandardFunctionRealName(functionName string, functionArguments []types.Type) (string, error) {
-	for _, functionNameNotDependingOnArgumentsTypes := range []string{"array", "allocate", "deAllocate", "length", "take", "tail"} {
+	for _, functionNameNotDependingOnArgumentsTypes := range []string{"array", "allocate", "deAllocate", "length", "take", "tail", "seed", "randomInt", "randomFloat"} {
 		if functionNameNotDependingOnArgumentsTypes == functionName {
 			return functionName, nil
 		}
@@ -86,36 +271,49 @@ func getStandardFunctionRealName(functionName string, functionArguments []types.
 		return typePrefix + functionName, err
 	}
 
+	if functionName == "min" || functionName == "max" || functionName == "abs" {
+		if len(functionArguments) < 1 {
+			return "", fmt.Errorf("Error in validation process: wrong amount of arguments in %s call", functionName)
+		}
+
+		typePrefix, err := getNumberTypePrefix(functionArguments[0])
+		return typePrefix + functionName, err
+	}
+
+	if isDoubleStandardFunction[functionName] {
+		return "f64" + functionName, nil
+	}
+
 	return "", fmt.Errorf("Internal compiler error: getting real name of %s not implemented", functionName)
 }
 
-func getStandardFunctionExtraArguments(functionName string, functionArguments []types.Type) ([]byte, error) {
+func getStandardFunctionExtraArguments(functionName string, functionArguments []types.Type) ([]wasmDecoder.Instruction, error) {
 	switch functionName {
 	case "take":
 		if len(functionArguments) != 2 {
-			return []byte{}, fmt.Errorf("Error in validation process: wrong amount of arguments to take ")
+			return []wasmDecoder.Instruction{}, fmt.Errorf("Error in validation process: wrong amount of arguments to take ")
 		}
 
 		sizeOfElementsInArray, err := getArrayTypeElementSize(functionArguments[1])
 		if err != nil {
-			return []byte{}, err
+			return []wasmDecoder.Instruction{}, err
 		}
 
 		return addConst(sizeOfElementsInArray), nil
 	case "tail":
 		if len(functionArguments) != 1 {
-			return []byte{}, fmt.Errorf("Error in validation process: wrong amount of arguments to tail ")
+			return []wasmDecoder.Instruction{}, fmt.Errorf("Error in validation process: wrong amount of arguments to tail ")
 		}
 
 		sizeOfElementsInArray, err := getArrayTypeElementSize(functionArguments[0])
 		if err != nil {
-			return []byte{}, err
+			return []wasmDecoder.Instruction{}, err
 		}
 
 		return addConst(sizeOfElementsInArray), nil
 	}
 
-	return []byte{}, nil
+	return []wasmDecoder.Instruction{}, nil
 }
 
 func getArrayTypePrefix(inputType types.Type) (string, error) {
@@ -131,6 +329,10 @@ func getArrayTypePrefix(inputType types.Type) (string, error) {
 			return "i32", nil
 		case token.FLOAT:
 			return "f32", nil
+		case token.LONG:
+			return "i64", nil
+		case token.DOUBLE:
+			return "f64", nil
 		case token.BOOL:
 			return "i8", nil
 		case token.STRING:
@@ -143,37 +345,78 @@ func getArrayTypePrefix(inputType types.Type) (string, error) {
 		return "i32", nil
 	case types.FunctionType:
 		return "i32", nil
+	case types.RecordType:
+		return "i32", nil
+	case types.UnionType:
+		return "i32", nil
+	case types.TupleType:
+		return "i32", nil
 	}
 
 	return "", fmt.Errorf("Type %s given to getArrayTypePrefix not supported", inputType)
 }
 
+func getNumberTypePrefix(inputType types.Type) (string, error) {
+	switch inputType.String() {
+	case token.INT:
+		return "i32", nil
+	case token.LONG:
+		return "i64", nil
+	case token.FLOAT:
+		return "f32", nil
+	case token.DOUBLE:
+		return "f64", nil
+	}
+
+	return "", fmt.Errorf("Type %s given to getNumberTypePrefix not supported", inputType)
+}
+
 func getArrayTypeElementSize(inputType types.Type) (int, error) {
 	arrayType, isArrayType := inputType.(types.ArrayType)
 	if !isArrayType {
 		return 0, fmt.Errorf("Internal compiler error: argument inputType in getArrayTypeElementSize not of arrayType")
 	}
 
-	switch t := arrayType.ElementType.(type) {
+	size, err := getTypeSize(arrayType.ElementType)
+	if err != nil {
+		return 0, fmt.Errorf("Type %s given to getArrayTypeElementSize not supported", arrayType)
+	}
+
+	return size, nil
+}
+
+//Returns the number of bytes used to store a value of the type in memory
+func getTypeSize(inputType types.Type) (int, error) {
+	switch t := inputType.(type) {
 	case types.StandardType:
 		switch t.Name {
 		case token.INT:
 			return 4, nil
 		case token.FLOAT:
 			return 4, nil
+		case token.LONG:
+			return 8, nil
+		case token.DOUBLE:
+			return 8, nil
 		case token.BOOL:
 			return 1, nil
 		case token.STRING:
 			return 4, nil
 		default:
-			return 0, fmt.Errorf("Type %s given to getArrayTypeElementSize not supported", arrayType)
+			return 0, fmt.Errorf("Type %s given to getTypeSize not supported", inputType)
 		}
 
 	case types.ArrayType:
 		return 4, nil
 	case types.FunctionType:
 		return 4, nil
+	case types.RecordType:
+		return 4, nil
+	case types.UnionType:
+		return 4, nil
+	case types.TupleType:
+		return 4, nil
 	}
 
-	return 0, fmt.Errorf("Type %s given to getArrayTypeElementSize not supported", arrayType)
+	return 0, fmt.Errorf("Type %s given to getTypeSize not supported", inputType)
 }
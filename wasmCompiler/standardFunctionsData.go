@@ -1,128 +1,55 @@
 package wasmCompiler
 
 import (
-	"compiler/token"
-	"compiler/types"
+	"path/filepath"
+	"strings"
 )
 
 type standardFunctionDataElement struct {
 	fileName  string
 	funcIndex int
-	funcType  types.FunctionType
 	name      string
 }
 
+// The standard functions written in wasm. Their types are read from the files, and the functions and globals they use
+// are imported together with them
 var standardFunctionsData []standardFunctionDataElement = []standardFunctionDataElement{
-	{
-		name: "allocate",
-		funcType: types.FunctionType{
-			ArgumentTypes: []types.Type{types.StandardType{Name: token.INT}},
-			ReturnTypes:   []types.Type{types.StandardType{Name: token.INT}},
-		},
-		fileName:  "./builtInsCode/memoryManagement.wasm",
-		funcIndex: 0,
-	},
-	{
-		name: "deAllocate",
-		funcType: types.FunctionType{
-			ArgumentTypes: []types.Type{types.StandardType{Name: token.INT}},
-			ReturnTypes:   []types.Type{},
-		},
-		fileName:  "./builtInsCode/memoryManagement.wasm",
-		funcIndex: 1,
-	},
-	{
-		name: "array",
-		funcType: types.FunctionType{
-			ArgumentTypes: []types.Type{types.StandardType{Name: token.INT}, types.StandardType{Name: token.INT}},
-			ReturnTypes:   []types.Type{types.StandardType{Name: token.INT}},
-		},
-		fileName:  "./builtInsCode/memoryManagement.wasm",
-		funcIndex: 2,
-	},
-	{
-		name: "take",
-		funcType: types.FunctionType{
-			ArgumentTypes: []types.Type{types.StandardType{Name: token.INT}, types.StandardType{Name: token.INT}, types.StandardType{Name: token.INT}},
-			ReturnTypes:   []types.Type{types.StandardType{Name: token.INT}},
-		},
-		fileName:  "./builtInsCode/memoryManagement.wasm",
-		funcIndex: 3,
-	},
-	{
-		name: "tail",
-		funcType: types.FunctionType{
-			ArgumentTypes: []types.Type{types.StandardType{Name: token.INT}, types.StandardType{Name: token.INT}},
-			ReturnTypes:   []types.Type{types.StandardType{Name: token.INT}},
-		},
-		fileName:  "./builtInsCode/memoryManagement.wasm",
-		funcIndex: 4,
-	},
-
-	{
-		name: "i32get",
-		funcType: types.FunctionType{
-			ArgumentTypes: []types.Type{types.StandardType{Name: token.INT}, types.StandardType{Name: token.INT}},
-			ReturnTypes:   []types.Type{types.StandardType{Name: token.INT}},
-		},
-		fileName:  "./builtInsCode/setterAndGetters.wasm",
-		funcIndex: 0,
-	},
-	{
-		name: "i8get",
-		funcType: types.FunctionType{
-			ArgumentTypes: []types.Type{types.StandardType{Name: token.INT}, types.StandardType{Name: token.INT}},
-			ReturnTypes:   []types.Type{types.StandardType{Name: token.INT}},
-		},
-		fileName:  "./builtInsCode/setterAndGetters.wasm",
-		funcIndex: 1,
-	},
-	{
-		name: "f32get",
-		funcType: types.FunctionType{
-			ArgumentTypes: []types.Type{types.StandardType{Name: token.INT}, types.StandardType{Name: token.INT}},
-			ReturnTypes:   []types.Type{types.StandardType{Name: token.FLOAT}},
-		},
-		fileName:  "./builtInsCode/setterAndGetters.wasm",
-		funcIndex: 2,
-	},
-
-	{
-		name: "i32set",
-		funcType: types.FunctionType{
-			ArgumentTypes: []types.Type{types.StandardType{Name: token.INT}, types.StandardType{Name: token.INT}, types.StandardType{Name: token.INT}},
-			ReturnTypes:   []types.Type{types.StandardType{Name: token.INT}},
-		},
-		fileName:  "./builtInsCode/setterAndGetters.wasm",
-		funcIndex: 3,
-	},
-	{
-		name: "i8set",
-		funcType: types.FunctionType{
-			ArgumentTypes: []types.Type{types.StandardType{Name: token.INT}, types.StandardType{Name: token.INT}, types.StandardType{Name: token.INT}},
-			ReturnTypes:   []types.Type{types.StandardType{Name: token.INT}},
-		},
-		fileName:  "./builtInsCode/setterAndGetters.wasm",
-		funcIndex: 4,
-	},
-	{
-		name: "f32set",
-		funcType: types.FunctionType{
-			ArgumentTypes: []types.Type{types.StandardType{Name: token.INT}, types.StandardType{Name: token.INT}, types.StandardType{Name: token.FLOAT}},
-			ReturnTypes:   []types.Type{types.StandardType{Name: token.INT}},
-		},
-		fileName:  "./builtInsCode/setterAndGetters.wasm",
-		funcIndex: 5,
-	},
-	{
-		name: "length",
-		funcType: types.FunctionType{
-			ArgumentTypes: []types.Type{types.StandardType{Name: token.INT}},
-			ReturnTypes:   []types.Type{types.StandardType{Name: token.INT}},
-		},
-		fileName:  "./builtInsCode/arrayFunctions.wasm",
-		funcIndex: 0,
-	},
+	{name: "allocate", fileName: "./builtInsCode/memoryManagement.wasm", funcIndex: 0},
+	{name: "deAllocate", fileName: "./builtInsCode/memoryManagement.wasm", funcIndex: 1},
+	{name: "array", fileName: "./builtInsCode/memoryManagement.wasm", funcIndex: 2},
+	{name: "take", fileName: "./builtInsCode/memoryManagement.wasm", funcIndex: 3},
+	{name: "tail", fileName: "./builtInsCode/memoryManagement.wasm", funcIndex: 4},
+	{name: "i32get", fileName: "./builtInsCode/setterAndGetters.wasm", funcIndex: 0},
+	{name: "i8get", fileName: "./builtInsCode/setterAndGetters.wasm", funcIndex: 1},
+	{name: "f32get", fileName: "./builtInsCode/setterAndGetters.wasm", funcIndex: 2},
+	{name: "i32set", fileName: "./builtInsCode/setterAndGetters.wasm", funcIndex: 3},
+	{name: "i8set", fileName: "./builtInsCode/setterAndGetters.wasm", funcIndex: 4},
+	{name: "f32set", fileName: "./builtInsCode/setterAndGetters.wasm", funcIndex: 5},
+	{name: "i64get", fileName: "./builtInsCode/setterAndGetters.wasm", funcIndex: 6},
+	{name: "f64get", fileName: "./builtInsCode/setterAndGetters.wasm", funcIndex: 7},
+	{name: "i64set", fileName: "./builtInsCode/setterAndGetters.wasm", funcIndex: 8},
+	{name: "f64set", fileName: "./builtInsCode/setterAndGetters.wasm", funcIndex: 9},
+	{name: "length", fileName: "./builtInsCode/arrayFunctions.wasm", funcIndex: 0},
+	{name: "i32min", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 0},
+	{name: "i32max", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 1},
+	{name: "i32abs", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 2},
+	{name: "i64min", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 3},
+	{name: "i64max", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 4},
+	{name: "i64abs", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 5},
+	{name: "f32min", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 6},
+	{name: "f32max", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 7},
+	{name: "f32abs", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 8},
+	{name: "f64min", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 9},
+	{name: "f64max", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 10},
+	{name: "f64abs", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 11},
+	{name: "f64exp", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 12},
+	{name: "f64log", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 13},
+	{name: "f64pow", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 14},
+	{name: "f64sin", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 15},
+	{name: "f64cos", fileName: "./builtInsCode/mathFunctions.wasm", funcIndex: 16},
+	{name: "seed", fileName: "./builtInsCode/randomFunctions.wasm", funcIndex: 0},
+	{name: "randomInt", fileName: "./builtInsCode/randomFunctions.wasm", funcIndex: 1},
+	{name: "randomFloat", fileName: "./builtInsCode/randomFunctions.wasm", funcIndex: 2},
 }
 
 var isOpenStandardFunction = map[string]bool{
@@ -131,4 +58,43 @@ var isOpenStandardFunction = map[string]bool{
 	"length": true,
 	"take":   true,
 	"tail":   true,
+	"min":    true,
+	"max":    true,
+	"abs":    true,
+	"pow":    true,
+	"exp":    true,
+	"log":    true,
+	"sin":    true,
+	"cos":    true,
+
+	"seed":        true,
+	"randomInt":   true,
+	"randomFloat": true,
+}
+
+// Standard functions only written for doubles. Float arguments are promoted to double and the result demoted back to float.
+var isDoubleStandardFunction = map[string]bool{
+	"pow": true,
+	"exp": true,
+	"log": true,
+	"sin": true,
+	"cos": true,
+}
+
+// Gives the names of the standard functions in the file by their function index in the file, so the indexes in
+// standardFunctionsData can be compared with the files. Functions with more than one name have their names joined
+func StandardFunctionNames(fileName string) map[int]string {
+	names := make(map[int][]string)
+	for i := 0; i < len(standardFunctionsData); i++ {
+		if filepath.Base(standardFunctionsData[i].fileName) == filepath.Base(fileName) {
+			names[standardFunctionsData[i].funcIndex] = append(names[standardFunctionsData[i].funcIndex], standardFunctionsData[i].name)
+		}
+	}
+
+	joinedNames := make(map[int]string)
+	for index := range names {
+		joinedNames[index] = strings.Join(names[index], ", ")
+	}
+
+	return joinedNames
 }
@@ -0,0 +1,27 @@
+package wasmCompiler
+
+import (
+	"compiler/wasmDecoder"
+)
+
+type startSection struct {
+	functionIndex    int
+	hasStartFunction bool
+}
+
+func newStartSection() *startSection {
+	return &startSection{
+		functionIndex:    0,
+		hasStartFunction: false,
+	}
+}
+
+func (s *startSection) setFunction(functionIndex int) {
+	s.functionIndex = functionIndex
+	s.hasStartFunction = true
+}
+
+func (s *startSection) addToModule(m *wasmDecoder.Module) {
+	m.HasStart = s.hasStartFunction
+	m.Start = uint32(s.functionIndex)
+}
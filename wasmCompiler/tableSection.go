@@ -1,8 +1,8 @@
 package wasmCompiler
 
 import (
-	"compiler/leb128"
 	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
 )
 
 type tableSection struct {
@@ -20,23 +20,14 @@ func (s *tableSection) addFunction() int {
 	return s.numFunctions - 1
 }
 
-func (s *tableSection) toByteCode() []uint8 {
+//The table holds every function, so functions can be called with their table index
+func (s *tableSection) addToModule(m *wasmDecoder.Module) {
 	if s.numFunctions == 0 {
-		return []uint8{}
+		return
 	}
 
-	byteCode := leb128.Int32ToULEB128(int32(1)) //Number of tables?
-
-	byteCode = append(byteCode, code.ANYFUNC)
-	byteCode = append(byteCode, leb128.Int32ToULEB128(int32(1))...) //???
-
-	byteCode = append(byteCode, leb128.Int32ToULEB128(int32(s.numFunctions))...) //Table min values
-	byteCode = append(byteCode, leb128.Int32ToULEB128(int32(s.numFunctions))...) // table max values
-
-	return createSection(code.SECTION_TABLE, byteCode)
+	m.Tables = []wasmDecoder.Table{{
+		ElementType: code.ANYFUNC,
+		Limits:      wasmDecoder.Limits{Min: uint32(s.numFunctions), Max: uint32(s.numFunctions), HasMax: true},
+	}}
 }
-
-/*
-
-
- */
@@ -0,0 +1,64 @@
+package wasmCompiler
+
+import (
+	"compiler/ast"
+	"compiler/wasmDecoder"
+)
+
+//Tuples are stored in memory allocated with allocate, with the values stored after each other like the fields of a record
+
+func (c *compiler) createTupleCode(expression ast.TupleExpression, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	outputCode := make([]wasmDecoder.Instruction, 0)
+	valuesCode := make([][]wasmDecoder.Instruction, 0)
+
+	for i := 0; i < len(expression.Elements); i++ {
+		elementCode, err := c.compileExpression(expression.Elements[i], functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		valuesCode = append(valuesCode, elementCode)
+	}
+
+	//The values returned by a function are all on the stack, so they are stored in locals before the tuple is allocated
+	if len(valuesCode) != len(expression.Type.ElementTypes) {
+		outputCode = flatten(valuesCode)
+		valuesCode = make([][]wasmDecoder.Instruction, len(expression.Type.ElementTypes))
+
+		for i := len(expression.Type.ElementTypes) - 1; i >= 0; i-- {
+			valueVariableIndex := functionLocals.defineLocalVariable(expression.Type.ElementTypes[i], "", c.symbolController)
+			outputCode = append(outputCode, localSet(valueVariableIndex))
+			valuesCode[i] = []wasmDecoder.Instruction{localGet(valueVariableIndex)}
+		}
+	}
+
+	tupleCode, err := c.createStoredValuesCode(expression.Type.ElementTypes, valuesCode, functionLocals)
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	return append(outputCode, tupleCode...), nil
+}
+
+//Loads every value of the tuple onto the stack, in the order they are stored in the tuple
+func (c *compiler) createTupleUnpackCode(expression ast.TupleUnpackExpression, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	outputCode, err := c.compileExpression(expression.Tuple, functionLocals)
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	tupleVariableIndex := functionLocals.defineLocalVariable(expression.Type, "", c.symbolController)
+	outputCode = append(outputCode, localSet(tupleVariableIndex))
+	tupleCode := []wasmDecoder.Instruction{localGet(tupleVariableIndex)}
+
+	for i := 0; i < len(expression.Type.ElementTypes); i++ {
+		valueCode, err := createLoadValueCode(expression.Type.ElementTypes, i, tupleCode)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		outputCode = append(outputCode, valueCode...)
+	}
+
+	return outputCode, nil
+}
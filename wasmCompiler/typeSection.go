@@ -1,23 +1,20 @@
 package wasmCompiler
 
 import (
-	"compiler/leb128"
 	"compiler/types"
-	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
 	"fmt"
 )
 
 type typeSection struct {
-	typesByteCode   []uint8
-	numTypes        int
+	types           []wasmDecoder.FunctionType
 	typeToTypeIndex map[string]int
 }
 
 func newTypeSection() *typeSection {
 	return &typeSection{
 		typeToTypeIndex: make(map[string]int),
-		numTypes:        0,
-		typesByteCode:   make([]uint8, 0),
+		types:           make([]wasmDecoder.FunctionType, 0),
 	}
 }
 
@@ -30,25 +27,25 @@ func (s *typeSection) getFunctionTypeIndex(functionType types.FunctionType) (int
 	return -1, fmt.Errorf("Internal compiler error: function type (%s) given to get function type index is not defined", functionType.String())
 }
 
-//Makes sure the type is in the type section byte code and returns the type index
+//Makes sure the type is in the type section and returns the type index
 func (s *typeSection) addType(functionType types.FunctionType) int {
 	if typeIndex, typeIsDeclared := s.typeToTypeIndex[functionType.String()]; typeIsDeclared {
 		return typeIndex
 	}
 
-	typeIndex := s.numTypes
-	s.numTypes++
+	typeIndex := len(s.types)
 	s.typeToTypeIndex[functionType.String()] = typeIndex
 
-	s.typesByteCode = append(s.typesByteCode, code.FUNC)
-	s.typesByteCode = append(s.typesByteCode, encodeVector(listOfTypesToByteCodeOfTypes(functionType.ArgumentTypes))...)
-	s.typesByteCode = append(s.typesByteCode, encodeVector(listOfTypesToByteCodeOfTypes(functionType.ReturnTypes))...)
+	s.types = append(s.types, wasmDecoder.FunctionType{
+		Parameters: listOfTypesToByteCodeOfTypes(functionType.ArgumentTypes),
+		Results:    listOfTypesToByteCodeOfTypes(functionType.ReturnTypes),
+	})
 
 	return typeIndex
 }
 
-func (s *typeSection) toByteCode() []byte {
-	return createSection(code.SECTION_TYPE, append(leb128.Int32ToULEB128(int32(s.numTypes)), s.typesByteCode...))
+func (s *typeSection) addToModule(m *wasmDecoder.Module) {
+	m.Types = s.types
 }
 
 func listOfTypesToByteCodeOfTypes(inputTypes []types.Type) []uint8 {
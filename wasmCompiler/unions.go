@@ -0,0 +1,151 @@
+package wasmCompiler
+
+import (
+	"compiler/ast"
+	"compiler/token"
+	"compiler/types"
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
+	"fmt"
+)
+
+//Values of union types are stored in memory allocated with allocate. The tag, which is the index of the variant, is stored first, followed by the values held by the variant
+
+func (c *compiler) createVariantCode(expression ast.VariantExpression, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	tag, isVariant := expression.Type.GetVariant(expression.Variant)
+	if !isVariant {
+		return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: union type %s has no variant %s", expression.Type.Name, expression.Variant)
+	}
+
+	valuesCode := [][]wasmDecoder.Instruction{addConst(tag)}
+	for i := 0; i < len(expression.Arguments); i++ {
+		argumentCode, err := c.compileExpression(expression.Arguments[i], functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		valuesCode = append(valuesCode, argumentCode)
+	}
+
+	return c.createStoredValuesCode(getVariantValueTypes(expression.Type.Variants[tag]), valuesCode, functionLocals)
+}
+
+//The arms are compiled in blocks inside each other, with the br_table jumping to the end of the block before the code of the arm matching the tag.
+//
+//	block (result)
+//	  block
+//	    block
+//	      tag
+//	      br_table
+//	    end
+//	    arm 0
+//	    br 1
+//	  end
+//	  arm 1
+//	end
+func (c *compiler) createMatchCode(expression ast.MatchExpression, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	unionType, isUnionType := expression.Type.(types.UnionType)
+	if !isUnionType {
+		return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: type in ast.MatchExpression not union")
+	}
+
+	outputCode, err := c.compileExpression(expression.Value, functionLocals)
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	valueVariableIndex := functionLocals.defineLocalVariable(types.StandardType{Name: token.INT}, "", c.symbolController)
+	outputCode = append(outputCode, localSet(valueVariableIndex))
+	valueCode := []wasmDecoder.Instruction{localGet(valueVariableIndex)}
+
+	//The arm used for each tag
+	armIndexes := make([]int, len(unionType.Variants))
+	for i := len(expression.Arms) - 1; i >= 0; i-- {
+		if expression.Arms[i].Variant == "" {
+			for j := 0; j < len(armIndexes); j++ {
+				armIndexes[j] = i
+			}
+
+			continue
+		}
+
+		tag, isVariant := unionType.GetVariant(expression.Arms[i].Variant)
+		if !isVariant {
+			return []wasmDecoder.Instruction{}, fmt.Errorf("Internal compiler error: union type %s has no variant %s", unionType.Name, expression.Arms[i].Variant)
+		}
+
+		armIndexes[tag] = i
+	}
+
+	matchTypeIndex := c.typeSection.addType(types.FunctionType{
+		ArgumentTypes: []types.Type{},
+		ReturnTypes:   expression.ReturnTypes,
+	})
+
+	outputCode = append(outputCode, blockInstruction(code.BLOCK, matchTypeIndex))
+	for i := 0; i < len(expression.Arms); i++ {
+		outputCode = append(outputCode, emptyBlockInstruction(code.BLOCK))
+	}
+
+	tagCode, err := createLoadValueCode([]types.Type{types.StandardType{Name: token.INT}}, 0, valueCode)
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	outputCode = append(outputCode, tagCode...)
+	outputCode = append(outputCode, branchTable(append(armIndexes, armIndexes[len(armIndexes)-1]))) //The default label is never used since all tags are in the table
+	outputCode = append(outputCode, instruction(code.END))
+
+	for i := 0; i < len(expression.Arms); i++ {
+		armCode, err := c.createMatchArmCode(expression.Arms[i], unionType, valueCode, functionLocals)
+		if err != nil {
+			return []wasmDecoder.Instruction{}, err
+		}
+
+		outputCode = append(outputCode, armCode...)
+		outputCode = append(outputCode, indexInstruction(code.BR, len(expression.Arms)-1-i))
+		outputCode = append(outputCode, instruction(code.END))
+	}
+
+	return outputCode, nil
+}
+
+//The values held by the variant are loaded into the local variables named in the arm before the expression of the arm
+func (c *compiler) createMatchArmCode(arm ast.MatchArm, unionType types.UnionType, valueCode []wasmDecoder.Instruction, functionLocals *functionLocals) ([]wasmDecoder.Instruction, error) {
+	outputCode := make([]wasmDecoder.Instruction, 0)
+
+	c.symbolController.PushBlock()
+	defer c.symbolController.PopBlock()
+
+	if arm.Variant != "" {
+		tag, _ := unionType.GetVariant(arm.Variant)
+		valueTypes := getVariantValueTypes(unionType.Variants[tag])
+
+		for i := 0; i < len(arm.Bindings); i++ {
+			if arm.Bindings[i].Identifier == token.WILDCARD {
+				continue
+			}
+
+			loadCode, err := createLoadValueCode(valueTypes, i+1, valueCode)
+			if err != nil {
+				return []wasmDecoder.Instruction{}, err
+			}
+
+			variableIndex := functionLocals.defineLocalVariable(arm.Bindings[i].Type, arm.Bindings[i].Identifier, c.symbolController)
+			outputCode = append(outputCode, loadCode...)
+			outputCode = append(outputCode, localSet(variableIndex))
+		}
+	}
+
+	expressionCode, err := c.compileExpression(arm.Expression, functionLocals)
+	if err != nil {
+		return []wasmDecoder.Instruction{}, err
+	}
+
+	return append(outputCode, expressionCode...), nil
+}
+
+//The types of the values stored in memory for the variant, starting with the tag
+func getVariantValueTypes(variant types.UnionVariant) []types.Type {
+	return append([]types.Type{types.StandardType{Name: token.INT}}, variant.Types...)
+}
@@ -0,0 +1,7 @@
+//go:build !debug
+// +build !debug
+
+package wasmCompiler
+
+// The compiled module is only validated in debug builds, which are built with: go build -tags debug
+const validateOutput = false
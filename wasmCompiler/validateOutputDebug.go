@@ -0,0 +1,8 @@
+//go:build debug
+// +build debug
+
+package wasmCompiler
+
+// Debug builds validate the compiled module before it is written, so errors in the compiler give an internal error
+// with the name of the function instead of an error from the wasm engine
+const validateOutput = true
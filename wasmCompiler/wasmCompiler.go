@@ -2,16 +2,31 @@ package wasmCompiler
 
 import (
 	"compiler/ast"
-	"compiler/leb128"
 	"compiler/symbolTable"
 	"compiler/validator"
-	"compiler/wasmCompiler/code"
-	"encoding/binary"
+	"compiler/wasmDecoder"
+	"compiler/wasmText"
+	"compiler/wasmValidator"
 	"fmt"
-	"math"
 )
 
 func Compile(syntaxTree ast.Program) ([]byte, error) {
+	byteCode, _, _, err := compileModule(syntaxTree)
+	return byteCode, err
+}
+
+// Gives the compiled module in the WebAssembly text format, with the names of the functions and their variables from
+// the Waffle code. Folded output nests the operands of instructions inside them
+func CompileToText(syntaxTree ast.Program, folded bool) (string, error) {
+	_, module, c, err := compileModule(syntaxTree)
+	if err != nil {
+		return "", err
+	}
+
+	return wasmText.PrintModule(module, wasmText.Names{Functions: c.functionNames, Locals: c.localNames, Globals: c.globalNames}, folded)
+}
+
+func compileModule(syntaxTree ast.Program) ([]byte, *wasmDecoder.Module, *compiler, error) {
 	c := &compiler{
 		typeSection:       newTypeSection(),
 		tableSection:      newTableSection(),
@@ -20,16 +35,33 @@ func Compile(syntaxTree ast.Program) ([]byte, error) {
 		codeSection:       newCodeSection(),
 		exportSection:     newExportSection(),
 		memorySection:     newMemorySection(1), //memory size in pages
+		globalSection:     newGlobalSection(),
+		startSection:      newStartSection(),
 		symbolController:  symbolTable.NewSymbolController(),
-		standardFunctions: standardFunctions{standardFunctionIndexes: make(map[string]typeAndFuncIndex)},
+		standardFunctions: newStandardFunctions(),
+		genericFunctions:  make(map[string]*genericFunction),
+		functionNames:     make(map[int]string),
+		localNames:        make(map[int]map[int]string),
+		globalNames:       make(map[int]string),
 	}
 
 	err := c.compile(syntaxTree)
 	if err != nil {
-		return []byte{}, err
+		return []byte{}, nil, nil, err
 	}
 
-	return c.toByteCode(), nil
+	module := c.toModule()
+	byteCode := wasmDecoder.Encode(module)
+
+	//Errors in the compiler are found here instead of when the module is compiled by the wasm engine
+	if validateOutput {
+		err = wasmValidator.Validate(byteCode, c.functionNames)
+		if err != nil {
+			return []byte{}, nil, nil, fmt.Errorf("Internal compiler error: the compiled module is not valid wasm: %s", err.Error())
+		}
+	}
+
+	return byteCode, module, c, nil
 }
 
 type compiler struct {
@@ -40,8 +72,19 @@ type compiler struct {
 	codeSection       *codeSection
 	exportSection     *exportSection
 	memorySection     *memorySection
+	globalSection     *globalSection
+	startSection      *startSection
 	symbolController  *symbolTable.SymbolController
 	standardFunctions standardFunctions
+
+	globalInitializers []ast.AssignmentStatement
+	genericFunctions   map[string]*genericFunction
+
+	//The names of the functions and their locals are used in the text format and in the errors from the validation of
+	//the compiled module. The names of the globals are only used in the text format
+	functionNames map[int]string
+	localNames    map[int]map[int]string
+	globalNames   map[int]string
 }
 
 func (c *compiler) compile(syntaxTree ast.Program) error {
@@ -50,21 +93,56 @@ func (c *compiler) compile(syntaxTree ast.Program) error {
 		return err
 	}
 
-	err = c.importMemoryHandler()
-	if err != nil {
-		return err
+	//All global functions are defined before any code is compiled so functions can use functions defined later in the file
+	for i := 0; i < len(validated.Body.Statements); i++ {
+		assignStatement, ok := validated.Body.Statements[i].(ast.AssignmentStatement)
+		if !ok {
+			continue
+		}
+
+		if functionDeclaration, ok := assignStatement.Value.(ast.DefineFunctionExpression); ok {
+			if len(functionDeclaration.TypeParameters) != 0 {
+				err := c.defineGenericFunction(assignStatement.Variables[0].Identifier, functionDeclaration)
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			err := c.defineGlobalFunction(assignStatement.Variables[0].Identifier, functionDeclaration)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	for i := 0; i < len(validated.Body.Statements); i++ {
 		curStatement := validated.Body.Statements[i]
+
+		//Type definitions are only used by the validator
+		if _, isTypeDefinition := curStatement.(ast.TypeDefinitionStatement); isTypeDefinition {
+			continue
+		}
+
 		assignStatement, ok := curStatement.(ast.AssignmentStatement)
 		if !ok {
-			return fmt.Errorf("Only function declaration valid in global scope")
+			return fmt.Errorf("Only assignment statements valid in global scope")
 		}
 
 		functionDeclaration, ok := assignStatement.Value.(ast.DefineFunctionExpression)
 		if !ok {
-			return fmt.Errorf("Only function declaration valid in global scope")
+			err := c.addGlobalValues(assignStatement)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		//Generic functions are compiled when they are used
+		if len(functionDeclaration.TypeParameters) != 0 {
+			continue
 		}
 
 		err := c.addGlobalFunction(assignStatement.Variables[0].Identifier, functionDeclaration)
@@ -73,36 +151,23 @@ func (c *compiler) compile(syntaxTree ast.Program) error {
 		}
 	}
 
-	return nil
-}
-
-func (c *compiler) toByteCode() []byte {
-	result := make([]byte, 0)
-
-	result = append(result, code.MagicModuleHeader...)
-	result = append(result, code.ModuleVersion...)
-	result = append(result, c.typeSection.toByteCode()...)
-	result = append(result, c.funcSection.toByteCode()...)
-	result = append(result, c.tableSection.toByteCode()...)
-	result = append(result, c.memorySection.toByteCode()...)
-	result = append(result, c.exportSection.toByteCode()...)
-	result = append(result, c.elementSection.toByteCode()...)
-	result = append(result, c.codeSection.toByteCode()...)
-
-	return result
-}
-
-func encodeVector(data []uint8) []uint8 {
-	return append(leb128.Int32ToULEB128(int32(len(data))), data...)
-}
-
-func createSection(sectionType uint8, data []uint8) []uint8 {
-	return append([]uint8{sectionType}, encodeVector(data)...)
+	return c.addStartFunction()
 }
 
-func float32ToLittleEndian(f float32) []byte {
-	bits := math.Float32bits(f)
-	buf := make([]byte, 4)
-	binary.LittleEndian.PutUint32(buf, bits)
-	return buf
+// The module is built from the sections and encoded by the encoder of the decoder, so the text format can be printed
+// from the same module
+func (c *compiler) toModule() *wasmDecoder.Module {
+	m := &wasmDecoder.Module{}
+
+	c.typeSection.addToModule(m)
+	c.funcSection.addToModule(m)
+	c.tableSection.addToModule(m)
+	c.memorySection.addToModule(m)
+	c.globalSection.addToModule(m)
+	c.exportSection.addToModule(m)
+	c.startSection.addToModule(m)
+	c.elementSection.addToModule(m)
+	c.codeSection.addToModule(m)
+
+	return m
 }
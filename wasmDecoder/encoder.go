@@ -0,0 +1,242 @@
+package wasmDecoder
+
+import (
+	"compiler/leb128"
+	"compiler/wasmCompiler/code"
+	"encoding/binary"
+	"math"
+)
+
+//The encoder writes a module in the binary format, so a module built from its typed sections and instructions can be
+//given to a wasm engine. Sections without content are left out. The offsets, sizes and bytes read by the decoder are
+//not used, so decoded modules can be changed and encoded again
+
+func Encode(m *Module) []byte {
+	output := append(append([]byte{}, code.MagicModuleHeader...), code.ModuleVersion...)
+
+	output = appendSection(output, code.SECTION_TYPE, len(m.Types), func(i int, content []byte) []byte {
+		content = append(content, code.FUNC)
+		content = appendValueTypes(content, m.Types[i].Parameters)
+		return appendValueTypes(content, m.Types[i].Results)
+	})
+
+	output = appendSection(output, code.SECTION_IMPORT, len(m.Imports), func(i int, content []byte) []byte {
+		imported := m.Imports[i]
+		content = appendName(appendName(content, imported.Module), imported.Name)
+		content = append(content, imported.Kind)
+
+		switch imported.Kind {
+		case code.DESC_FUNCTION:
+			return appendU32(content, imported.TypeIndex)
+		case code.DESC_TABLE:
+			return appendTable(content, imported.Table)
+		case code.DESC_MEMORY:
+			return appendLimits(content, imported.Memory)
+		}
+
+		return appendGlobalType(content, imported.Global)
+	})
+
+	output = appendSection(output, code.SECTION_FUNCTION, len(m.Functions), func(i int, content []byte) []byte {
+		return appendU32(content, m.Functions[i])
+	})
+
+	output = appendSection(output, code.SECTION_TABLE, len(m.Tables), func(i int, content []byte) []byte {
+		return appendTable(content, m.Tables[i])
+	})
+
+	output = appendSection(output, code.SECTION_MEMORY, len(m.Memories), func(i int, content []byte) []byte {
+		return appendLimits(content, m.Memories[i])
+	})
+
+	output = appendSection(output, code.SECTION_GLOBAL, len(m.Globals), func(i int, content []byte) []byte {
+		content = appendGlobalType(content, m.Globals[i].Type)
+		return append(content, EncodeInstructions(m.Globals[i].Init)...)
+	})
+
+	output = appendSection(output, code.SECTION_EXPORT, len(m.Exports), func(i int, content []byte) []byte {
+		content = append(appendName(content, m.Exports[i].Name), m.Exports[i].Kind)
+		return appendU32(content, m.Exports[i].Index)
+	})
+
+	if m.HasStart {
+		output = append(output, code.SECTION_START)
+		output = appendVector(output, appendU32([]byte{}, m.Start))
+	}
+
+	output = appendSection(output, code.SECTION_ELEMENT, len(m.Elements), func(i int, content []byte) []byte {
+		element := m.Elements[i]
+
+		//Flags 2 gives the table index and the element kind, which is 0 for function indexes
+		if element.TableIndex == 0 {
+			content = append(content, 0)
+		} else {
+			content = appendU32(append(content, 2), element.TableIndex)
+		}
+
+		content = append(content, EncodeInstructions(element.Offset)...)
+		if element.TableIndex != 0 {
+			content = append(content, 0)
+		}
+
+		content = appendU32(content, uint32(len(element.Functions)))
+		for j := 0; j < len(element.Functions); j++ {
+			content = appendU32(content, element.Functions[j])
+		}
+
+		return content
+	})
+
+	output = appendSection(output, code.SECTION_CODE, len(m.Code), func(i int, content []byte) []byte {
+		body := appendU32([]byte{}, uint32(len(m.Code[i].Locals)))
+		for j := 0; j < len(m.Code[i].Locals); j++ {
+			body = append(appendU32(body, m.Code[i].Locals[j].Count), m.Code[i].Locals[j].ValueType)
+		}
+
+		body = append(body, EncodeInstructions(m.Code[i].Instructions)...)
+		return appendVector(content, body)
+	})
+
+	output = appendSection(output, code.SECTION_DATA, len(m.Data), func(i int, content []byte) []byte {
+		segment := m.Data[i]
+
+		switch {
+		case segment.Passive:
+			content = append(content, 1)
+		case segment.MemoryIndex == 0:
+			content = append(content, 0)
+		default:
+			content = appendU32(append(content, 2), segment.MemoryIndex)
+		}
+
+		if !segment.Passive {
+			content = append(content, EncodeInstructions(segment.Offset)...)
+		}
+
+		return appendVector(content, segment.Bytes)
+	})
+
+	for i := 0; i < len(m.Customs); i++ {
+		output = append(output, code.SECTION_CUSTOM)
+		output = appendVector(output, append(appendName([]byte{}, m.Customs[i].Name), m.Customs[i].Bytes...))
+	}
+
+	return output
+}
+
+// Adds the section with a vector of the given number of elements, where every element is added by encodeElement
+func appendSection(output []byte, sectionId uint8, numElements int, encodeElement func(i int, content []byte) []byte) []byte {
+	if numElements == 0 {
+		return output
+	}
+
+	content := appendU32([]byte{}, uint32(numElements))
+	for i := 0; i < numElements; i++ {
+		content = encodeElement(i, content)
+	}
+
+	return appendVector(append(output, sectionId), content)
+}
+
+func EncodeInstructions(instructions []Instruction) []byte {
+	output := make([]byte, 0)
+	for i := 0; i < len(instructions); i++ {
+		output = append(output, EncodeInstruction(instructions[i])...)
+	}
+
+	return output
+}
+
+func EncodeInstruction(instruction Instruction) []byte {
+	output := []byte{instruction.Opcode}
+	if instruction.Opcode == code.MISC_PREFIX {
+		return appendU32(output, instruction.MiscOpcode)
+	}
+
+	immediates, hasImmediates := instructionImmediates[instruction.Opcode]
+	if !hasImmediates {
+		return output
+	}
+
+	switch immediates {
+	case immediateBlockType:
+		return appendBlockType(output, instruction.BlockType)
+	case immediateIndex:
+		return appendU32(output, instruction.Index)
+	case immediateCallIndirect:
+		return appendU32(appendU32(output, instruction.Index), instruction.Table)
+	case immediateBranchTable:
+		//The default label is the last label, and is not counted in the number of labels
+		output = appendU32(output, uint32(len(instruction.Labels)-1))
+		for i := 0; i < len(instruction.Labels); i++ {
+			output = appendU32(output, instruction.Labels[i])
+		}
+
+		return output
+	case immediateMemory:
+		return appendU32(appendU32(output, instruction.Align), instruction.MemoryOffset)
+	case immediateMemoryIndex:
+		return append(output, uint8(instruction.Index))
+	case immediateI32:
+		return append(output, leb128.Int32ToLEB128(instruction.I32)...)
+	case immediateI64:
+		return append(output, leb128.Int64ToLEB128(instruction.I64)...)
+	case immediateF32:
+		bits := make([]byte, 4)
+		binary.LittleEndian.PutUint32(bits, math.Float32bits(instruction.F32))
+		return append(output, bits...)
+	}
+
+	bits := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bits, math.Float64bits(instruction.F64))
+	return append(output, bits...)
+}
+
+// Block types with a type index are signed numbers, so they can not be confused with the value types
+func appendBlockType(output []byte, blockType BlockType) []byte {
+	if blockType.TypeIndex >= 0 {
+		return append(output, leb128.Int64ToLEB128(blockType.TypeIndex)...)
+	}
+
+	if len(blockType.Results) == 0 {
+		return append(output, code.EMPTY)
+	}
+
+	return append(output, blockType.Results[0])
+}
+
+func appendU32(output []byte, value uint32) []byte {
+	return append(output, leb128.Int32ToULEB128(int32(value))...)
+}
+
+func appendVector(output []byte, content []byte) []byte {
+	return append(appendU32(output, uint32(len(content))), content...)
+}
+
+func appendName(output []byte, name string) []byte {
+	return appendVector(output, []byte(name))
+}
+
+func appendValueTypes(output []byte, valueTypes []uint8) []byte {
+	return appendVector(output, valueTypes)
+}
+
+func appendLimits(output []byte, limits Limits) []byte {
+	if !limits.HasMax {
+		return appendU32(append(output, code.LIMIT_MIN), limits.Min)
+	}
+
+	return appendU32(appendU32(append(output, code.LIMIT_MIN_MAX), limits.Min), limits.Max)
+}
+
+func appendTable(output []byte, table Table) []byte {
+	return appendLimits(append(output, table.ElementType), table.Limits)
+}
+
+func appendGlobalType(output []byte, globalType GlobalType) []byte {
+	if globalType.Mutable {
+		return append(output, globalType.ValueType, code.MUTABLE)
+	}
+
+	return append(output, globalType.ValueType, code.IMMUTABLE)
+}
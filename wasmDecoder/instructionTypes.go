@@ -0,0 +1,76 @@
+package wasmDecoder
+
+import "compiler/wasmCompiler/code"
+
+// The types of the numeric instructions. They only have fixed types, so the validator checks them with this table
+var NumericInstructionTypes = newNumericInstructionTypes()
+
+// The types of the instructions with MISC_PREFIX, by their sub opcode
+var MiscInstructionTypes = map[uint8]FunctionType{
+	code.I32_TRUNC_SAT_F32_S: convert(code.F32, code.I32),
+	code.I32_TRUNC_SAT_F32_U: convert(code.F32, code.I32),
+	code.I32_TRUNC_SAT_F64_S: convert(code.F64, code.I32),
+	code.I32_TRUNC_SAT_F64_U: convert(code.F64, code.I32),
+	code.I64_TRUNC_SAT_F32_S: convert(code.F32, code.I64),
+	code.I64_TRUNC_SAT_F32_U: convert(code.F32, code.I64),
+	code.I64_TRUNC_SAT_F64_S: convert(code.F64, code.I64),
+	code.I64_TRUNC_SAT_F64_U: convert(code.F64, code.I64),
+}
+
+func newNumericInstructionTypes() map[uint8]FunctionType {
+	instructions := map[uint8]FunctionType{
+		code.I32_EQZ:             convert(code.I32, code.I32),
+		code.I64_EQZ:             convert(code.I64, code.I32),
+		code.I32_WRAP_I64:        convert(code.I64, code.I32),
+		code.I32_TRUNC_S_F32:     convert(code.F32, code.I32),
+		code.I32_TRUNC_U_F32:     convert(code.F32, code.I32),
+		code.I32_TRUNC_S_F64:     convert(code.F64, code.I32),
+		code.I32_TRUNC_U_F64:     convert(code.F64, code.I32),
+		code.I64_EXTEND_S_I32:    convert(code.I32, code.I64),
+		code.I64_EXTEND_U_I32:    convert(code.I32, code.I64),
+		code.I64_TRUNC_S_F32:     convert(code.F32, code.I64),
+		code.I64_TRUNC_U_F32:     convert(code.F32, code.I64),
+		code.I64_TRUNC_S_F64:     convert(code.F64, code.I64),
+		code.I64_TRUNC_U_F64:     convert(code.F64, code.I64),
+		code.F32_CONVERT_S_I32:   convert(code.I32, code.F32),
+		code.F32_CONVERT_U_I32:   convert(code.I32, code.F32),
+		code.F32_CONVERT_S_I64:   convert(code.I64, code.F32),
+		code.F32_CONVERT_U_I64:   convert(code.I64, code.F32),
+		code.F32_DEMOTE_F64:      convert(code.F64, code.F32),
+		code.F64_CONVERT_S_I32:   convert(code.I32, code.F64),
+		code.F64_CONVERT_U_I32:   convert(code.I32, code.F64),
+		code.F64_CONVERT_S_I64:   convert(code.I64, code.F64),
+		code.F64_CONVERT_U_I64:   convert(code.I64, code.F64),
+		code.F64_PROMOTE_F32:     convert(code.F32, code.F64),
+		code.I32_REINTERPRET_F32: convert(code.F32, code.I32),
+		code.I64_REINTERPRET_F64: convert(code.F64, code.I64),
+		code.F32_REINTERPRET_I32: convert(code.I32, code.F32),
+		code.F64_REINTERPRET_I64: convert(code.I64, code.F64),
+	}
+
+	//The other numeric instructions are in ranges of opcodes with the same type
+	addRange := func(first, last uint8, parameters []uint8, result uint8) {
+		for opcode := first; opcode <= last; opcode++ {
+			instructions[opcode] = FunctionType{Parameters: parameters, Results: []uint8{result}}
+		}
+	}
+
+	addRange(code.I32_EQ, code.I32_GE_U, []uint8{code.I32, code.I32}, code.I32)
+	addRange(code.I64_EQ, code.I64_GE_U, []uint8{code.I64, code.I64}, code.I32)
+	addRange(code.F32_EQ, code.F32_GE, []uint8{code.F32, code.F32}, code.I32)
+	addRange(code.F64_EQ, code.F64_GE, []uint8{code.F64, code.F64}, code.I32)
+	addRange(code.I32_CLZ, code.I32_POPCNT, []uint8{code.I32}, code.I32)
+	addRange(code.I32_ADD, code.I32_ROTR, []uint8{code.I32, code.I32}, code.I32)
+	addRange(code.I64_CLZ, code.I64_POPCNT, []uint8{code.I64}, code.I64)
+	addRange(code.I64_ADD, code.I64_ROTR, []uint8{code.I64, code.I64}, code.I64)
+	addRange(code.F32_ABS, code.F32_SQRT, []uint8{code.F32}, code.F32)
+	addRange(code.F32_ADD, code.F32_COPYSIGN, []uint8{code.F32, code.F32}, code.F32)
+	addRange(code.F64_ABS, code.F64_SQRT, []uint8{code.F64}, code.F64)
+	addRange(code.F64_ADD, code.F64_COPYSIGN, []uint8{code.F64, code.F64}, code.F64)
+
+	return instructions
+}
+
+func convert(from uint8, to uint8) FunctionType {
+	return FunctionType{Parameters: []uint8{from}, Results: []uint8{to}}
+}
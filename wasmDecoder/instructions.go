@@ -0,0 +1,229 @@
+package wasmDecoder
+
+import (
+	"compiler/wasmCompiler/code"
+	"fmt"
+	"math"
+)
+
+// An instruction with its immediates. Offset is the position of the opcode in the bytes it was read from and Length
+// is the number of bytes of the instruction with its immediates
+type Instruction struct {
+	Offset     int
+	Length     int
+	Opcode     uint8
+	MiscOpcode uint32 //The sub opcode of instructions with MISC_PREFIX
+
+	BlockType BlockType
+	Index     uint32   //Function, type, local, global or label index, or the reserved memory byte of memory.size and memory.grow
+	Table     uint32   //Table index of call_indirect
+	Labels    []uint32 //Labels of br_table, with the default label last
+
+	Align        uint32
+	MemoryOffset uint32
+
+	I32 int32
+	I64 int64
+	F32 float32
+	F64 float64
+}
+
+// Block types are empty, one value type, or the index of a function type when TypeIndex is not -1
+type BlockType struct {
+	Results   []uint8
+	TypeIndex int64
+}
+
+// The kinds of immediates each instruction has. Instructions without an entry have no immediates
+const (
+	immediateBlockType uint8 = iota
+	immediateIndex
+	immediateCallIndirect
+	immediateBranchTable
+	immediateMemory
+	immediateMemoryIndex
+	immediateI32
+	immediateI64
+	immediateF32
+	immediateF64
+)
+
+var instructionImmediates = map[uint8]uint8{
+	code.BLOCK:         immediateBlockType,
+	code.LOOP:          immediateBlockType,
+	code.IF:            immediateBlockType,
+	code.BR:            immediateIndex,
+	code.BR_IF:         immediateIndex,
+	code.BR_TABLE:      immediateBranchTable,
+	code.CALL:          immediateIndex,
+	code.CALL_INDIRECT: immediateCallIndirect,
+	code.LOCAL_GET:     immediateIndex,
+	code.LOCAL_SET:     immediateIndex,
+	code.LOCAL_TEE:     immediateIndex,
+	code.GLOBAL_GET:    immediateIndex,
+	code.GLOBAL_SET:    immediateIndex,
+	code.MEMORY_SIZE:   immediateMemoryIndex,
+	code.MEMORY_GROW:   immediateMemoryIndex,
+	code.I32_CONST:     immediateI32,
+	code.I64_CONST:     immediateI64,
+	code.F32_CONST:     immediateF32,
+	code.F64_CONST:     immediateF64,
+}
+
+func init() {
+	for opcode := code.I32_LOAD; opcode <= code.I64_STORE32; opcode++ {
+		instructionImmediates[opcode] = immediateMemory
+	}
+}
+
+// Reads instructions until the end instruction that ends the expression, which is included in the instructions
+func ReadExpression(r *Reader) ([]Instruction, error) {
+	instructions := make([]Instruction, 0)
+	depth := 0
+
+	for {
+		instruction, err := ReadInstruction(r)
+		if err != nil {
+			return instructions, err
+		}
+
+		instructions = append(instructions, instruction)
+
+		switch instruction.Opcode {
+		case code.BLOCK, code.LOOP, code.IF:
+			depth++
+		case code.END:
+			if depth == 0 {
+				return instructions, nil
+			}
+
+			depth--
+		}
+	}
+}
+
+func ReadInstruction(r *Reader) (Instruction, error) {
+	instruction := Instruction{Offset: r.Position}
+
+	opcode, err := r.ReadByte()
+	if err != nil {
+		return Instruction{}, fmt.Errorf("expected instruction: %s", err.Error())
+	}
+
+	instruction.Opcode = opcode
+
+	if opcode == code.MISC_PREFIX {
+		instruction.MiscOpcode, err = r.ReadU32()
+		if err != nil {
+			return Instruction{}, err
+		}
+
+		if instruction.MiscOpcode > math.MaxUint8 || code.MiscInstructionNames[uint8(instruction.MiscOpcode)] == "" {
+			return Instruction{}, fmt.Errorf("unknown instruction 0x%x %v at byte %v", opcode, instruction.MiscOpcode, instruction.Offset)
+		}
+
+		instruction.Length = r.Position - instruction.Offset
+		return instruction, nil
+	}
+
+	if _, isInstruction := code.InstructionNames[opcode]; !isInstruction {
+		return Instruction{}, fmt.Errorf("unknown instruction 0x%x at byte %v", opcode, instruction.Offset)
+	}
+
+	immediates, hasImmediates := instructionImmediates[opcode]
+	if hasImmediates {
+		err = readImmediates(r, immediates, &instruction)
+		if err != nil {
+			return Instruction{}, fmt.Errorf("%s at byte %v: %s", code.InstructionNames[opcode], instruction.Offset, err.Error())
+		}
+	}
+
+	instruction.Length = r.Position - instruction.Offset
+	return instruction, nil
+}
+
+func readImmediates(r *Reader, immediates uint8, instruction *Instruction) error {
+	var err error
+
+	switch immediates {
+	case immediateBlockType:
+		instruction.BlockType, err = readBlockType(r)
+	case immediateIndex:
+		instruction.Index, err = r.ReadU32()
+	case immediateCallIndirect:
+		instruction.Index, err = r.ReadU32()
+		if err != nil {
+			return err
+		}
+
+		instruction.Table, err = r.ReadU32()
+	case immediateBranchTable:
+		numLabels, err := r.ReadVectorLength()
+		if err != nil {
+			return err
+		}
+
+		//The default label is after the other labels
+		for i := 0; i <= numLabels; i++ {
+			label, err := r.ReadU32()
+			if err != nil {
+				return err
+			}
+
+			instruction.Labels = append(instruction.Labels, label)
+		}
+	case immediateMemory:
+		instruction.Align, err = r.ReadU32()
+		if err != nil {
+			return err
+		}
+
+		instruction.MemoryOffset, err = r.ReadU32()
+	case immediateMemoryIndex:
+		memoryIndex, err := r.ReadByte()
+		instruction.Index = uint32(memoryIndex)
+		return err
+	case immediateI32:
+		instruction.I32, err = r.ReadS32()
+	case immediateI64:
+		instruction.I64, err = r.ReadS64()
+	case immediateF32:
+		bits, err := r.ReadU32LittleEndian()
+		instruction.F32 = math.Float32frombits(bits)
+		return err
+	case immediateF64:
+		bits, err := r.ReadU64LittleEndian()
+		instruction.F64 = math.Float64frombits(bits)
+		return err
+	}
+
+	return err
+}
+
+func readBlockType(r *Reader) (BlockType, error) {
+	if r.IsAtEnd() {
+		return BlockType{}, fmt.Errorf("expected block type")
+	}
+
+	first := r.Bytes[r.Position]
+	if first == code.EMPTY {
+		r.Position++
+		return BlockType{Results: []uint8{}, TypeIndex: -1}, nil
+	}
+
+	if _, isValueType := ValueTypeNames[first]; isValueType {
+		r.Position++
+		return BlockType{Results: []uint8{first}, TypeIndex: -1}, nil
+	}
+
+	typeIndex, err := r.ReadS33()
+	if err != nil {
+		return BlockType{}, err
+	}
+
+	if typeIndex < 0 {
+		return BlockType{}, fmt.Errorf("block type %v is not a value type or a type index", typeIndex)
+	}
+
+	return BlockType{TypeIndex: typeIndex}, nil
+}
@@ -0,0 +1,191 @@
+package wasmDecoder
+
+import (
+	"compiler/leb128"
+	"compiler/wasmCompiler/code"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Reads the values in a part of a module. The position is relative to the start of the bytes
+type Reader struct {
+	Bytes    []byte
+	Position int
+}
+
+func NewReader(bytes []byte) *Reader {
+	return &Reader{Bytes: bytes, Position: 0}
+}
+
+func (r *Reader) IsAtEnd() bool {
+	return r.Position >= len(r.Bytes)
+}
+
+func (r *Reader) ReadByte() (byte, error) {
+	if r.IsAtEnd() {
+		return 0, fmt.Errorf("unexpected end at byte %v", r.Position)
+	}
+
+	r.Position++
+	return r.Bytes[r.Position-1], nil
+}
+
+func (r *Reader) ReadBytes(numBytes int) ([]byte, error) {
+	if numBytes < 0 || r.Position+numBytes > len(r.Bytes) {
+		return []byte{}, fmt.Errorf("unexpected end at byte %v, expected %v more bytes", r.Position, numBytes)
+	}
+
+	r.Position += numBytes
+	return r.Bytes[r.Position-numBytes : r.Position], nil
+}
+
+func (r *Reader) ReadU32() (uint32, error) {
+	value, numBytes, err := leb128.ULEB128ToUint32(r.Bytes[r.Position:])
+	if err != nil {
+		return 0, fmt.Errorf("%s at byte %v", err.Error(), r.Position)
+	}
+
+	r.Position += numBytes
+	return value, nil
+}
+
+func (r *Reader) ReadS32() (int32, error) {
+	value, numBytes, err := leb128.SLEB128ToInt32(r.Bytes[r.Position:])
+	if err != nil {
+		return 0, fmt.Errorf("%s at byte %v", err.Error(), r.Position)
+	}
+
+	r.Position += numBytes
+	return value, nil
+}
+
+func (r *Reader) ReadS33() (int64, error) {
+	value, numBytes, err := leb128.SLEB128ToInt33(r.Bytes[r.Position:])
+	if err != nil {
+		return 0, fmt.Errorf("%s at byte %v", err.Error(), r.Position)
+	}
+
+	r.Position += numBytes
+	return value, nil
+}
+
+func (r *Reader) ReadS64() (int64, error) {
+	value, numBytes, err := leb128.SLEB128ToInt64(r.Bytes[r.Position:])
+	if err != nil {
+		return 0, fmt.Errorf("%s at byte %v", err.Error(), r.Position)
+	}
+
+	r.Position += numBytes
+	return value, nil
+}
+
+func (r *Reader) ReadU32LittleEndian() (uint32, error) {
+	bytes, err := r.ReadBytes(4)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(bytes), nil
+}
+
+func (r *Reader) ReadU64LittleEndian() (uint64, error) {
+	bytes, err := r.ReadBytes(8)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint64(bytes), nil
+}
+
+// Reads the length of a vector and checks that every element could fit in the remaining bytes
+func (r *Reader) ReadVectorLength() (int, error) {
+	length, err := r.ReadU32()
+	if err != nil {
+		return 0, err
+	}
+
+	if int(length) > len(r.Bytes)-r.Position {
+		return 0, fmt.Errorf("vector length %v at byte %v is longer than the remaining bytes", length, r.Position)
+	}
+
+	return int(length), nil
+}
+
+func (r *Reader) ReadName() (string, error) {
+	length, err := r.ReadVectorLength()
+	if err != nil {
+		return "", err
+	}
+
+	bytes, err := r.ReadBytes(length)
+	if err != nil {
+		return "", err
+	}
+
+	if !utf8.Valid(bytes) {
+		return "", fmt.Errorf("name at byte %v is not valid UTF-8", r.Position-length)
+	}
+
+	return string(bytes), nil
+}
+
+func (r *Reader) ReadValueType() (uint8, error) {
+	valueType, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, isValueType := ValueTypeNames[valueType]; !isValueType {
+		return 0, fmt.Errorf("invalid value type 0x%x at byte %v", valueType, r.Position-1)
+	}
+
+	return valueType, nil
+}
+
+func (r *Reader) ReadValueTypes() ([]uint8, error) {
+	length, err := r.ReadVectorLength()
+	if err != nil {
+		return []uint8{}, err
+	}
+
+	valueTypes := make([]uint8, 0)
+	for i := 0; i < length; i++ {
+		valueType, err := r.ReadValueType()
+		if err != nil {
+			return []uint8{}, err
+		}
+
+		valueTypes = append(valueTypes, valueType)
+	}
+
+	return valueTypes, nil
+}
+
+// Limits are the size of a table or a memory
+func (r *Reader) ReadLimits() (Limits, error) {
+	flag, err := r.ReadByte()
+	if err != nil {
+		return Limits{}, err
+	}
+
+	if flag != code.LIMIT_MIN && flag != code.LIMIT_MIN_MAX {
+		return Limits{}, fmt.Errorf("invalid limits flag %v at byte %v", flag, r.Position-1)
+	}
+
+	min, err := r.ReadU32()
+	if err != nil {
+		return Limits{}, err
+	}
+
+	if flag == code.LIMIT_MIN {
+		return Limits{Min: min}, nil
+	}
+
+	max, err := r.ReadU32()
+	if err != nil {
+		return Limits{}, err
+	}
+
+	return Limits{Min: min, Max: max, HasMax: true}, nil
+}
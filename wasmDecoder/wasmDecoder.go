@@ -0,0 +1,654 @@
+package wasmDecoder
+
+import (
+	"bytes"
+	"compiler/wasmCompiler/code"
+	"fmt"
+	"os"
+)
+
+//The decoder reads a wasm binary module into a typed module with every section and the instructions of every
+//function body. It checks the structure of the module, like the section order and the section sizes, but it does not
+//validate the module. The wasmValidator package validates decoded modules. The encoder writes modules in the binary
+//format, which is how the compiler writes the modules it builds
+
+type Module struct {
+	Types     []FunctionType
+	Imports   []Import
+	Functions []uint32 //The type indexes of the functions defined in the module, after the imported functions
+	Tables    []Table
+	Memories  []Limits
+	Globals   []Global
+	Exports   []Export
+	HasStart  bool
+	Start     uint32
+	Elements  []Element
+	Code      []FunctionBody
+	Data      []DataSegment
+	Customs   []CustomSection
+	Sections  []Section //The sections in the order they are in the module
+}
+
+// Offset is the position of the section id in the module and ContentOffset is the position of the content after the
+// section size
+type Section struct {
+	Id            uint8
+	Offset        int
+	ContentOffset int
+	Size          int
+}
+
+type FunctionType struct {
+	Parameters []uint8
+	Results    []uint8
+}
+
+// Imports have a type index for functions, a table, a memory or a global type depending on the kind
+type Import struct {
+	Module    string
+	Name      string
+	Kind      uint8
+	TypeIndex uint32
+	Table     Table
+	Memory    Limits
+	Global    GlobalType
+}
+
+type Limits struct {
+	Min    uint32
+	Max    uint32
+	HasMax bool
+}
+
+type Table struct {
+	ElementType uint8
+	Limits      Limits
+}
+
+type GlobalType struct {
+	ValueType uint8
+	Mutable   bool
+}
+
+// The init expression is a constant expression with the end instruction
+type Global struct {
+	Type GlobalType
+	Init []Instruction
+}
+
+type Export struct {
+	Name  string
+	Kind  uint8
+	Index uint32
+}
+
+// Active element segments with function indexes, which is the only kind of element segment in wasm 1.0
+type Element struct {
+	TableIndex uint32
+	Offset     []Instruction
+	Functions  []uint32
+}
+
+// Offset is the position of the body in the module, after the size of the body. The offsets of the instructions are
+// positions in the bytes of the body, and the instructions include the end of the function
+type FunctionBody struct {
+	Offset       int
+	Bytes        []byte
+	Locals       []Locals
+	Instructions []Instruction
+}
+
+type Locals struct {
+	Count     uint32
+	ValueType uint8
+}
+
+type DataSegment struct {
+	Passive     bool
+	MemoryIndex uint32
+	Offset      []Instruction
+	Bytes       []byte
+}
+
+type CustomSection struct {
+	Name  string
+	Bytes []byte
+}
+
+// The order sections must have in a module. Custom sections can be anywhere
+var sectionOrder = map[uint8]int{
+	code.SECTION_TYPE:     1,
+	code.SECTION_IMPORT:   2,
+	code.SECTION_FUNCTION: 3,
+	code.SECTION_TABLE:    4,
+	code.SECTION_MEMORY:   5,
+	code.SECTION_GLOBAL:   6,
+	code.SECTION_EXPORT:   7,
+	code.SECTION_START:    8,
+	code.SECTION_ELEMENT:  9,
+	code.SECTION_CODE:     10,
+	code.SECTION_DATA:     11,
+}
+
+var SectionNames = map[uint8]string{
+	code.SECTION_CUSTOM:   "custom",
+	code.SECTION_TYPE:     "type",
+	code.SECTION_IMPORT:   "import",
+	code.SECTION_FUNCTION: "function",
+	code.SECTION_TABLE:    "table",
+	code.SECTION_MEMORY:   "memory",
+	code.SECTION_GLOBAL:   "global",
+	code.SECTION_EXPORT:   "export",
+	code.SECTION_START:    "start",
+	code.SECTION_ELEMENT:  "element",
+	code.SECTION_CODE:     "code",
+	code.SECTION_DATA:     "data",
+}
+
+var ValueTypeNames = map[uint8]string{
+	code.I32: "i32",
+	code.I64: "i64",
+	code.F32: "f32",
+	code.F64: "f64",
+}
+
+var ExportKindNames = map[uint8]string{
+	code.DESC_FUNCTION: "func",
+	code.DESC_TABLE:    "table",
+	code.DESC_MEMORY:   "memory",
+	code.DESC_GLOBAL:   "global",
+}
+
+func DecodeFile(fileName string) (*Module, error) {
+	fileContent, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	module, err := Decode(fileContent)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding %s: %s", fileName, err.Error())
+	}
+
+	return module, nil
+}
+
+func Decode(moduleBytes []byte) (*Module, error) {
+	m := &Module{}
+	r := NewReader(moduleBytes)
+
+	header, err := r.ReadBytes(len(code.MagicModuleHeader) + len(code.ModuleVersion))
+	if err != nil {
+		return nil, fmt.Errorf("module is too short for the header")
+	}
+
+	if !bytes.Equal(header[:len(code.MagicModuleHeader)], code.MagicModuleHeader) {
+		return nil, fmt.Errorf("module does not start with the magic header")
+	}
+
+	if !bytes.Equal(header[len(code.MagicModuleHeader):], code.ModuleVersion) {
+		return nil, fmt.Errorf("module version is not %v", code.ModuleVersion)
+	}
+
+	lastSectionOrder := 0
+	hasCodeSection := false
+	for !r.IsAtEnd() {
+		sectionStart := r.Position
+		sectionId, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		sectionName, isSection := SectionNames[sectionId]
+		if !isSection {
+			return nil, fmt.Errorf("unknown section id %v at byte %v", sectionId, sectionStart)
+		}
+
+		sectionSize, err := r.ReadVectorLength()
+		if err != nil {
+			return nil, fmt.Errorf("size of %s section: %s", sectionName, err.Error())
+		}
+
+		contentStart := r.Position
+		content, _ := r.ReadBytes(sectionSize)
+		m.Sections = append(m.Sections, Section{Id: sectionId, Offset: sectionStart, ContentOffset: contentStart, Size: sectionSize})
+
+		if sectionId != code.SECTION_CUSTOM {
+			if sectionOrder[sectionId] <= lastSectionOrder {
+				return nil, fmt.Errorf("%s section at byte %v is out of order or appears more than once", sectionName, sectionStart)
+			}
+
+			lastSectionOrder = sectionOrder[sectionId]
+		}
+
+		hasCodeSection = hasCodeSection || sectionId == code.SECTION_CODE
+
+		err = m.decodeSection(sectionId, NewReader(content), contentStart)
+		if err != nil {
+			return nil, fmt.Errorf("%s section at byte %v: %s", sectionName, sectionStart, err.Error())
+		}
+	}
+
+	if !hasCodeSection && len(m.Functions) != 0 {
+		return nil, fmt.Errorf("the function section has %v functions but there is no code section", len(m.Functions))
+	}
+
+	return m, nil
+}
+
+func (m *Module) decodeSection(sectionId uint8, r *Reader, contentStart int) error {
+	var err error
+
+	switch sectionId {
+	case code.SECTION_CUSTOM:
+		err = m.decodeCustomSection(r)
+	case code.SECTION_TYPE:
+		err = m.decodeTypeSection(r)
+	case code.SECTION_IMPORT:
+		err = m.decodeImportSection(r)
+	case code.SECTION_FUNCTION:
+		err = decodeVector(r, func(r *Reader) error {
+			typeIndex, err := r.ReadU32()
+			m.Functions = append(m.Functions, typeIndex)
+			return err
+		})
+	case code.SECTION_TABLE:
+		err = decodeVector(r, func(r *Reader) error {
+			table, err := readTable(r)
+			m.Tables = append(m.Tables, table)
+			return err
+		})
+	case code.SECTION_MEMORY:
+		err = decodeVector(r, func(r *Reader) error {
+			limits, err := r.ReadLimits()
+			m.Memories = append(m.Memories, limits)
+			return err
+		})
+	case code.SECTION_GLOBAL:
+		err = m.decodeGlobalSection(r)
+	case code.SECTION_EXPORT:
+		err = m.decodeExportSection(r)
+	case code.SECTION_START:
+		m.HasStart = true
+		m.Start, err = r.ReadU32()
+	case code.SECTION_ELEMENT:
+		err = m.decodeElementSection(r)
+	case code.SECTION_CODE:
+		err = m.decodeCodeSection(r, contentStart)
+	case code.SECTION_DATA:
+		err = m.decodeDataSection(r)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !r.IsAtEnd() {
+		return fmt.Errorf("section size is %v bytes but the content ends at byte %v", len(r.Bytes), r.Position)
+	}
+
+	return nil
+}
+
+// Reads the length of a vector and then every element with the given function
+func decodeVector(r *Reader, decodeElement func(r *Reader) error) error {
+	length, err := r.ReadVectorLength()
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < length; i++ {
+		err = decodeElement(r)
+		if err != nil {
+			return fmt.Errorf("element %v: %s", i, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (m *Module) decodeCustomSection(r *Reader) error {
+	name, err := r.ReadName()
+	if err != nil {
+		return err
+	}
+
+	content, _ := r.ReadBytes(len(r.Bytes) - r.Position)
+	m.Customs = append(m.Customs, CustomSection{Name: name, Bytes: content})
+	return nil
+}
+
+func (m *Module) decodeTypeSection(r *Reader) error {
+	return decodeVector(r, func(r *Reader) error {
+		form, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		if form != code.FUNC {
+			return fmt.Errorf("type does not start with 0x%x", code.FUNC)
+		}
+
+		parameters, err := r.ReadValueTypes()
+		if err != nil {
+			return err
+		}
+
+		results, err := r.ReadValueTypes()
+		if err != nil {
+			return err
+		}
+
+		m.Types = append(m.Types, FunctionType{Parameters: parameters, Results: results})
+		return nil
+	})
+}
+
+func (m *Module) decodeImportSection(r *Reader) error {
+	return decodeVector(r, func(r *Reader) error {
+		moduleName, err := r.ReadName()
+		if err != nil {
+			return err
+		}
+
+		name, err := r.ReadName()
+		if err != nil {
+			return err
+		}
+
+		kind, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		imported := Import{Module: moduleName, Name: name, Kind: kind}
+		switch kind {
+		case code.DESC_FUNCTION:
+			imported.TypeIndex, err = r.ReadU32()
+		case code.DESC_TABLE:
+			imported.Table, err = readTable(r)
+		case code.DESC_MEMORY:
+			imported.Memory, err = r.ReadLimits()
+		case code.DESC_GLOBAL:
+			imported.Global, err = readGlobalType(r)
+		default:
+			return fmt.Errorf("import %s.%s has invalid kind %v", moduleName, name, kind)
+		}
+
+		m.Imports = append(m.Imports, imported)
+		return err
+	})
+}
+
+func readTable(r *Reader) (Table, error) {
+	elementType, err := r.ReadByte()
+	if err != nil {
+		return Table{}, err
+	}
+
+	limits, err := r.ReadLimits()
+	return Table{ElementType: elementType, Limits: limits}, err
+}
+
+func readGlobalType(r *Reader) (GlobalType, error) {
+	valueType, err := r.ReadValueType()
+	if err != nil {
+		return GlobalType{}, err
+	}
+
+	mutability, err := r.ReadByte()
+	if err != nil {
+		return GlobalType{}, err
+	}
+
+	if mutability != code.IMMUTABLE && mutability != code.MUTABLE {
+		return GlobalType{}, fmt.Errorf("invalid global mutability %v", mutability)
+	}
+
+	return GlobalType{ValueType: valueType, Mutable: mutability == code.MUTABLE}, nil
+}
+
+func (m *Module) decodeGlobalSection(r *Reader) error {
+	return decodeVector(r, func(r *Reader) error {
+		globalType, err := readGlobalType(r)
+		if err != nil {
+			return err
+		}
+
+		init, err := ReadExpression(r)
+		if err != nil {
+			return fmt.Errorf("init expression: %s", err.Error())
+		}
+
+		m.Globals = append(m.Globals, Global{Type: globalType, Init: init})
+		return nil
+	})
+}
+
+func (m *Module) decodeExportSection(r *Reader) error {
+	return decodeVector(r, func(r *Reader) error {
+		name, err := r.ReadName()
+		if err != nil {
+			return err
+		}
+
+		kind, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		if _, isKind := ExportKindNames[kind]; !isKind {
+			return fmt.Errorf("export %s has invalid kind %v", name, kind)
+		}
+
+		index, err := r.ReadU32()
+		m.Exports = append(m.Exports, Export{Name: name, Kind: kind, Index: index})
+		return err
+	})
+}
+
+func (m *Module) decodeElementSection(r *Reader) error {
+	return decodeVector(r, func(r *Reader) error {
+		flags, err := r.ReadU32()
+		if err != nil {
+			return err
+		}
+
+		//Flags 0 is an active segment for table 0 and flags 2 is an active segment for the given table, with a function
+		//index element kind. The other flags are from the reference types proposal
+		element := Element{}
+		switch flags {
+		case 0:
+		case 2:
+			element.TableIndex, err = r.ReadU32()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("element segment flags %v are not supported", flags)
+		}
+
+		element.Offset, err = ReadExpression(r)
+		if err != nil {
+			return fmt.Errorf("offset: %s", err.Error())
+		}
+
+		if flags == 2 {
+			elementKind, err := r.ReadByte()
+			if err != nil {
+				return err
+			}
+
+			if elementKind != 0 {
+				return fmt.Errorf("element kind %v is not supported", elementKind)
+			}
+		}
+
+		err = decodeVector(r, func(r *Reader) error {
+			functionIndex, err := r.ReadU32()
+			element.Functions = append(element.Functions, functionIndex)
+			return err
+		})
+
+		m.Elements = append(m.Elements, element)
+		return err
+	})
+}
+
+func (m *Module) decodeCodeSection(r *Reader, contentStart int) error {
+	err := decodeVector(r, func(r *Reader) error {
+		bodySize, err := r.ReadVectorLength()
+		if err != nil {
+			return err
+		}
+
+		bodyStart := r.Position
+		body, _ := r.ReadBytes(bodySize)
+
+		functionBody, err := decodeFunctionBody(body)
+		functionBody.Offset = contentStart + bodyStart
+		m.Code = append(m.Code, functionBody)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(m.Code) != len(m.Functions) {
+		return fmt.Errorf("there are %v function bodies, but the function section has %v functions", len(m.Code), len(m.Functions))
+	}
+
+	return nil
+}
+
+func decodeFunctionBody(body []byte) (FunctionBody, error) {
+	r := NewReader(body)
+	functionBody := FunctionBody{Bytes: body}
+
+	err := decodeVector(r, func(r *Reader) error {
+		count, err := r.ReadU32()
+		if err != nil {
+			return err
+		}
+
+		valueType, err := r.ReadValueType()
+		functionBody.Locals = append(functionBody.Locals, Locals{Count: count, ValueType: valueType})
+		return err
+	})
+
+	if err != nil {
+		return functionBody, fmt.Errorf("locals: %s", err.Error())
+	}
+
+	functionBody.Instructions, err = ReadExpression(r)
+	if err != nil {
+		return functionBody, err
+	}
+
+	if !r.IsAtEnd() {
+		return functionBody, fmt.Errorf("function ends at byte %v, before the end of the function body", r.Position)
+	}
+
+	return functionBody, nil
+}
+
+func (m *Module) decodeDataSection(r *Reader) error {
+	return decodeVector(r, func(r *Reader) error {
+		flags, err := r.ReadU32()
+		if err != nil {
+			return err
+		}
+
+		segment := DataSegment{}
+		switch flags {
+		case 0:
+		case 1:
+			segment.Passive = true
+		case 2:
+			segment.MemoryIndex, err = r.ReadU32()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("data segment flags %v are not supported", flags)
+		}
+
+		if !segment.Passive {
+			segment.Offset, err = ReadExpression(r)
+			if err != nil {
+				return fmt.Errorf("offset: %s", err.Error())
+			}
+		}
+
+		length, err := r.ReadVectorLength()
+		if err != nil {
+			return err
+		}
+
+		segment.Bytes, err = r.ReadBytes(length)
+		m.Data = append(m.Data, segment)
+		return err
+	})
+}
+
+func (m *Module) NumImportedFunctions() int {
+	return m.numImports(code.DESC_FUNCTION)
+}
+
+func (m *Module) NumImportedGlobals() int {
+	return m.numImports(code.DESC_GLOBAL)
+}
+
+func (m *Module) numImports(kind uint8) int {
+	numImports := 0
+	for i := 0; i < len(m.Imports); i++ {
+		if m.Imports[i].Kind == kind {
+			numImports++
+		}
+	}
+
+	return numImports
+}
+
+// Gives the type index of the function with the index, which counts the imported functions first
+func (m *Module) FunctionTypeIndex(functionIndex int) (uint32, error) {
+	for i := 0; i < len(m.Imports); i++ {
+		if m.Imports[i].Kind != code.DESC_FUNCTION {
+			continue
+		}
+
+		if functionIndex == 0 {
+			return m.Imports[i].TypeIndex, nil
+		}
+
+		functionIndex--
+	}
+
+	if functionIndex < 0 || functionIndex >= len(m.Functions) {
+		return 0, fmt.Errorf("function index out of range")
+	}
+
+	return m.Functions[functionIndex], nil
+}
+
+// Gives the type of the global with the index, which counts the imported globals first
+func (m *Module) GlobalType(globalIndex int) (GlobalType, error) {
+	for i := 0; i < len(m.Imports); i++ {
+		if m.Imports[i].Kind != code.DESC_GLOBAL {
+			continue
+		}
+
+		if globalIndex == 0 {
+			return m.Imports[i].Global, nil
+		}
+
+		globalIndex--
+	}
+
+	if globalIndex < 0 || globalIndex >= len(m.Globals) {
+		return GlobalType{}, fmt.Errorf("global index out of range")
+	}
+
+	return m.Globals[globalIndex].Type, nil
+}
@@ -0,0 +1,213 @@
+package wasmDecoder
+
+import (
+	"compiler/leb128"
+	"compiler/wasmCompiler/code"
+	"strings"
+	"testing"
+)
+
+func section(id uint8, content ...byte) []byte {
+	return append(append([]byte{id}, leb128.Int32ToULEB128(int32(len(content)))...), content...)
+}
+
+func module(sections ...[]byte) []byte {
+	bytes := append(append([]byte{}, code.MagicModuleHeader...), code.ModuleVersion...)
+	for _, section := range sections {
+		bytes = append(bytes, section...)
+	}
+
+	return bytes
+}
+
+// A type section with the type () -> (i32)
+var typeSection = section(code.SECTION_TYPE, 1, code.FUNC, 0, 1, code.I32)
+
+// A code section with one function giving 42
+var codeSection = section(code.SECTION_CODE, 1, 4, 0, code.I32_CONST, 42, code.END)
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		bytes []byte
+		err   string
+	}{
+		{"empty", []byte{}, "module is too short for the header"},
+		{"short header", code.MagicModuleHeader, "module is too short for the header"},
+		{"magic header", []byte{0x00, 0x61, 0x73, 0x6e, 1, 0, 0, 0}, "module does not start with the magic header"},
+		{"version", []byte{0x00, 0x61, 0x73, 0x6d, 2, 0, 0, 0}, "module version is not"},
+		{"unknown section id", module(section(12)), "unknown section id 12 at byte 8"},
+		{"section size", module([]byte{code.SECTION_TYPE, 5, 0}), "size of type section"},
+		{"section order", module(section(code.SECTION_FUNCTION, 0), typeSection), "type section at byte 11 is out of order"},
+		{"duplicate section", module(typeSection, typeSection), "type section at byte 15 is out of order or appears more than once"},
+		{"function without code", module(typeSection, section(code.SECTION_FUNCTION, 1, 0)), "the function section has 1 functions but there is no code section"},
+		{"more functions than bodies", module(typeSection, section(code.SECTION_FUNCTION, 2, 0, 0), codeSection), "there are 1 function bodies, but the function section has 2 functions"},
+		{"more bodies than functions", module(typeSection, codeSection), "there are 1 function bodies, but the function section has 0 functions"},
+		{"content shorter than the section", module(section(code.SECTION_FUNCTION, 1, 0, 0)), "section size is 3 bytes but the content ends at byte 2"},
+		{"type form", module(section(code.SECTION_TYPE, 1, 0x61, 0, 0)), "type does not start with 0x60"},
+		{"value type", module(section(code.SECTION_TYPE, 1, code.FUNC, 1, 0x70, 0)), "invalid value type 0x70"},
+		{"unknown instruction", module(typeSection, section(code.SECTION_FUNCTION, 1, 0), section(code.SECTION_CODE, 1, 3, 0, 0xff, code.END)), "unknown instruction 0xff"},
+		{"missing end", module(typeSection, section(code.SECTION_FUNCTION, 1, 0), section(code.SECTION_CODE, 1, 3, 0, code.I32_CONST, 42)), "expected instruction"},
+		{"bytes after the end", module(typeSection, section(code.SECTION_FUNCTION, 1, 0), section(code.SECTION_CODE, 1, 5, 0, code.I32_CONST, 42, code.END, code.NOP)), "function ends at byte 4, before the end of the function body"},
+		{"export kind", module(section(code.SECTION_EXPORT, 1, 1, 'f', 4, 0)), "export f has invalid kind 4"},
+		{"global mutability", module(section(code.SECTION_GLOBAL, 1, code.I32, 2, code.I32_CONST, 0, code.END)), "invalid global mutability 2"},
+	}
+
+	for _, test := range tests {
+		_, err := Decode(test.bytes)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, expected %q", test.name, err, test.err)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	exportSection := section(code.SECTION_EXPORT, 1, 4, 'm', 'a', 'i', 'n', code.DESC_FUNCTION, 0)
+	customSection := section(code.SECTION_CUSTOM, 4, 'n', 'a', 'm', 'e', 1, 2)
+	m, err := Decode(module(typeSection, section(code.SECTION_FUNCTION, 1, 0), exportSection, codeSection, customSection))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(m.Types) != 1 || len(m.Types[0].Parameters) != 0 || len(m.Types[0].Results) != 1 || m.Types[0].Results[0] != code.I32 {
+		t.Errorf("types are %v, expected () -> (i32)", m.Types)
+	}
+
+	if len(m.Functions) != 1 || m.Functions[0] != 0 {
+		t.Errorf("functions are %v, expected [0]", m.Functions)
+	}
+
+	if len(m.Exports) != 1 || m.Exports[0].Name != "main" || m.Exports[0].Kind != code.DESC_FUNCTION {
+		t.Errorf("exports are %v, expected main", m.Exports)
+	}
+
+	if len(m.Customs) != 1 || m.Customs[0].Name != "name" || len(m.Customs[0].Bytes) != 2 {
+		t.Errorf("custom sections are %v, expected name with 2 bytes", m.Customs)
+	}
+
+	if len(m.Code) != 1 {
+		t.Fatalf("there are %v function bodies, expected 1", len(m.Code))
+	}
+
+	instructions := m.Code[0].Instructions
+	if len(instructions) != 2 || instructions[0].Opcode != code.I32_CONST || instructions[0].I32 != 42 || instructions[1].Opcode != code.END {
+		t.Errorf("instructions are %v, expected i32.const 42 and end", instructions)
+	}
+
+	//The body starts after the header, the type, function and export sections and the code section id, size, count and body size
+	expectedOffset := 8 + len(typeSection) + 4 + len(exportSection) + 4
+	if m.Code[0].Offset != expectedOffset || instructions[0].Offset != 1 || instructions[0].Length != 2 {
+		t.Errorf("body is at byte %v with i32.const at %v of length %v, expected %v, 1 and 2", m.Code[0].Offset, instructions[0].Offset, instructions[0].Length, expectedOffset)
+	}
+}
+
+func TestReadInstruction(t *testing.T) {
+	tests := []struct {
+		name     string
+		bytes    []byte
+		expected Instruction
+	}{
+		{"empty block", []byte{code.BLOCK, code.EMPTY}, Instruction{Opcode: code.BLOCK, BlockType: BlockType{Results: []uint8{}, TypeIndex: -1}}},
+		{"block with result", []byte{code.BLOCK, code.I64}, Instruction{Opcode: code.BLOCK, BlockType: BlockType{Results: []uint8{code.I64}, TypeIndex: -1}}},
+		{"block with type index", []byte{code.BLOCK, 0xc0, 0x00}, Instruction{Opcode: code.BLOCK, BlockType: BlockType{TypeIndex: 64}}},
+		{"br_table", []byte{code.BR_TABLE, 2, 0, 1, 2}, Instruction{Opcode: code.BR_TABLE, Labels: []uint32{0, 1, 2}}},
+		{"negative i32", []byte{code.I32_CONST, 0x7f}, Instruction{Opcode: code.I32_CONST, I32: -1}},
+		{"i64", []byte{code.I64_CONST, 0x80, 0x01}, Instruction{Opcode: code.I64_CONST, I64: 128}},
+		{"load", []byte{code.I32_LOAD, 2, 8}, Instruction{Opcode: code.I32_LOAD, Align: 2, MemoryOffset: 8}},
+		{"call_indirect", []byte{code.CALL_INDIRECT, 3, 0}, Instruction{Opcode: code.CALL_INDIRECT, Index: 3}},
+	}
+
+	for _, test := range tests {
+		instruction, err := ReadInstruction(NewReader(test.bytes))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err.Error())
+			continue
+		}
+
+		test.expected.Length = len(test.bytes)
+		if !instructionsEqual(instruction, test.expected) {
+			t.Errorf("%s: got %+v, expected %+v", test.name, instruction, test.expected)
+		}
+	}
+}
+
+func instructionsEqual(a Instruction, b Instruction) bool {
+	if a.Opcode != b.Opcode || a.Length != b.Length || a.Index != b.Index || a.Table != b.Table || a.Align != b.Align ||
+		a.MemoryOffset != b.MemoryOffset || a.I32 != b.I32 || a.I64 != b.I64 || a.BlockType.TypeIndex != b.BlockType.TypeIndex {
+		return false
+	}
+
+	return uint32sEqual(a.Labels, b.Labels) && string(a.BlockType.Results) == string(b.BlockType.Results)
+}
+
+func uint32sEqual(a []uint32, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestEncodeInstruction(t *testing.T) {
+	tests := []struct {
+		name        string
+		instruction Instruction
+		bytes       []byte
+	}{
+		{"empty block", Instruction{Opcode: code.BLOCK, BlockType: BlockType{Results: []uint8{}, TypeIndex: -1}}, []byte{code.BLOCK, code.EMPTY}},
+		{"block with result", Instruction{Opcode: code.BLOCK, BlockType: BlockType{Results: []uint8{code.I64}, TypeIndex: -1}}, []byte{code.BLOCK, code.I64}},
+		{"block with type index", Instruction{Opcode: code.BLOCK, BlockType: BlockType{TypeIndex: 64}}, []byte{code.BLOCK, 0xc0, 0x00}},
+		{"br_table", Instruction{Opcode: code.BR_TABLE, Labels: []uint32{0, 1, 2}}, []byte{code.BR_TABLE, 2, 0, 1, 2}},
+		{"negative i32", Instruction{Opcode: code.I32_CONST, I32: -1}, []byte{code.I32_CONST, 0x7f}},
+		{"i64", Instruction{Opcode: code.I64_CONST, I64: 128}, []byte{code.I64_CONST, 0x80, 0x01}},
+		{"f32", Instruction{Opcode: code.F32_CONST, F32: 1}, []byte{code.F32_CONST, 0x00, 0x00, 0x80, 0x3f}},
+		{"load", Instruction{Opcode: code.I32_LOAD, Align: 2, MemoryOffset: 8}, []byte{code.I32_LOAD, 2, 8}},
+		{"call_indirect", Instruction{Opcode: code.CALL_INDIRECT, Index: 3}, []byte{code.CALL_INDIRECT, 3, 0}},
+		{"misc", Instruction{Opcode: code.MISC_PREFIX, MiscOpcode: 2}, []byte{code.MISC_PREFIX, 2}},
+		{"without immediates", Instruction{Opcode: code.I32_ADD}, []byte{code.I32_ADD}},
+	}
+
+	for _, test := range tests {
+		bytes := EncodeInstruction(test.instruction)
+		if string(bytes) != string(test.bytes) {
+			t.Errorf("%s: got %v, expected %v", test.name, bytes, test.bytes)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	exportSection := section(code.SECTION_EXPORT, 1, 4, 'm', 'a', 'i', 'n', code.DESC_FUNCTION, 0)
+	globalSection := section(code.SECTION_GLOBAL, 1, code.I32, code.MUTABLE, code.I32_CONST, 7, code.END)
+	elementSection := section(code.SECTION_ELEMENT, 1, 0, code.I32_CONST, 0, code.END, 1, 0)
+	tableSection := section(code.SECTION_TABLE, 1, code.ANYFUNC, code.LIMIT_MIN_MAX, 1, 1)
+	bytes := module(typeSection, section(code.SECTION_FUNCTION, 1, 0), tableSection, globalSection, exportSection, elementSection, codeSection)
+
+	m, err := Decode(bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//The decoded module gives the same bytes when it is encoded again
+	encoded := Encode(m)
+	if string(encoded) != string(bytes) {
+		t.Errorf("encoded module is %v, expected %v", encoded, bytes)
+	}
+
+	//Modules built without the decoder have no offsets or bytes of the bodies
+	built := &Module{
+		Types:     m.Types,
+		Functions: []uint32{0},
+		Code:      []FunctionBody{{Instructions: []Instruction{{Opcode: code.I32_CONST, I32: 42}, {Opcode: code.END}}}},
+	}
+
+	expected := module(typeSection, section(code.SECTION_FUNCTION, 1, 0), codeSection)
+	if encoded := Encode(built); string(encoded) != string(expected) {
+		t.Errorf("encoded module is %v, expected %v", encoded, expected)
+	}
+}
@@ -0,0 +1,372 @@
+package wasmDisassembler
+
+import (
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
+	"compiler/wasmText"
+	"fmt"
+	"strings"
+)
+
+//The disassembler prints every section of a module with its position, and every instruction with its position and
+//its bytes, so the indexes in the binary can be compared with the indexes the compiler expects. Functions are named
+//with the names from the name section, the names they are exported with, or the names given to the disassembler
+
+const nameSectionFunctionNames uint8 = 1
+
+// The number of bytes shown before the text of an instruction. Longer instructions continue on the next lines
+const bytesPerLine = 8
+
+type disassembler struct {
+	module        *wasmDecoder.Module
+	functionNames map[int]string
+	lines         []string
+}
+
+// The given function names are used for the functions the module does not name, and can be nil
+func Disassemble(moduleBytes []byte, functionNames map[int]string) (string, error) {
+	module, err := wasmDecoder.Decode(moduleBytes)
+	if err != nil {
+		return "", err
+	}
+
+	d := &disassembler{module: module, functionNames: getFunctionNames(module), lines: make([]string, 0)}
+	for index, name := range functionNames {
+		if _, hasName := d.functionNames[index]; !hasName {
+			d.functionNames[index] = name
+		}
+	}
+
+	sectionPrinters := map[uint8]func(){
+		code.SECTION_TYPE:     d.printTypes,
+		code.SECTION_IMPORT:   d.printImports,
+		code.SECTION_FUNCTION: d.printFunctions,
+		code.SECTION_TABLE:    d.printTables,
+		code.SECTION_MEMORY:   d.printMemories,
+		code.SECTION_GLOBAL:   d.printGlobals,
+		code.SECTION_EXPORT:   d.printExports,
+		code.SECTION_START:    d.printStart,
+		code.SECTION_ELEMENT:  d.printElements,
+		code.SECTION_CODE:     d.printCode,
+		code.SECTION_DATA:     d.printData,
+	}
+
+	d.addLine(fmt.Sprintf("module of %v bytes with %v sections", len(moduleBytes), len(module.Sections)))
+
+	numCustoms := 0
+	for i := 0; i < len(module.Sections); i++ {
+		section := module.Sections[i]
+
+		d.addLine("")
+		d.addLine(fmt.Sprintf("%s section (id %v) at 0x%06x, content at 0x%06x, %v bytes", wasmDecoder.SectionNames[section.Id], section.Id, section.Offset, section.ContentOffset, section.Size))
+
+		if section.Id == code.SECTION_CUSTOM {
+			custom := module.Customs[numCustoms]
+			numCustoms++
+
+			d.addLine(fmt.Sprintf("  name %q, %v bytes of content", custom.Name, len(custom.Bytes)))
+			continue
+		}
+
+		sectionPrinters[section.Id]()
+	}
+
+	return strings.Join(d.lines, "\n") + "\n", nil
+}
+
+func (d *disassembler) printTypes() {
+	for i := 0; i < len(d.module.Types); i++ {
+		d.addLine(fmt.Sprintf("  type %v: %s", i, typeText(d.module.Types[i])))
+	}
+}
+
+func (d *disassembler) printImports() {
+	numFunctions := 0
+	numGlobals := 0
+
+	for i := 0; i < len(d.module.Imports); i++ {
+		imported := d.module.Imports[i]
+
+		description := ""
+		switch imported.Kind {
+		case code.DESC_FUNCTION:
+			description = fmt.Sprintf("%s type %v", d.functionText(numFunctions), imported.TypeIndex)
+			numFunctions++
+		case code.DESC_TABLE:
+			description = "table " + limitsText(imported.Table.Limits)
+		case code.DESC_MEMORY:
+			description = "memory " + limitsText(imported.Memory)
+		case code.DESC_GLOBAL:
+			description = fmt.Sprintf("global %v %s", numGlobals, globalTypeText(imported.Global))
+			numGlobals++
+		}
+
+		d.addLine(fmt.Sprintf("  import %v: %s.%s %s", i, imported.Module, imported.Name, description))
+	}
+}
+
+func (d *disassembler) printFunctions() {
+	for i := 0; i < len(d.module.Functions); i++ {
+		functionIndex := d.module.NumImportedFunctions() + i
+		d.addLine(fmt.Sprintf("  %s: type %v", d.functionText(functionIndex), d.module.Functions[i]))
+	}
+}
+
+func (d *disassembler) printTables() {
+	for i := 0; i < len(d.module.Tables); i++ {
+		d.addLine(fmt.Sprintf("  table %v: element type 0x%x, %s", i, d.module.Tables[i].ElementType, limitsText(d.module.Tables[i].Limits)))
+	}
+}
+
+func (d *disassembler) printMemories() {
+	for i := 0; i < len(d.module.Memories); i++ {
+		d.addLine(fmt.Sprintf("  memory %v: %s", i, limitsText(d.module.Memories[i])))
+	}
+}
+
+func (d *disassembler) printGlobals() {
+	for i := 0; i < len(d.module.Globals); i++ {
+		global := d.module.Globals[i]
+		d.addLine(fmt.Sprintf("  global %v: %s = %s", d.module.NumImportedGlobals()+i, globalTypeText(global.Type), expressionText(global.Init)))
+	}
+}
+
+func (d *disassembler) printExports() {
+	for i := 0; i < len(d.module.Exports); i++ {
+		export := d.module.Exports[i]
+		d.addLine(fmt.Sprintf("  export %v: %q %s %v", i, export.Name, wasmDecoder.ExportKindNames[export.Kind], export.Index))
+	}
+}
+
+func (d *disassembler) printStart() {
+	d.addLine("  start: " + d.functionText(int(d.module.Start)))
+}
+
+func (d *disassembler) printElements() {
+	for i := 0; i < len(d.module.Elements); i++ {
+		element := d.module.Elements[i]
+		d.addLine(fmt.Sprintf("  element %v: table %v, offset %s, %v functions", i, element.TableIndex, expressionText(element.Offset), len(element.Functions)))
+
+		//The position in the table is printed for every function, as it is the index call_indirect uses
+		for j := 0; j < len(element.Functions); j++ {
+			d.addLine(fmt.Sprintf("    [%v] = %s", j, d.functionText(int(element.Functions[j]))))
+		}
+	}
+}
+
+func (d *disassembler) printCode() {
+	for i := 0; i < len(d.module.Code); i++ {
+		functionIndex := d.module.NumImportedFunctions() + i
+		body := d.module.Code[i]
+
+		typeIndex := d.module.Functions[i]
+		signature := ""
+		if int(typeIndex) < len(d.module.Types) {
+			signature = " " + typeText(d.module.Types[typeIndex])
+		}
+
+		d.addLine(fmt.Sprintf("  %s: type %v%s, body at 0x%06x, %v bytes", d.functionText(functionIndex), typeIndex, signature, body.Offset, len(body.Bytes)))
+
+		for j := 0; j < len(body.Locals); j++ {
+			d.addLine(fmt.Sprintf("    locals: %v %s", body.Locals[j].Count, wasmDecoder.ValueTypeNames[body.Locals[j].ValueType]))
+		}
+
+		d.printInstructions(body)
+	}
+}
+
+// Every instruction is printed with its position in the module, its bytes and its text, indented by the depth of the
+// blocks it is in
+func (d *disassembler) printInstructions(body wasmDecoder.FunctionBody) {
+	depth := 0
+
+	for i := 0; i < len(body.Instructions); i++ {
+		instruction := body.Instructions[i]
+		instructionBytes := body.Bytes[instruction.Offset : instruction.Offset+instruction.Length]
+
+		if instruction.Opcode == code.END || instruction.Opcode == code.ELSE {
+			depth--
+		}
+
+		if depth < 0 {
+			depth = 0
+		}
+
+		text := strings.Repeat("  ", depth) + wasmText.InstructionText(instruction)
+		if instruction.Opcode == code.CALL {
+			text += " ;; " + d.functionText(int(instruction.Index))
+		}
+
+		for start := 0; start < len(instructionBytes); start += bytesPerLine {
+			end := start + bytesPerLine
+			if end > len(instructionBytes) {
+				end = len(instructionBytes)
+			}
+
+			lineText := ""
+			if start == 0 {
+				lineText = text
+			}
+
+			d.addLine(fmt.Sprintf("    %06x: %-*s | %s", body.Offset+instruction.Offset+start, bytesPerLine*3-1, bytesText(instructionBytes[start:end]), lineText))
+		}
+
+		switch instruction.Opcode {
+		case code.BLOCK, code.LOOP, code.IF, code.ELSE:
+			depth++
+		}
+	}
+}
+
+func (d *disassembler) printData() {
+	for i := 0; i < len(d.module.Data); i++ {
+		segment := d.module.Data[i]
+		if segment.Passive {
+			d.addLine(fmt.Sprintf("  data %v: passive, %v bytes", i, len(segment.Bytes)))
+			continue
+		}
+
+		d.addLine(fmt.Sprintf("  data %v: memory %v, offset %s, %v bytes", i, segment.MemoryIndex, expressionText(segment.Offset), len(segment.Bytes)))
+	}
+}
+
+func (d *disassembler) functionText(functionIndex int) string {
+	if name, hasName := d.functionNames[functionIndex]; hasName {
+		return fmt.Sprintf("func %v <%s>", functionIndex, name)
+	}
+
+	return fmt.Sprintf("func %v", functionIndex)
+}
+
+func (d *disassembler) addLine(line string) {
+	d.lines = append(d.lines, line)
+}
+
+// Functions are named with the names they are exported with, and the names in the name section replace them
+func getFunctionNames(module *wasmDecoder.Module) map[int]string {
+	names := make(map[int]string)
+
+	for i := 0; i < len(module.Exports); i++ {
+		if module.Exports[i].Kind == code.DESC_FUNCTION {
+			names[int(module.Exports[i].Index)] = module.Exports[i].Name
+		}
+	}
+
+	for i := 0; i < len(module.Customs); i++ {
+		if module.Customs[i].Name != "name" {
+			continue
+		}
+
+		//An invalid name section is ignored, as engines do not use it to run the module either
+		nameSectionNames, err := readNameSectionFunctionNames(module.Customs[i].Bytes)
+		if err != nil {
+			continue
+		}
+
+		for index, name := range nameSectionNames {
+			names[index] = name
+		}
+	}
+
+	return names
+}
+
+// The name section has subsections with an id and a size. The function names subsection is a vector of function
+// indexes and names
+func readNameSectionFunctionNames(content []byte) (map[int]string, error) {
+	names := make(map[int]string)
+	r := wasmDecoder.NewReader(content)
+
+	for !r.IsAtEnd() {
+		subsectionId, err := r.ReadByte()
+		if err != nil {
+			return names, err
+		}
+
+		subsectionSize, err := r.ReadVectorLength()
+		if err != nil {
+			return names, err
+		}
+
+		subsection, err := r.ReadBytes(subsectionSize)
+		if err != nil {
+			return names, err
+		}
+
+		if subsectionId != nameSectionFunctionNames {
+			continue
+		}
+
+		subsectionReader := wasmDecoder.NewReader(subsection)
+		numNames, err := subsectionReader.ReadVectorLength()
+		if err != nil {
+			return names, err
+		}
+
+		for i := 0; i < numNames; i++ {
+			functionIndex, err := subsectionReader.ReadU32()
+			if err != nil {
+				return names, err
+			}
+
+			name, err := subsectionReader.ReadName()
+			if err != nil {
+				return names, err
+			}
+
+			names[int(functionIndex)] = name
+		}
+	}
+
+	return names, nil
+}
+
+func typeText(functionType wasmDecoder.FunctionType) string {
+	return "(" + valueTypesText(functionType.Parameters) + ") -> (" + valueTypesText(functionType.Results) + ")"
+}
+
+func valueTypesText(valueTypes []uint8) string {
+	names := make([]string, 0)
+	for i := 0; i < len(valueTypes); i++ {
+		names = append(names, wasmDecoder.ValueTypeNames[valueTypes[i]])
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func limitsText(limits wasmDecoder.Limits) string {
+	if limits.HasMax {
+		return fmt.Sprintf("min %v max %v", limits.Min, limits.Max)
+	}
+
+	return fmt.Sprintf("min %v", limits.Min)
+}
+
+func globalTypeText(globalType wasmDecoder.GlobalType) string {
+	if globalType.Mutable {
+		return "mut " + wasmDecoder.ValueTypeNames[globalType.ValueType]
+	}
+
+	return wasmDecoder.ValueTypeNames[globalType.ValueType]
+}
+
+// Constant expressions are printed without their end instruction
+func expressionText(expression []wasmDecoder.Instruction) string {
+	texts := make([]string, 0)
+	for i := 0; i < len(expression); i++ {
+		if expression[i].Opcode != code.END {
+			texts = append(texts, wasmText.InstructionText(expression[i]))
+		}
+	}
+
+	return strings.Join(texts, " ")
+}
+
+func bytesText(bytes []byte) string {
+	texts := make([]string, 0)
+	for i := 0; i < len(bytes); i++ {
+		texts = append(texts, fmt.Sprintf("%02x", bytes[i]))
+	}
+
+	return strings.Join(texts, " ")
+}
@@ -0,0 +1,65 @@
+package wasmDisassembler
+
+import (
+	"compiler/wasmCompiler"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDisassembleStandardFunctions(t *testing.T) {
+	fileName := "../builtInsCode/randomFunctions.wasm"
+	moduleBytes, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := Disassemble(moduleBytes, wasmCompiler.StandardFunctionNames(fileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//The positions are the positions of the section ids and the function bodies in the file
+	expectedLines := []string{
+		"module of 304 bytes with 4 sections",
+		"type section (id 1) at 0x000008, content at 0x00000a, 16 bytes",
+		"  type 1: (i32, i32) -> (i32)",
+		"function section (id 3) at 0x00001a, content at 0x00001c, 4 bytes",
+		"  func 0 <seed>: type 0",
+		"  func 1 <randomInt>: type 1",
+		"  func 2 <randomFloat>: type 2",
+		"global section (id 6) at 0x000020, content at 0x000022, 14 bytes",
+		"  global 0: mut i64 = i64.const 2685821657736338717",
+		"code section (id 10) at 0x000030, content at 0x000033, 253 bytes",
+		"  func 0 <seed>: type 0 (i32) -> (i32), body at 0x000035, 99 bytes",
+		"    locals: 1 i64",
+		"    000038: 20 00                   | local.get 0",
+		"    000093: 24 00                   | global.set 0",
+		"  func 1 <randomInt>: type 1 (i32, i32) -> (i32), body at 0x000099, 85 bytes",
+		"  func 2 <randomFloat>: type 2 () -> (f32), body at 0x0000ef, 65 bytes",
+	}
+
+	lines := strings.Split(text, "\n")
+	for _, expected := range expectedLines {
+		if !containsLine(lines, expected) {
+			t.Errorf("expected the line %q in:\n%s", expected, text)
+		}
+	}
+}
+
+func TestDisassembleErrors(t *testing.T) {
+	_, err := Disassemble([]byte{0x00, 0x61, 0x73, 0x6d}, nil)
+	if err == nil || !strings.Contains(err.Error(), "module is too short for the header") {
+		t.Errorf("got error %v, expected the header to be too short", err)
+	}
+}
+
+func containsLine(lines []string, line string) bool {
+	for i := 0; i < len(lines); i++ {
+		if lines[i] == line {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,288 @@
+package wasmText
+
+import (
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// A folded instruction with the instructions that give its operands, and the bodies of blocks. The if instruction has
+// the then branch and the else branch as its blocks
+type node struct {
+	instruction wasmDecoder.Instruction
+	operands    []*node
+	blocks      [][]*node
+	numResults  int //-1 when the number of values the instruction leaves on the stack is not known
+}
+
+// The instructions after the end of the function are not printed, as the function is closed by its parenthesis
+func (p *printer) printLinearInstructions(instructions []wasmDecoder.Instruction) {
+	for i := 0; i < len(instructions)-1; i++ {
+		instruction := instructions[i]
+
+		switch instruction.Opcode {
+		case code.END:
+			p.indent--
+			p.addLine("end")
+			continue
+		case code.ELSE:
+			p.indent--
+			p.addLine("else")
+			p.indent++
+			continue
+		}
+
+		p.addLine(p.instructionText(instruction))
+
+		switch instruction.Opcode {
+		case code.BLOCK, code.LOOP, code.IF:
+			p.indent++
+		}
+	}
+}
+
+// Reads the instructions until the end or else instruction that ends the block, and gives the opcode that ended it.
+// Every instruction takes the instructions before it as its operands when they each give one value, and there are
+// as many of them as the instruction uses
+func (p *printer) foldInstructions(instructions []wasmDecoder.Instruction, position *int) ([]*node, uint8) {
+	nodes := make([]*node, 0)
+
+	for *position < len(instructions) {
+		instruction := instructions[*position]
+		*position++
+
+		if instruction.Opcode == code.END || instruction.Opcode == code.ELSE {
+			return nodes, instruction.Opcode
+		}
+
+		newNode := &node{instruction: instruction, operands: []*node{}, blocks: [][]*node{}}
+
+		switch instruction.Opcode {
+		case code.BLOCK, code.LOOP:
+			body, _ := p.foldInstructions(instructions, position)
+			newNode.blocks = append(newNode.blocks, body)
+		case code.IF:
+			thenBranch, endOpcode := p.foldInstructions(instructions, position)
+			newNode.blocks = append(newNode.blocks, thenBranch)
+
+			if endOpcode == code.ELSE {
+				elseBranch, _ := p.foldInstructions(instructions, position)
+				newNode.blocks = append(newNode.blocks, elseBranch)
+			}
+		}
+
+		numOperands, numResults := p.getStackEffect(instruction)
+		newNode.numResults = numResults
+
+		nodes = foldOperands(nodes, newNode, numOperands)
+	}
+
+	return nodes, code.END
+}
+
+func foldOperands(nodes []*node, newNode *node, numOperands int) []*node {
+	if numOperands > len(nodes) {
+		return append(nodes, newNode)
+	}
+
+	operands := nodes[len(nodes)-numOperands:]
+	for i := 0; i < len(operands); i++ {
+		if operands[i].numResults != 1 {
+			return append(nodes, newNode)
+		}
+	}
+
+	newNode.operands = append(newNode.operands, operands...)
+	return append(nodes[:len(nodes)-numOperands], newNode)
+}
+
+// Gives the number of operands an instruction can be folded with and the number of values it gives. Instructions that
+// end the block they are in give an unknown number of values, so they are never used as operands
+func (p *printer) getStackEffect(instruction wasmDecoder.Instruction) (numOperands int, numResults int) {
+	if instructionType, isNumeric := wasmDecoder.NumericInstructionTypes[instruction.Opcode]; isNumeric {
+		return len(instructionType.Parameters), len(instructionType.Results)
+	}
+
+	if instruction.Opcode >= code.I32_LOAD && instruction.Opcode <= code.I64_LOAD32_U {
+		return 1, 1
+	}
+
+	if instruction.Opcode >= code.I32_STORE && instruction.Opcode <= code.I64_STORE32 {
+		return 2, 0
+	}
+
+	switch instruction.Opcode {
+	case code.I32_CONST, code.I64_CONST, code.F32_CONST, code.F64_CONST, code.LOCAL_GET, code.GLOBAL_GET, code.MEMORY_SIZE:
+		return 0, 1
+	case code.LOCAL_SET, code.GLOBAL_SET, code.DROP:
+		return 1, 0
+	case code.LOCAL_TEE, code.MEMORY_GROW:
+		return 1, 1
+	case code.SELECT:
+		return 3, 1
+	case code.NOP:
+		return 0, 0
+	case code.BLOCK, code.LOOP:
+		return 0, p.getNumBlockResults(instruction.BlockType)
+	case code.IF:
+		return 1, p.getNumBlockResults(instruction.BlockType)
+	case code.BR_IF, code.BR_TABLE:
+		return 1, -1
+	case code.RETURN:
+		return len(p.results), -1
+	case code.CALL:
+		typeIndex, err := p.module.FunctionTypeIndex(int(instruction.Index))
+		if err != nil || int(typeIndex) >= len(p.module.Types) {
+			return 0, -1
+		}
+
+		calledType := p.module.Types[typeIndex]
+		return len(calledType.Parameters), len(calledType.Results)
+	case code.CALL_INDIRECT:
+		if int(instruction.Index) >= len(p.module.Types) {
+			return 1, -1
+		}
+
+		calledType := p.module.Types[instruction.Index]
+		return len(calledType.Parameters) + 1, len(calledType.Results)
+	case code.MISC_PREFIX:
+		instructionType := wasmDecoder.MiscInstructionTypes[uint8(instruction.MiscOpcode)]
+		return len(instructionType.Parameters), len(instructionType.Results)
+	}
+
+	return 0, -1
+}
+
+func (p *printer) getNumBlockResults(blockType wasmDecoder.BlockType) int {
+	if blockType.TypeIndex == -1 {
+		return len(blockType.Results)
+	}
+
+	if blockType.TypeIndex >= int64(len(p.module.Types)) {
+		return -1
+	}
+
+	return len(p.module.Types[blockType.TypeIndex].Results)
+}
+
+func (p *printer) printNode(n *node) {
+	if len(n.operands) == 0 && len(n.blocks) == 0 {
+		p.addLine("(" + p.instructionText(n.instruction) + ")")
+		return
+	}
+
+	p.addLine("(" + p.instructionText(n.instruction))
+	p.indent++
+
+	for i := 0; i < len(n.operands); i++ {
+		p.printNode(n.operands[i])
+	}
+
+	//The branches of if are in then and else expressions, the body of block and loop is directly in the instruction
+	if n.instruction.Opcode == code.IF {
+		branchNames := []string{"then", "else"}
+		for i := 0; i < len(n.blocks); i++ {
+			p.addLine("(" + branchNames[i])
+			p.indent++
+			p.printNodes(n.blocks[i])
+			p.indent--
+			p.closeLine()
+		}
+	} else if len(n.blocks) != 0 {
+		p.printNodes(n.blocks[0])
+	}
+
+	p.indent--
+	p.closeLine()
+}
+
+func (p *printer) printNodes(nodes []*node) {
+	for i := 0; i < len(nodes); i++ {
+		p.printNode(nodes[i])
+	}
+}
+
+// Gives the instruction in the text format, with the indexes of functions and locals instead of their names
+func InstructionText(instruction wasmDecoder.Instruction) string {
+	return (&printer{}).instructionText(instruction)
+}
+
+// Gives the name of the instruction with its immediates
+func (p *printer) instructionText(instruction wasmDecoder.Instruction) string {
+	if instruction.Opcode == code.MISC_PREFIX {
+		return code.MiscInstructionNames[uint8(instruction.MiscOpcode)]
+	}
+
+	name := code.InstructionNames[instruction.Opcode]
+
+	if instruction.Opcode >= code.I32_LOAD && instruction.Opcode <= code.I64_STORE32 {
+		if instruction.MemoryOffset != 0 {
+			name += fmt.Sprintf(" offset=%v", instruction.MemoryOffset)
+		}
+
+		return name + fmt.Sprintf(" align=%v", uint64(1)<<instruction.Align)
+	}
+
+	switch instruction.Opcode {
+	case code.BLOCK, code.LOOP, code.IF:
+		return name + blockTypeText(instruction.BlockType)
+	case code.BR, code.BR_IF:
+		return fmt.Sprintf("%s %v", name, instruction.Index)
+	case code.BR_TABLE:
+		for i := 0; i < len(instruction.Labels); i++ {
+			name += fmt.Sprintf(" %v", instruction.Labels[i])
+		}
+
+		return name
+	case code.CALL:
+		return name + " " + p.functionReference(instruction.Index)
+	case code.CALL_INDIRECT:
+		if instruction.Table != 0 {
+			name += fmt.Sprintf(" %v", instruction.Table)
+		}
+
+		return fmt.Sprintf("%s (type %v)", name, instruction.Index)
+	case code.LOCAL_GET, code.LOCAL_SET, code.LOCAL_TEE:
+		return name + " " + p.localReference(instruction.Index)
+	case code.GLOBAL_GET, code.GLOBAL_SET:
+		return name + " " + p.globalReference(instruction.Index)
+	case code.I32_CONST:
+		return fmt.Sprintf("%s %v", name, instruction.I32)
+	case code.I64_CONST:
+		return fmt.Sprintf("%s %v", name, instruction.I64)
+	case code.F32_CONST:
+		return name + " " + floatText(float64(instruction.F32), 32)
+	case code.F64_CONST:
+		return name + " " + floatText(instruction.F64, 64)
+	}
+
+	return name
+}
+
+func blockTypeText(blockType wasmDecoder.BlockType) string {
+	if blockType.TypeIndex != -1 {
+		return fmt.Sprintf(" (type %v)", blockType.TypeIndex)
+	}
+
+	if len(blockType.Results) == 0 {
+		return ""
+	}
+
+	return " (result" + valueTypesText(blockType.Results) + ")"
+}
+
+// Floats are printed with the fewest digits that give the same value when they are read
+func floatText(value float64, bitSize int) string {
+	switch {
+	case math.IsNaN(value):
+		return "nan"
+	case math.IsInf(value, 1):
+		return "inf"
+	case math.IsInf(value, -1):
+		return "-inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, bitSize)
+}
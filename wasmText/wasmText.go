@@ -0,0 +1,406 @@
+package wasmText
+
+import (
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//The text format is printed from a module of the decoder, which is either the module the compiler encodes or a module
+//decoded from a binary. Functions, locals and globals are printed with the names given to them, and with their indexes
+//when they have no name
+
+// The names of the functions by function index, the names of their locals by function index and local index, and the
+// names of the globals by global index
+type Names struct {
+	Functions map[int]string
+	Locals    map[int]map[int]string
+	Globals   map[int]string
+}
+
+type printer struct {
+	module      *wasmDecoder.Module
+	folded      bool
+	functionIds map[int]string
+	localNames  map[int]map[int]string
+	globalIds   map[int]string
+
+	localIds map[int]string //The identifiers of the locals of the function being printed
+	results  []uint8        //The results of the function being printed
+
+	lines  []string
+	indent int
+}
+
+// Gives the module in the WebAssembly text format. Folded output nests the operands of instructions and the bodies of
+// blocks inside them, linear output has one instruction on every line
+func Print(moduleBytes []byte, names Names, folded bool) (string, error) {
+	module, err := wasmDecoder.Decode(moduleBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return PrintModule(module, names, folded)
+}
+
+// Gives the text format of a module that is already decoded or built by the compiler
+func PrintModule(module *wasmDecoder.Module, names Names, folded bool) (string, error) {
+	p := &printer{
+		module:      module,
+		folded:      folded,
+		functionIds: getIdentifiers(names.Functions),
+		localNames:  names.Locals,
+		globalIds:   getIdentifiers(names.Globals),
+		lines:       make([]string, 0),
+	}
+
+	err := p.printModule()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(p.lines, "\n") + "\n", nil
+}
+
+func (p *printer) printModule() error {
+	p.addLine("(module")
+	p.indent++
+
+	for i := 0; i < len(p.module.Types); i++ {
+		p.addLine(fmt.Sprintf("(type (;%v;) (func%s))", i, functionTypeText(p.module.Types[i], nil)))
+	}
+
+	p.printImports()
+
+	for i := 0; i < len(p.module.Code); i++ {
+		err := p.printFunction(p.module.NumImportedFunctions() + i)
+		if err != nil {
+			return err
+		}
+	}
+
+	numTables := 0
+	numMemories := 0
+	for i := 0; i < len(p.module.Imports); i++ {
+		switch p.module.Imports[i].Kind {
+		case code.DESC_TABLE:
+			numTables++
+		case code.DESC_MEMORY:
+			numMemories++
+		}
+	}
+
+	for i := 0; i < len(p.module.Tables); i++ {
+		p.addLine(fmt.Sprintf("(table (;%v;) %s)", numTables+i, tableText(p.module.Tables[i])))
+	}
+
+	for i := 0; i < len(p.module.Memories); i++ {
+		p.addLine(fmt.Sprintf("(memory (;%v;) %s)", numMemories+i, limitsText(p.module.Memories[i])))
+	}
+
+	for i := 0; i < len(p.module.Globals); i++ {
+		global := p.module.Globals[i]
+		p.addLine(fmt.Sprintf("(global %s %s %s)", p.globalDefinitionId(p.module.NumImportedGlobals()+i), globalTypeText(global.Type), p.constantExpressionText(global.Init)))
+	}
+
+	for i := 0; i < len(p.module.Exports); i++ {
+		export := p.module.Exports[i]
+
+		reference := fmt.Sprint(export.Index)
+		switch export.Kind {
+		case code.DESC_FUNCTION:
+			reference = p.functionReference(export.Index)
+		case code.DESC_GLOBAL:
+			reference = p.globalReference(export.Index)
+		}
+
+		p.addLine(fmt.Sprintf("(export %s (%s %s))", quoteString([]byte(export.Name)), wasmDecoder.ExportKindNames[export.Kind], reference))
+	}
+
+	if p.module.HasStart {
+		p.addLine(fmt.Sprintf("(start %s)", p.functionReference(p.module.Start)))
+	}
+
+	for i := 0; i < len(p.module.Elements); i++ {
+		element := p.module.Elements[i]
+
+		functions := ""
+		for j := 0; j < len(element.Functions); j++ {
+			functions += " " + p.functionReference(element.Functions[j])
+		}
+
+		p.addLine(fmt.Sprintf("(elem (;%v;) (table %v) %s func%s)", i, element.TableIndex, p.constantExpressionText(element.Offset), functions))
+	}
+
+	for i := 0; i < len(p.module.Data); i++ {
+		segment := p.module.Data[i]
+		if segment.Passive {
+			p.addLine(fmt.Sprintf("(data (;%v;) %s)", i, quoteString(segment.Bytes)))
+			continue
+		}
+
+		p.addLine(fmt.Sprintf("(data (;%v;) (memory %v) %s %s)", i, segment.MemoryIndex, p.constantExpressionText(segment.Offset), quoteString(segment.Bytes)))
+	}
+
+	p.indent--
+	p.closeLine()
+
+	return nil
+}
+
+func (p *printer) printImports() {
+	numFunctions := 0
+	numGlobals := 0
+
+	for i := 0; i < len(p.module.Imports); i++ {
+		imported := p.module.Imports[i]
+
+		description := ""
+		switch imported.Kind {
+		case code.DESC_FUNCTION:
+			description = fmt.Sprintf("(func %s (type %v))", p.functionDefinitionId(numFunctions), imported.TypeIndex)
+			numFunctions++
+		case code.DESC_TABLE:
+			description = fmt.Sprintf("(table %s)", tableText(imported.Table))
+		case code.DESC_MEMORY:
+			description = fmt.Sprintf("(memory %s)", limitsText(imported.Memory))
+		case code.DESC_GLOBAL:
+			description = fmt.Sprintf("(global %s %s)", p.globalDefinitionId(numGlobals), globalTypeText(imported.Global))
+			numGlobals++
+		}
+
+		p.addLine(fmt.Sprintf("(import %s %s %s)", quoteString([]byte(imported.Module)), quoteString([]byte(imported.Name)), description))
+	}
+}
+
+func (p *printer) printFunction(functionIndex int) error {
+	typeIndex, err := p.module.FunctionTypeIndex(functionIndex)
+	if err != nil || int(typeIndex) >= len(p.module.Types) {
+		return fmt.Errorf("function %v has type %v, but there are %v types", functionIndex, typeIndex, len(p.module.Types))
+	}
+
+	functionType := p.module.Types[typeIndex]
+	body := p.module.Code[functionIndex-p.module.NumImportedFunctions()]
+
+	p.localIds = getIdentifiers(p.localNames[functionIndex])
+	p.results = functionType.Results
+
+	p.addLine(fmt.Sprintf("(func %s (type %v)%s", p.functionDefinitionId(functionIndex), typeIndex, functionTypeText(functionType, p.localIds)))
+	p.indent++
+
+	localIndex := len(functionType.Parameters)
+	for i := 0; i < len(body.Locals); i++ {
+		for j := 0; j < int(body.Locals[i].Count); j++ {
+			p.addLine(fmt.Sprintf("(local%s %s)", p.localDefinitionId(localIndex), wasmDecoder.ValueTypeNames[body.Locals[i].ValueType]))
+			localIndex++
+		}
+	}
+
+	if p.folded {
+		position := 0
+		nodes, _ := p.foldInstructions(body.Instructions, &position)
+		p.printNodes(nodes)
+	} else {
+		p.printLinearInstructions(body.Instructions)
+	}
+
+	p.indent--
+	p.closeLine()
+
+	return nil
+}
+
+// Functions are printed with their names and indexes so they can be found with the indexes in the binary
+func (p *printer) functionDefinitionId(functionIndex int) string {
+	if id, hasId := p.functionIds[functionIndex]; hasId {
+		return fmt.Sprintf("%s (;%v;)", id, functionIndex)
+	}
+
+	return fmt.Sprintf("(;%v;)", functionIndex)
+}
+
+func (p *printer) globalDefinitionId(globalIndex int) string {
+	if id, hasId := p.globalIds[globalIndex]; hasId {
+		return fmt.Sprintf("%s (;%v;)", id, globalIndex)
+	}
+
+	return fmt.Sprintf("(;%v;)", globalIndex)
+}
+
+func (p *printer) localDefinitionId(localIndex int) string {
+	if id, hasId := p.localIds[localIndex]; hasId {
+		return " " + id
+	}
+
+	return ""
+}
+
+func (p *printer) functionReference(functionIndex uint32) string {
+	if id, hasId := p.functionIds[int(functionIndex)]; hasId {
+		return id
+	}
+
+	return fmt.Sprint(functionIndex)
+}
+
+func (p *printer) globalReference(globalIndex uint32) string {
+	if id, hasId := p.globalIds[int(globalIndex)]; hasId {
+		return id
+	}
+
+	return fmt.Sprint(globalIndex)
+}
+
+func (p *printer) localReference(localIndex uint32) string {
+	if id, hasId := p.localIds[int(localIndex)]; hasId {
+		return id
+	}
+
+	return fmt.Sprint(localIndex)
+}
+
+// Constant expressions are printed folded, without the end instruction
+func (p *printer) constantExpressionText(expression []wasmDecoder.Instruction) string {
+	output := ""
+	for i := 0; i < len(expression); i++ {
+		if expression[i].Opcode == code.END {
+			continue
+		}
+
+		if output != "" {
+			output += " "
+		}
+
+		output += "(" + p.instructionText(expression[i]) + ")"
+	}
+
+	return output
+}
+
+func (p *printer) addLine(line string) {
+	p.lines = append(p.lines, strings.Repeat("  ", p.indent)+line)
+}
+
+// Adds the closing parenthesis of the last opened expression to the end of the last line
+func (p *printer) closeLine() {
+	p.lines[len(p.lines)-1] += ")"
+}
+
+// The parameters are named with the given identifiers. The identifiers are nil for types that are not of a function
+func functionTypeText(functionType wasmDecoder.FunctionType, parameterIds map[int]string) string {
+	output := ""
+
+	for i := 0; i < len(functionType.Parameters); i++ {
+		if id, hasId := parameterIds[i]; hasId {
+			output += fmt.Sprintf(" (param %s %s)", id, wasmDecoder.ValueTypeNames[functionType.Parameters[i]])
+			continue
+		}
+
+		output += fmt.Sprintf(" (param %s)", wasmDecoder.ValueTypeNames[functionType.Parameters[i]])
+	}
+
+	if len(functionType.Results) != 0 {
+		output += " (result" + valueTypesText(functionType.Results) + ")"
+	}
+
+	return output
+}
+
+func valueTypesText(valueTypes []uint8) string {
+	output := ""
+	for i := 0; i < len(valueTypes); i++ {
+		output += " " + wasmDecoder.ValueTypeNames[valueTypes[i]]
+	}
+
+	return output
+}
+
+func tableText(table wasmDecoder.Table) string {
+	return limitsText(table.Limits) + " funcref"
+}
+
+func limitsText(limits wasmDecoder.Limits) string {
+	if limits.HasMax {
+		return fmt.Sprintf("%v %v", limits.Min, limits.Max)
+	}
+
+	return fmt.Sprint(limits.Min)
+}
+
+func globalTypeText(globalType wasmDecoder.GlobalType) string {
+	if globalType.Mutable {
+		return "(mut " + wasmDecoder.ValueTypeNames[globalType.ValueType] + ")"
+	}
+
+	return wasmDecoder.ValueTypeNames[globalType.ValueType]
+}
+
+// Strings are printed with the printable characters as they are, and the other bytes as hexadecimal escapes
+func quoteString(bytes []byte) string {
+	output := "\""
+	for i := 0; i < len(bytes); i++ {
+		if bytes[i] < 0x20 || bytes[i] >= 0x7f || bytes[i] == '"' || bytes[i] == '\\' {
+			output += fmt.Sprintf("\\%02x", bytes[i])
+			continue
+		}
+
+		output += string(bytes[i])
+	}
+
+	return output + "\""
+}
+
+// Names become identifiers by replacing the characters identifiers can not have. Names that are used more than once
+// get the index after them, so every identifier is unique
+func getIdentifiers(names map[int]string) map[int]string {
+	indexes := make([]int, 0)
+	for index := range names {
+		indexes = append(indexes, index)
+	}
+
+	sort.Ints(indexes)
+
+	ids := make(map[int]string)
+	used := make(map[string]bool)
+	for i := 0; i < len(indexes); i++ {
+		id := "$" + toIdentifier(names[indexes[i]])
+		if used[id] {
+			id = fmt.Sprintf("%s_%v", id, indexes[i])
+		}
+
+		used[id] = true
+		ids[indexes[i]] = id
+	}
+
+	return ids
+}
+
+func toIdentifier(name string) string {
+	output := ""
+	for i := 0; i < len(name); i++ {
+		if isIdentifierCharacter(name[i]) {
+			output += string(name[i])
+			continue
+		}
+
+		if !strings.HasSuffix(output, "_") {
+			output += "_"
+		}
+	}
+
+	if output == "" {
+		return "_"
+	}
+
+	return output
+}
+
+func isIdentifierCharacter(character byte) bool {
+	if character >= '0' && character <= '9' || character >= 'a' && character <= 'z' || character >= 'A' && character <= 'Z' {
+		return true
+	}
+
+	return strings.IndexByte("!#$%&'*+-./:<=>?@\\^_`|~", character) != -1
+}
@@ -0,0 +1,508 @@
+package wasmValidator
+
+import (
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
+	"fmt"
+	"strings"
+)
+
+//Function bodies are validated by following the types on the value stack, like in the validation algorithm in the
+//appendix of the WebAssembly specification. Every block, loop and if pushes a control frame with the types it takes
+//and gives, and every instruction takes its parameters from the stack and puts its results on it
+
+// The type of a value from an unreachable part of the code, which can be used as any type
+const unknownType uint8 = 0
+
+type controlFrame struct {
+	opcode      uint8
+	startTypes  []uint8
+	endTypes    []uint8
+	height      int
+	unreachable bool
+}
+
+type functionValidator struct {
+	module   *module
+	locals   []uint8
+	results  []uint8
+	values   []uint8
+	controls []controlFrame
+}
+
+func (m *module) validateFunctionBody(functionIndex int, body wasmDecoder.FunctionBody) error {
+	functionType := m.Types[m.functions[functionIndex]]
+	v := &functionValidator{module: m, locals: append([]uint8{}, functionType.Parameters...), results: functionType.Results}
+
+	for i := 0; i < len(body.Locals); i++ {
+		if len(v.locals)+int(body.Locals[i].Count) > maxLocals {
+			return fmt.Errorf("function has more than %v locals", maxLocals)
+		}
+
+		for j := 0; j < int(body.Locals[i].Count); j++ {
+			v.locals = append(v.locals, body.Locals[i].ValueType)
+		}
+	}
+
+	//The function body is a block which ends with the last end instruction
+	v.pushControl(code.BLOCK, []uint8{}, functionType.Results)
+	for i := 0; i < len(body.Instructions); i++ {
+		err := v.validateInstruction(body.Instructions[i])
+		if err != nil {
+			return fmt.Errorf("%s at byte %v of the body: %s", instructionName(body.Instructions[i]), body.Instructions[i].Offset, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (v *functionValidator) validateInstruction(instruction wasmDecoder.Instruction) error {
+	opcode := instruction.Opcode
+	if instructionType, isNumeric := wasmDecoder.NumericInstructionTypes[opcode]; isNumeric {
+		return v.applyType(instructionType)
+	}
+
+	if memoryInstruction, isMemory := memoryInstructions[opcode]; isMemory {
+		return v.validateMemoryInstruction(memoryInstruction, instruction)
+	}
+
+	switch opcode {
+	case code.UNREACHABLE:
+		v.setUnreachable()
+	case code.NOP:
+	case code.BLOCK, code.LOOP, code.IF:
+		blockType, err := v.getBlockType(instruction.BlockType)
+		if err != nil {
+			return err
+		}
+
+		if opcode == code.IF {
+			err = v.popExpected(code.I32)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = v.popValues(blockType.Parameters)
+		if err != nil {
+			return err
+		}
+
+		v.pushControl(opcode, blockType.Parameters, blockType.Results)
+	case code.ELSE:
+		frame, err := v.popControl()
+		if err != nil {
+			return err
+		}
+
+		if frame.opcode != code.IF {
+			return fmt.Errorf("else without if")
+		}
+
+		v.pushControl(code.ELSE, frame.startTypes, frame.endTypes)
+	case code.END:
+		frame, err := v.popControl()
+		if err != nil {
+			return err
+		}
+
+		if frame.opcode == code.IF && !typesEqual(frame.startTypes, frame.endTypes) {
+			return fmt.Errorf("if without else must give the types it takes, %s, but gives %s", typesString(frame.startTypes), typesString(frame.endTypes))
+		}
+
+		v.pushValues(frame.endTypes)
+	case code.BR, code.BR_IF:
+		labelTypes, err := v.getLabelTypes(instruction.Index)
+		if err != nil {
+			return err
+		}
+
+		if opcode == code.BR_IF {
+			err = v.popExpected(code.I32)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = v.popValues(labelTypes)
+		if err != nil {
+			return err
+		}
+
+		if opcode == code.BR {
+			v.setUnreachable()
+		} else {
+			v.pushValues(labelTypes)
+		}
+	case code.BR_TABLE:
+		return v.validateBranchTable(instruction.Labels)
+	case code.RETURN:
+		err := v.popValues(v.results)
+		if err != nil {
+			return err
+		}
+
+		v.setUnreachable()
+	case code.CALL:
+		if int(instruction.Index) >= len(v.module.functions) {
+			return fmt.Errorf("function %v does not exist, there are %v functions", instruction.Index, len(v.module.functions))
+		}
+
+		calledType := v.module.Types[v.module.functions[instruction.Index]]
+		err := v.applyType(calledType)
+		if err != nil {
+			return fmt.Errorf("calling %s: %s", v.module.functionName(int(instruction.Index)), err.Error())
+		}
+	case code.CALL_INDIRECT:
+		return v.validateCallIndirect(instruction)
+	case code.DROP:
+		_, err := v.popValue()
+		return err
+	case code.SELECT:
+		return v.validateSelect()
+	case code.LOCAL_GET, code.LOCAL_SET, code.LOCAL_TEE:
+		return v.validateLocalInstruction(instruction)
+	case code.GLOBAL_GET, code.GLOBAL_SET:
+		return v.validateGlobalInstruction(instruction)
+	case code.MEMORY_SIZE, code.MEMORY_GROW:
+		err := v.validateMemoryIndex(instruction.Index)
+		if err != nil {
+			return err
+		}
+
+		if opcode == code.MEMORY_SIZE {
+			v.pushValue(code.I32)
+			return nil
+		}
+
+		return v.applyType(convert(code.I32, code.I32))
+	case code.I32_CONST:
+		v.pushValue(code.I32)
+	case code.I64_CONST:
+		v.pushValue(code.I64)
+	case code.F32_CONST:
+		v.pushValue(code.F32)
+	case code.F64_CONST:
+		v.pushValue(code.F64)
+	case code.MISC_PREFIX:
+		return v.applyType(wasmDecoder.MiscInstructionTypes[uint8(instruction.MiscOpcode)])
+	default:
+		return fmt.Errorf("unknown instruction")
+	}
+
+	return nil
+}
+
+func (v *functionValidator) pushValue(valueType uint8) {
+	v.values = append(v.values, valueType)
+}
+
+func (v *functionValidator) pushValues(valueTypes []uint8) {
+	v.values = append(v.values, valueTypes...)
+}
+
+// Values can not be taken from the stack below the height of the current block. In unreachable code the stack below
+// the height has values of any type
+func (v *functionValidator) popValue() (uint8, error) {
+	frame := v.controls[len(v.controls)-1]
+	if len(v.values) == frame.height {
+		if frame.unreachable {
+			return unknownType, nil
+		}
+
+		return 0, fmt.Errorf("expected a value on the stack, but the stack of the block is empty")
+	}
+
+	value := v.values[len(v.values)-1]
+	v.values = v.values[:len(v.values)-1]
+	return value, nil
+}
+
+func (v *functionValidator) popExpected(expected uint8) error {
+	actual, err := v.popValue()
+	if err != nil {
+		return fmt.Errorf("expected %s: %s", valueTypeName(expected), err.Error())
+	}
+
+	if actual != expected && actual != unknownType && expected != unknownType {
+		return fmt.Errorf("expected %s on the stack, got %s", valueTypeName(expected), valueTypeName(actual))
+	}
+
+	return nil
+}
+
+// The last type is on the top of the stack so the values are taken in reverse order
+func (v *functionValidator) popValues(expected []uint8) error {
+	for i := len(expected) - 1; i >= 0; i-- {
+		err := v.popExpected(expected[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *functionValidator) applyType(instruction wasmDecoder.FunctionType) error {
+	err := v.popValues(instruction.Parameters)
+	if err != nil {
+		return err
+	}
+
+	v.pushValues(instruction.Results)
+	return nil
+}
+
+func (v *functionValidator) pushControl(opcode uint8, startTypes []uint8, endTypes []uint8) {
+	v.controls = append(v.controls, controlFrame{opcode: opcode, startTypes: startTypes, endTypes: endTypes, height: len(v.values)})
+	v.pushValues(startTypes)
+}
+
+// A block must end with exactly the values it gives on its part of the stack
+func (v *functionValidator) popControl() (controlFrame, error) {
+	if len(v.controls) == 0 {
+		return controlFrame{}, fmt.Errorf("end of block without block")
+	}
+
+	frame := v.controls[len(v.controls)-1]
+	err := v.popValues(frame.endTypes)
+	if err != nil {
+		return controlFrame{}, fmt.Errorf("block must end with %s: %s", typesString(frame.endTypes), err.Error())
+	}
+
+	if len(v.values) != frame.height {
+		return controlFrame{}, fmt.Errorf("block must end with %s, but there are %v more values on the stack", typesString(frame.endTypes), len(v.values)-frame.height)
+	}
+
+	v.controls = v.controls[:len(v.controls)-1]
+	return frame, nil
+}
+
+func (v *functionValidator) setUnreachable() {
+	frame := &v.controls[len(v.controls)-1]
+	v.values = v.values[:frame.height]
+	frame.unreachable = true
+}
+
+// A branch to a loop goes to the start of the loop, so it takes the types the loop takes. Other branches go to the
+// end of the block and take the types the block gives
+func (v *functionValidator) labelTypes(frame controlFrame) []uint8 {
+	if frame.opcode == code.LOOP {
+		return frame.startTypes
+	}
+
+	return frame.endTypes
+}
+
+func (v *functionValidator) getLabelTypes(label uint32) ([]uint8, error) {
+	if int(label) >= len(v.controls) {
+		return []uint8{}, fmt.Errorf("label %v does not exist, there are %v blocks", label, len(v.controls))
+	}
+
+	return v.labelTypes(v.controls[len(v.controls)-1-int(label)]), nil
+}
+
+func (v *functionValidator) getBlockType(blockType wasmDecoder.BlockType) (wasmDecoder.FunctionType, error) {
+	if blockType.TypeIndex == -1 {
+		return wasmDecoder.FunctionType{Parameters: []uint8{}, Results: blockType.Results}, nil
+	}
+
+	if blockType.TypeIndex >= int64(len(v.module.Types)) {
+		return wasmDecoder.FunctionType{}, fmt.Errorf("block type %v does not exist, there are %v types", blockType.TypeIndex, len(v.module.Types))
+	}
+
+	return v.module.Types[blockType.TypeIndex], nil
+}
+
+// The last label is the default label
+func (v *functionValidator) validateBranchTable(labels []uint32) error {
+	labelTypes := make([][]uint8, 0)
+	for i := 0; i < len(labels); i++ {
+		types, err := v.getLabelTypes(labels[i])
+		if err != nil {
+			return err
+		}
+
+		labelTypes = append(labelTypes, types)
+	}
+
+	err := v.popExpected(code.I32)
+	if err != nil {
+		return err
+	}
+
+	defaultTypes := labelTypes[len(labelTypes)-1]
+	for i := 0; i < len(labelTypes)-1; i++ {
+		if len(labelTypes[i]) != len(defaultTypes) {
+			return fmt.Errorf("label %v takes %s, but the default label takes %s", labels[i], typesString(labelTypes[i]), typesString(defaultTypes))
+		}
+
+		//The values are checked for every label and then put back for the next label
+		values := append([]uint8{}, v.values...)
+		err = v.popValues(labelTypes[i])
+		if err != nil {
+			return err
+		}
+
+		v.values = values
+	}
+
+	err = v.popValues(defaultTypes)
+	if err != nil {
+		return err
+	}
+
+	v.setUnreachable()
+	return nil
+}
+
+func (v *functionValidator) validateCallIndirect(instruction wasmDecoder.Instruction) error {
+	if int(instruction.Table) >= v.module.numTables {
+		return fmt.Errorf("table %v does not exist, there are %v tables", instruction.Table, v.module.numTables)
+	}
+
+	if int(instruction.Index) >= len(v.module.Types) {
+		return fmt.Errorf("type %v does not exist, there are %v types", instruction.Index, len(v.module.Types))
+	}
+
+	err := v.popExpected(code.I32)
+	if err != nil {
+		return fmt.Errorf("table index: %s", err.Error())
+	}
+
+	calledType := v.module.Types[instruction.Index]
+	return v.applyType(calledType)
+}
+
+// Select takes two values of the same type and a condition
+func (v *functionValidator) validateSelect() error {
+	err := v.popExpected(code.I32)
+	if err != nil {
+		return err
+	}
+
+	first, err := v.popValue()
+	if err != nil {
+		return err
+	}
+
+	second, err := v.popValue()
+	if err != nil {
+		return err
+	}
+
+	if first != second && first != unknownType && second != unknownType {
+		return fmt.Errorf("values have different types, %s and %s", valueTypeName(second), valueTypeName(first))
+	}
+
+	if first == unknownType {
+		v.pushValue(second)
+	} else {
+		v.pushValue(first)
+	}
+
+	return nil
+}
+
+func (v *functionValidator) validateLocalInstruction(instruction wasmDecoder.Instruction) error {
+	if int(instruction.Index) >= len(v.locals) {
+		return fmt.Errorf("local %v does not exist, there are %v locals", instruction.Index, len(v.locals))
+	}
+
+	localType := v.locals[instruction.Index]
+	switch instruction.Opcode {
+	case code.LOCAL_GET:
+		v.pushValue(localType)
+		return nil
+	case code.LOCAL_SET:
+		return v.popExpected(localType)
+	default:
+		return v.applyType(convert(localType, localType))
+	}
+}
+
+func (v *functionValidator) validateGlobalInstruction(instruction wasmDecoder.Instruction) error {
+	if int(instruction.Index) >= len(v.module.globals) {
+		return fmt.Errorf("global %v does not exist, there are %v globals", instruction.Index, len(v.module.globals))
+	}
+
+	usedGlobal := v.module.globals[instruction.Index]
+	if instruction.Opcode == code.GLOBAL_GET {
+		v.pushValue(usedGlobal.ValueType)
+		return nil
+	}
+
+	if !usedGlobal.Mutable {
+		return fmt.Errorf("global %v is immutable", instruction.Index)
+	}
+
+	return v.popExpected(usedGlobal.ValueType)
+}
+
+func (v *functionValidator) validateMemoryInstruction(memory memoryInstruction, instruction wasmDecoder.Instruction) error {
+	err := v.validateMemoryIndex(0)
+	if err != nil {
+		return err
+	}
+
+	if instruction.Align > memory.maxAlignment {
+		return fmt.Errorf("alignment 2^%v is larger than the %v bytes used", instruction.Align, 1<<memory.maxAlignment)
+	}
+
+	if memory.isStore {
+		return v.applyType(wasmDecoder.FunctionType{Parameters: []uint8{code.I32, memory.valueType}, Results: []uint8{}})
+	}
+
+	return v.applyType(convert(code.I32, memory.valueType))
+}
+
+func (v *functionValidator) validateMemoryIndex(memoryIndex uint32) error {
+	if int(memoryIndex) >= v.module.numMemories {
+		return fmt.Errorf("memory %v does not exist, there are %v memories", memoryIndex, v.module.numMemories)
+	}
+
+	return nil
+}
+
+func typesEqual(a []uint8, b []uint8) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func valueTypeName(valueType uint8) string {
+	if valueType == unknownType {
+		return "any type"
+	}
+
+	return wasmDecoder.ValueTypeNames[valueType]
+}
+
+func typesString(valueTypes []uint8) string {
+	if len(valueTypes) == 0 {
+		return "no values"
+	}
+
+	names := make([]string, 0)
+	for i := 0; i < len(valueTypes); i++ {
+		names = append(names, valueTypeName(valueTypes[i]))
+	}
+
+	return "[" + strings.Join(names, " ") + "]"
+}
+
+func instructionName(instruction wasmDecoder.Instruction) string {
+	if instruction.Opcode == code.MISC_PREFIX {
+		return code.MiscInstructionNames[uint8(instruction.MiscOpcode)]
+	}
+
+	return code.InstructionNames[instruction.Opcode]
+}
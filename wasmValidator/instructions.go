@@ -0,0 +1,44 @@
+package wasmValidator
+
+import (
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
+)
+
+// A load or store with the type of the value and the log2 of the number of bytes it uses, which is the largest
+// alignment it can have
+type memoryInstruction struct {
+	valueType    uint8
+	maxAlignment uint32
+	isStore      bool
+}
+
+var memoryInstructions = map[uint8]memoryInstruction{
+	code.I32_LOAD:     {code.I32, 2, false},
+	code.I64_LOAD:     {code.I64, 3, false},
+	code.F32_LOAD:     {code.F32, 2, false},
+	code.F64_LOAD:     {code.F64, 3, false},
+	code.I32_LOAD8_S:  {code.I32, 0, false},
+	code.I32_LOAD8_U:  {code.I32, 0, false},
+	code.I32_LOAD16_S: {code.I32, 1, false},
+	code.I32_LOAD16_U: {code.I32, 1, false},
+	code.I64_LOAD8_S:  {code.I64, 0, false},
+	code.I64_LOAD8_U:  {code.I64, 0, false},
+	code.I64_LOAD16_S: {code.I64, 1, false},
+	code.I64_LOAD16_U: {code.I64, 1, false},
+	code.I64_LOAD32_S: {code.I64, 2, false},
+	code.I64_LOAD32_U: {code.I64, 2, false},
+	code.I32_STORE:    {code.I32, 2, true},
+	code.I64_STORE:    {code.I64, 3, true},
+	code.F32_STORE:    {code.F32, 2, true},
+	code.F64_STORE:    {code.F64, 3, true},
+	code.I32_STORE8:   {code.I32, 0, true},
+	code.I32_STORE16:  {code.I32, 1, true},
+	code.I64_STORE8:   {code.I64, 0, true},
+	code.I64_STORE16:  {code.I64, 1, true},
+	code.I64_STORE32:  {code.I64, 2, true},
+}
+
+func convert(from uint8, to uint8) wasmDecoder.FunctionType {
+	return wasmDecoder.FunctionType{Parameters: []uint8{from}, Results: []uint8{to}}
+}
@@ -0,0 +1,329 @@
+package wasmValidator
+
+import (
+	"compiler/wasmCompiler/code"
+	"compiler/wasmDecoder"
+	"fmt"
+)
+
+//The validator checks a compiled module with the validation algorithm from the WebAssembly specification, so errors
+//in the compiler are found before the module is given to a wasm engine. The decoder checks the section order and
+//sizes, and the validator checks the indexes used in every section and the types of the values on the stack in every
+//function body
+
+const maxPages = 65536
+const maxLocals = 50000
+
+type module struct {
+	*wasmDecoder.Module
+	functions     []uint32 //The type index of every function, imported functions first
+	globals       []wasmDecoder.GlobalType
+	numTables     int
+	numMemories   int
+	functionNames map[int]string
+}
+
+// Validates the module. The function names are used in the errors and can be missing for some or all functions
+func Validate(moduleBytes []byte, functionNames map[int]string) error {
+	decoded, err := wasmDecoder.Decode(moduleBytes)
+	if err != nil {
+		return err
+	}
+
+	m := &module{Module: decoded, functionNames: functionNames}
+
+	validations := []struct {
+		section  uint8
+		validate func() error
+	}{
+		{code.SECTION_IMPORT, m.validateImports},
+		{code.SECTION_FUNCTION, m.validateFunctions},
+		{code.SECTION_TABLE, m.validateTables},
+		{code.SECTION_MEMORY, m.validateMemories},
+		{code.SECTION_GLOBAL, m.validateGlobals},
+		{code.SECTION_EXPORT, m.validateExports},
+		{code.SECTION_START, m.validateStart},
+		{code.SECTION_ELEMENT, m.validateElements},
+		{code.SECTION_CODE, m.validateCode},
+		{code.SECTION_DATA, m.validateData},
+	}
+
+	for i := 0; i < len(validations); i++ {
+		err = validations[i].validate()
+		if err != nil {
+			return fmt.Errorf("invalid %s section: %s", wasmDecoder.SectionNames[validations[i].section], err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (m *module) validateImports() error {
+	for i := 0; i < len(m.Imports); i++ {
+		imported := m.Imports[i]
+
+		switch imported.Kind {
+		case code.DESC_FUNCTION:
+			if int(imported.TypeIndex) >= len(m.Types) {
+				return fmt.Errorf("import %s.%s uses type %v, but there are %v types", imported.Module, imported.Name, imported.TypeIndex, len(m.Types))
+			}
+
+			m.functions = append(m.functions, imported.TypeIndex)
+		case code.DESC_TABLE:
+			err := m.validateTable(imported.Table)
+			if err != nil {
+				return err
+			}
+		case code.DESC_MEMORY:
+			err := m.validateMemory(imported.Memory)
+			if err != nil {
+				return err
+			}
+		case code.DESC_GLOBAL:
+			m.globals = append(m.globals, imported.Global)
+		}
+	}
+
+	return nil
+}
+
+func (m *module) validateFunctions() error {
+	for i := 0; i < len(m.Functions); i++ {
+		if int(m.Functions[i]) >= len(m.Types) {
+			return fmt.Errorf("%s uses type %v, but there are %v types", m.functionName(len(m.functions)), m.Functions[i], len(m.Types))
+		}
+
+		m.functions = append(m.functions, m.Functions[i])
+	}
+
+	return nil
+}
+
+func (m *module) validateTables() error {
+	for i := 0; i < len(m.Tables); i++ {
+		err := m.validateTable(m.Tables[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *module) validateTable(table wasmDecoder.Table) error {
+	if table.ElementType != code.ANYFUNC {
+		return fmt.Errorf("table element type is 0x%x, expected 0x%x", table.ElementType, code.ANYFUNC)
+	}
+
+	err := validateLimits(table.Limits, ^uint32(0))
+	if err != nil {
+		return fmt.Errorf("table %v: %s", m.numTables, err.Error())
+	}
+
+	m.numTables++
+	if m.numTables > 1 {
+		return fmt.Errorf("there is more than one table")
+	}
+
+	return nil
+}
+
+func (m *module) validateMemories() error {
+	for i := 0; i < len(m.Memories); i++ {
+		err := m.validateMemory(m.Memories[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *module) validateMemory(limits wasmDecoder.Limits) error {
+	err := validateLimits(limits, maxPages)
+	if err != nil {
+		return fmt.Errorf("memory %v: %s", m.numMemories, err.Error())
+	}
+
+	m.numMemories++
+	if m.numMemories > 1 {
+		return fmt.Errorf("there is more than one memory")
+	}
+
+	return nil
+}
+
+// The max must not be less than the min, and both must not be more than the given maximum
+func validateLimits(limits wasmDecoder.Limits, maximum uint32) error {
+	if limits.Min > maximum {
+		return fmt.Errorf("limits minimum %v is more than %v", limits.Min, maximum)
+	}
+
+	if !limits.HasMax {
+		return nil
+	}
+
+	if limits.Max > maximum {
+		return fmt.Errorf("limits maximum %v is more than %v", limits.Max, maximum)
+	}
+
+	if limits.Max < limits.Min {
+		return fmt.Errorf("limits maximum %v is less than the minimum %v", limits.Max, limits.Min)
+	}
+
+	return nil
+}
+
+func (m *module) validateGlobals() error {
+	for i := 0; i < len(m.Globals); i++ {
+		err := m.validateConstantExpression(m.Globals[i].Init, m.Globals[i].Type.ValueType)
+		if err != nil {
+			return fmt.Errorf("initial value of global %v: %s", len(m.globals), err.Error())
+		}
+
+		m.globals = append(m.globals, m.Globals[i].Type)
+	}
+
+	return nil
+}
+
+// Constant expressions give the initial values of globals and the offsets of segments. They are one const instruction
+// or a get of an imported immutable global, followed by the end instruction
+func (m *module) validateConstantExpression(expression []wasmDecoder.Instruction, expectedType uint8) error {
+	if len(expression) != 2 {
+		return fmt.Errorf("constant expression must have one instruction, it has %v", len(expression)-1)
+	}
+
+	constantTypes := map[uint8]uint8{
+		code.I32_CONST: code.I32,
+		code.I64_CONST: code.I64,
+		code.F32_CONST: code.F32,
+		code.F64_CONST: code.F64,
+	}
+
+	instruction := expression[0]
+	valueType, isConstant := constantTypes[instruction.Opcode]
+	if instruction.Opcode == code.GLOBAL_GET {
+		if int(instruction.Index) >= m.NumImportedGlobals() {
+			return fmt.Errorf("constant expression uses global %v, but only the %v imported globals can be used", instruction.Index, m.NumImportedGlobals())
+		}
+
+		if m.globals[instruction.Index].Mutable {
+			return fmt.Errorf("constant expression uses mutable global %v", instruction.Index)
+		}
+
+		valueType = m.globals[instruction.Index].ValueType
+	} else if !isConstant {
+		return fmt.Errorf("%s is not allowed in a constant expression", instructionName(instruction))
+	}
+
+	if valueType != expectedType {
+		return fmt.Errorf("constant expression gives %s, expected %s", valueTypeName(valueType), valueTypeName(expectedType))
+	}
+
+	return nil
+}
+
+func (m *module) validateExports() error {
+	names := make(map[string]bool)
+	numDefinitions := map[uint8]int{
+		code.DESC_FUNCTION: len(m.functions),
+		code.DESC_TABLE:    m.numTables,
+		code.DESC_MEMORY:   m.numMemories,
+		code.DESC_GLOBAL:   len(m.globals),
+	}
+
+	for i := 0; i < len(m.Exports); i++ {
+		export := m.Exports[i]
+		if names[export.Name] {
+			return fmt.Errorf("name %s is exported more than once", export.Name)
+		}
+
+		names[export.Name] = true
+
+		if int(export.Index) >= numDefinitions[export.Kind] {
+			return fmt.Errorf("export %s has %s index %v, but there are %v", export.Name, wasmDecoder.ExportKindNames[export.Kind], export.Index, numDefinitions[export.Kind])
+		}
+	}
+
+	return nil
+}
+
+func (m *module) validateStart() error {
+	if !m.HasStart {
+		return nil
+	}
+
+	if int(m.Start) >= len(m.functions) {
+		return fmt.Errorf("start function %v does not exist, there are %v functions", m.Start, len(m.functions))
+	}
+
+	startType := m.Types[m.functions[m.Start]]
+	if len(startType.Parameters) != 0 || len(startType.Results) != 0 {
+		return fmt.Errorf("start %s must have no parameters and no results", m.functionName(int(m.Start)))
+	}
+
+	return nil
+}
+
+func (m *module) validateElements() error {
+	for i := 0; i < len(m.Elements); i++ {
+		element := m.Elements[i]
+		if int(element.TableIndex) >= m.numTables {
+			return fmt.Errorf("element segment %v uses table %v, but there are %v tables", i, element.TableIndex, m.numTables)
+		}
+
+		err := m.validateConstantExpression(element.Offset, code.I32)
+		if err != nil {
+			return fmt.Errorf("offset of element segment %v: %s", i, err.Error())
+		}
+
+		for j := 0; j < len(element.Functions); j++ {
+			if int(element.Functions[j]) >= len(m.functions) {
+				return fmt.Errorf("element segment %v uses function %v, but there are %v functions", i, element.Functions[j], len(m.functions))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *module) validateCode() error {
+	for i := 0; i < len(m.Code); i++ {
+		functionIndex := m.NumImportedFunctions() + i
+		err := m.validateFunctionBody(functionIndex, m.Code[i])
+		if err != nil {
+			return fmt.Errorf("%s (index %v, body at byte %v): %s", m.functionName(functionIndex), functionIndex, m.Code[i].Offset, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (m *module) validateData() error {
+	for i := 0; i < len(m.Data); i++ {
+		segment := m.Data[i]
+		if segment.Passive {
+			continue
+		}
+
+		if int(segment.MemoryIndex) >= m.numMemories {
+			return fmt.Errorf("data segment %v uses memory %v, but there are %v memories", i, segment.MemoryIndex, m.numMemories)
+		}
+
+		err := m.validateConstantExpression(segment.Offset, code.I32)
+		if err != nil {
+			return fmt.Errorf("offset of data segment %v: %s", i, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (m *module) functionName(functionIndex int) string {
+	if name, hasName := m.functionNames[functionIndex]; hasName {
+		return "function " + name
+	}
+
+	return fmt.Sprintf("function %v", functionIndex)
+}
@@ -0,0 +1,138 @@
+package wasmValidator
+
+import (
+	"compiler/leb128"
+	"compiler/wasmCompiler/code"
+	"strings"
+	"testing"
+)
+
+func section(id uint8, content ...byte) []byte {
+	return append(append([]byte{id}, leb128.Int32ToULEB128(int32(len(content)))...), content...)
+}
+
+func moduleBytes(sections ...[]byte) []byte {
+	bytes := append(append([]byte{}, code.MagicModuleHeader...), code.ModuleVersion...)
+	for _, section := range sections {
+		bytes = append(bytes, section...)
+	}
+
+	return bytes
+}
+
+// The types of the test modules are 0: () -> (i32), 1: () -> () and 2: (i32) -> (i32)
+var typeSection = section(code.SECTION_TYPE, 3,
+	code.FUNC, 0, 1, code.I32,
+	code.FUNC, 0, 0,
+	code.FUNC, 1, code.I32, 1, code.I32)
+
+// A module with one function of the given type, with the given locals declarations and instructions. The sections are
+// added between the function section and the code section
+func functionModule(typeIndex uint8, locals []byte, instructions []byte, sections ...[]byte) []byte {
+	body := append(append([]byte{}, locals...), instructions...)
+	codeSection := section(code.SECTION_CODE, append(append([]byte{1}, leb128.Int32ToULEB128(int32(len(body)))...), body...)...)
+
+	moduleSections := [][]byte{typeSection, section(code.SECTION_FUNCTION, 1, typeIndex)}
+	moduleSections = append(moduleSections, sections...)
+	return moduleBytes(append(moduleSections, codeSection)...)
+}
+
+var noLocals = []byte{0}
+var memorySection = section(code.SECTION_MEMORY, 1, code.LIMIT_MIN, 1)
+
+func TestValidateFunctionBodies(t *testing.T) {
+	tests := []struct {
+		name         string
+		typeIndex    uint8
+		locals       []byte
+		instructions []byte
+		sections     [][]byte
+		err          string
+	}{
+		{"valid", 0, noLocals, []byte{code.I32_CONST, 1, code.END}, nil, ""},
+		{"stack underflow", 0, noLocals, []byte{code.I32_CONST, 1, code.I32_ADD, code.END}, nil, "i32.add at byte 3 of the body: expected i32: expected a value on the stack, but the stack of the block is empty"},
+		{"missing result", 0, noLocals, []byte{code.END}, nil, "block must end with [i32]"},
+		{"extra values", 1, noLocals, []byte{code.I32_CONST, 1, code.END}, nil, "there are 1 more values on the stack"},
+		{"extra values in a block", 0, noLocals, []byte{code.BLOCK, code.EMPTY, code.I32_CONST, 1, code.END, code.I32_CONST, 2, code.END}, nil, "there are 1 more values on the stack"},
+		{"wrong type", 0, noLocals, []byte{code.I64_CONST, 1, code.END}, nil, "expected i32 on the stack, got i64"},
+		{"values outside the block", 0, noLocals, []byte{code.I32_CONST, 1, code.BLOCK, code.I32, code.DROP, code.END, code.END}, nil, "the stack of the block is empty"},
+		{"br to the function", 0, noLocals, []byte{code.I32_CONST, 1, code.BR, 0, code.END}, nil, ""},
+		{"br depth", 1, noLocals, []byte{code.BR, 1, code.END}, nil, "br at byte 1 of the body: label 1 does not exist, there are 1 blocks"},
+		{"br_if depth", 1, noLocals, []byte{code.BLOCK, code.EMPTY, code.I32_CONST, 0, code.BR_IF, 2, code.END, code.END}, nil, "label 2 does not exist, there are 2 blocks"},
+		{"br without the label values", 0, noLocals, []byte{code.BR, 0, code.END}, nil, "expected i32"},
+		{"unreachable values", 0, noLocals, []byte{code.UNREACHABLE, code.I32_ADD, code.END}, nil, ""},
+		{"values after unreachable", 0, noLocals, []byte{code.UNREACHABLE, code.I64_CONST, 1, code.END}, nil, "expected i32 on the stack, got i64"},
+		{"br_table", 1, noLocals, []byte{code.BLOCK, code.EMPTY, code.I32_CONST, 0, code.BR_TABLE, 1, 0, 1, code.END, code.END}, nil, ""},
+		{"br_table arity", 1, noLocals, []byte{code.BLOCK, code.EMPTY, code.BLOCK, code.I32, code.I32_CONST, 0, code.I32_CONST, 0, code.BR_TABLE, 1, 0, 1, code.END, code.DROP, code.END, code.END}, nil, "label 0 takes [i32], but the default label takes no values"},
+		{"br_table depth", 1, noLocals, []byte{code.I32_CONST, 0, code.BR_TABLE, 1, 0, 3, code.END}, nil, "label 3 does not exist, there are 1 blocks"},
+		{"if with else", 0, noLocals, []byte{code.I32_CONST, 1, code.IF, code.I32, code.I32_CONST, 2, code.ELSE, code.I32_CONST, 3, code.END, code.END}, nil, ""},
+		{"if without else", 0, noLocals, []byte{code.I32_CONST, 1, code.IF, code.I32, code.I32_CONST, 2, code.END, code.END}, nil, "if without else must give the types it takes, no values, but gives [i32]"},
+		{"if without condition", 1, noLocals, []byte{code.IF, code.EMPTY, code.END, code.END}, nil, "expected i32"},
+		{"else without if", 1, noLocals, []byte{code.BLOCK, code.EMPTY, code.ELSE, code.END, code.END}, nil, "else without if"},
+		{"block type index", 1, noLocals, []byte{code.BLOCK, 3, code.END, code.END}, nil, "block type 3 does not exist, there are 3 types"},
+		{"local of an argument", 2, noLocals, []byte{code.LOCAL_GET, 0, code.END}, nil, ""},
+		{"local index", 2, noLocals, []byte{code.LOCAL_GET, 1, code.END}, nil, "local 1 does not exist, there are 1 locals"},
+		{"declared local", 2, []byte{1, 2, code.I64}, []byte{code.LOCAL_GET, 2, code.END}, nil, "expected i32 on the stack, got i64"},
+		{"declared local index", 2, []byte{1, 2, code.I64}, []byte{code.LOCAL_GET, 3, code.END}, nil, "local 3 does not exist, there are 3 locals"},
+		{"call", 0, noLocals, []byte{code.CALL, 0, code.END}, nil, ""},
+		{"call index", 1, noLocals, []byte{code.CALL, 1, code.END}, nil, "function 1 does not exist, there are 1 functions"},
+		{"call arguments", 2, noLocals, []byte{code.CALL, 0, code.END}, nil, "calling function 0: expected i32"},
+		{"global index", 0, noLocals, []byte{code.GLOBAL_GET, 0, code.END}, nil, "global 0 does not exist, there are 0 globals"},
+		{"immutable global", 1, noLocals, []byte{code.I32_CONST, 1, code.GLOBAL_SET, 0, code.END}, [][]byte{section(code.SECTION_GLOBAL, 1, code.I32, code.IMMUTABLE, code.I32_CONST, 0, code.END)}, "global 0 is immutable"},
+		{"load", 0, noLocals, []byte{code.I32_CONST, 0, code.I32_LOAD, 2, 0, code.END}, [][]byte{memorySection}, ""},
+		{"load without memory", 0, noLocals, []byte{code.I32_CONST, 0, code.I32_LOAD, 2, 0, code.END}, nil, "memory 0 does not exist, there are 0 memories"},
+		{"memory.size without memory", 0, noLocals, []byte{code.MEMORY_SIZE, 0, code.END}, nil, "memory 0 does not exist, there are 0 memories"},
+		{"load alignment", 0, noLocals, []byte{code.I32_CONST, 0, code.I32_LOAD, 3, 0, code.END}, [][]byte{memorySection}, "alignment 2^3 is larger than the 4 bytes used"},
+		{"call_indirect without table", 0, noLocals, []byte{code.I32_CONST, 0, code.CALL_INDIRECT, 0, 0, code.END}, nil, "table 0 does not exist, there are 0 tables"},
+	}
+
+	for _, test := range tests {
+		err := Validate(functionModule(test.typeIndex, test.locals, test.instructions, test.sections...), map[int]string{})
+		if !errorMatches(err, test.err) {
+			t.Errorf("%s: got error %v, expected %q", test.name, err, test.err)
+		}
+	}
+}
+
+func TestValidateModules(t *testing.T) {
+	body := []byte{code.I32_CONST, 1, code.END}
+	tests := []struct {
+		name  string
+		bytes []byte
+		err   string
+	}{
+		{"function type index", moduleBytes(typeSection, section(code.SECTION_FUNCTION, 1, 3), section(code.SECTION_CODE, 1, 4, 0, code.I32_CONST, 1, code.END)), "function 0 uses type 3, but there are 3 types"},
+		{"import type index", moduleBytes(typeSection, section(code.SECTION_IMPORT, 1, 1, 'm', 1, 'f', code.DESC_FUNCTION, 5)), "import m.f uses type 5, but there are 3 types"},
+		{"two memories", moduleBytes(section(code.SECTION_MEMORY, 2, code.LIMIT_MIN, 1, code.LIMIT_MIN, 1)), "there is more than one memory"},
+		{"memory limits", moduleBytes(section(code.SECTION_MEMORY, 1, code.LIMIT_MIN_MAX, 2, 1)), "limits maximum 1 is less than the minimum 2"},
+		{"global initial value", moduleBytes(section(code.SECTION_GLOBAL, 1, code.I32, code.IMMUTABLE, code.I64_CONST, 0, code.END)), "constant expression gives i64, expected i32"},
+		{"export index", functionModule(0, noLocals, body, section(code.SECTION_EXPORT, 1, 1, 'f', code.DESC_FUNCTION, 1)), "export f has func index 1, but there are 1"},
+		{"duplicate export", functionModule(0, noLocals, body, section(code.SECTION_EXPORT, 2, 1, 'f', code.DESC_FUNCTION, 0, 1, 'f', code.DESC_FUNCTION, 0)), "name f is exported more than once"},
+		{"start type", functionModule(0, noLocals, body, section(code.SECTION_START, 0)), "start function 0 must have no parameters and no results"},
+		{"data without memory", moduleBytes(section(code.SECTION_DATA, 1, 0, code.I32_CONST, 0, code.END, 1, 42)), "data segment 0 uses memory 0, but there are 0 memories"},
+		{"element without table", functionModule(0, noLocals, body, section(code.SECTION_ELEMENT, 1, 0, code.I32_CONST, 0, code.END, 1, 0)), "element segment 0 uses table 0, but there are 0 tables"},
+		{"decoding error", moduleBytes(section(code.SECTION_FUNCTION, 0), typeSection), "type section at byte 11 is out of order"},
+	}
+
+	for _, test := range tests {
+		err := Validate(test.bytes, map[int]string{})
+		if !errorMatches(err, test.err) {
+			t.Errorf("%s: got error %v, expected %q", test.name, err, test.err)
+		}
+	}
+}
+
+func TestFunctionNamesInErrors(t *testing.T) {
+	err := Validate(functionModule(1, noLocals, []byte{code.I32_CONST, 1, code.END}), map[int]string{0: "main"})
+	if !errorMatches(err, "invalid code section: function main (index 0, body at byte") {
+		t.Errorf("got error %v, expected it to name function main", err)
+	}
+}
+
+func errorMatches(err error, expected string) bool {
+	if expected == "" {
+		return err == nil
+	}
+
+	return err != nil && strings.Contains(err.Error(), expected)
+}